@@ -16,10 +16,41 @@ See the License for the specific language governing permissions and
 package harbor
 
 import (
+	"context"
+	"fmt"
 	client2 "github.com/hujianxiong/go-harbor/pkg/client"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
 )
 
-func NewClientSet(host, username, password string) (clientSet *client2.Clientset, err error) {
-	return client2.NewForConfig(rest2.NewDefaultConfig(host, username, password))
+// NewClientSet creates a client set for the Harbor at host authenticated with the username
+// and password, customized by the options, e.g. WithCAFile for a private CA. Whether Harbor
+// serves its API under /api/v2.0 or, for Harbor 1.x, under /api is detected on first use,
+// unless given with WithAPIVersionPath.
+func NewClientSet(host, username, password string, options ...ClientSetOption) (clientSet *client2.Clientset, err error) {
+	config := rest2.NewDefaultConfig(host, username, password)
+	config.APIVersionPath = rest2.AutoAPIVersionPath
+	for _, option := range options {
+		option(config)
+	}
+	return client2.NewForConfig(config)
+}
+
+// NewClientSetAndPing creates a client set like NewClientSet, then pings Harbor and, when a
+// username is given, gets the current user so that an unreachable host or rejected
+// credentials fail fast instead of on the first API call. The checks are aborted once ctx
+// is done.
+func NewClientSetAndPing(ctx context.Context, host, username, password string, options ...ClientSetOption) (clientSet *client2.Clientset, err error) {
+	clientSet, err = NewClientSet(host, username, password, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err = clientSet.System.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("ping harbor %s error: %v", host, err)
+	}
+	if username != "" {
+		if _, err = clientSet.User.Current(ctx); err != nil {
+			return nil, fmt.Errorf("check credentials of %s error: %v", username, err)
+		}
+	}
+	return clientSet, nil
 }
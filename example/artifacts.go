@@ -16,27 +16,28 @@ See the License for the specific language governing permissions and
 package example
 
 import (
+	"context"
 	"fmt"
 	"github.com/hujianxiong/go-harbor"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 )
 
-func Artifact(host, username, password string) error {
+func Artifact(ctx context.Context, host, username, password string) error {
 	clientSet, err := harbor.NewClientSet(host, username, password)
 	if err != nil {
 		return fmt.Errorf("get client set error:%v", err)
 	}
 	query := model.Query{}
-	results, err := clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").List(&query)
+	results, err := clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").List(ctx, &query)
 	if err != nil || len(*results) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 
-	result, err := clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").Get("latest")
+	result, err := clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").Get(ctx, "latest")
 	if err != nil || len(result.Tags) == 0 {
 		return fmt.Errorf("%v", err)
 	}
-	err = clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").Delete("latest")
+	err = clientSet.V2.Repositories("appsmith").Artifacts("appsmith-editor").Delete(ctx, "latest")
 	if err != nil {
 		return fmt.Errorf("%v", err)
 	}
@@ -17,7 +17,7 @@ package example
 
 /*
 func TestArtifact(t *testing.T) {
-	err := Artifact("xxx.xx.xx", "username", "password")
+	err := Artifact(context.Background(), "xxx.xx.xx", "username", "password")
 	if err != nil {
 		t.Errorf("%v", err)
 	}
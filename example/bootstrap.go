@@ -0,0 +1,105 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"github.com/goharbor/harbor/src/common/models"
+	client2 "github.com/hujianxiong/go-harbor/pkg/client"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// Project member roles
+const (
+	RoleProjectAdmin = 1
+	RoleDeveloper    = 2
+	RoleGuest        = 3
+	RoleMaintainer   = 4
+)
+
+// BootstrapOptions describes the project set up by ProjectBootstrap.
+type BootstrapOptions struct {
+	Project string
+	Public  bool
+	// StorageLimit is the storage quota of the project in bytes, -1 for unlimited.
+	StorageLimit int64
+	// Groups maps the OIDC or HTTP groups to add as members to their role.
+	Groups map[string]int
+	// GroupType is the type of Groups, model.UserGroupTypeOIDC by default.
+	GroupType int
+}
+
+// ProjectBootstrap creates a project if it does not exist yet and grants the user groups
+// their role on it, it can be run repeatedly.
+func ProjectBootstrap(ctx context.Context, clientSet *client2.Clientset, opts *BootstrapOptions) error {
+	public := "false"
+	if opts.Public {
+		public = "true"
+	}
+	if _, err := clientSet.V2.Get(ctx, opts.Project); err != nil {
+		err = clientSet.V2.RESTClient().Post().
+			Resource("projects").
+			Body(&models.ProjectRequest{
+				Name:         opts.Project,
+				Metadata:     map[string]string{"public": public},
+				StorageLimit: &opts.StorageLimit,
+			}).
+			Do(ctx).
+			Error()
+		if err != nil {
+			return fmt.Errorf("create project %s error: %v", opts.Project, err)
+		}
+	}
+	project, err := clientSet.V2.Get(ctx, opts.Project)
+	if err != nil {
+		return fmt.Errorf("get project %s error: %v", opts.Project, err)
+	}
+
+	groupType := opts.GroupType
+	if groupType == 0 {
+		groupType = model.UserGroupTypeOIDC
+	}
+	for name, role := range opts.Groups {
+		group, err := clientSet.UserGroup.GetByName(ctx, name, groupType)
+		if err != nil {
+			if err := clientSet.UserGroup.Create(ctx, &model.UserGroup{GroupName: name, GroupType: groupType}); err != nil {
+				return fmt.Errorf("create user group %s error: %v", name, err)
+			}
+			if group, err = clientSet.UserGroup.GetByName(ctx, name, groupType); err != nil {
+				return err
+			}
+		}
+		// members that already exist are rejected with a conflict, which is fine here
+		err = clientSet.V2.RESTClient().Post().
+			Project(opts.Project).
+			Resource("members").
+			Body(&models.MemberReq{
+				ProjectID:   project.ProjectID,
+				Role:        role,
+				MemberGroup: models.UserGroup{ID: int(group.ID)},
+			}).
+			Do(ctx).
+			Error()
+		if err != nil {
+			fmt.Printf("add group %s to project %s: %v\n", name, opts.Project, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/hujianxiong/go-harbor/pkg/client"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/replication"
+	"net/url"
+)
+
+// DRDrillOptions describes a disaster recovery drill between two Harbor instances.
+type DRDrillOptions struct {
+	// Primary is the Harbor the images are pulled from, registered on the DR site.
+	Primary *model.Registry
+	// Project is replicated from the primary into the same project on the DR site.
+	Project string
+	// PolicyName is the name of the replication policy on the DR site.
+	PolicyName string
+}
+
+// ReplicationDRDrill pulls a project from the primary Harbor into the DR Harbor with a
+// replication policy, then checks that every repository of the primary project is
+// available with the same artifacts on the DR site.
+func ReplicationDRDrill(ctx context.Context, primary, dr *client2.Clientset, opts *DRDrillOptions) error {
+	execution, err := replication.Mirror(ctx, dr.Registry, dr.Replication, &replication.MirrorOptions{
+		Registry:     opts.Primary,
+		PolicyName:   opts.PolicyName,
+		Repositories: []string{opts.Project + "/**"},
+		Project:      opts.Project,
+		Override:     true,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("replication execution %d: %d tasks succeeded\n", execution.ID, execution.Succeed)
+
+	repositories, err := primary.V2.Repositories(opts.Project).List(ctx, &model.Query{PageSize: 100})
+	if err != nil {
+		return fmt.Errorf("list repositories of project %s error: %v", opts.Project, err)
+	}
+	for _, repo := range *repositories {
+		name := url.PathEscape(repo.Name[len(opts.Project)+1:])
+		artifacts, err := primary.V2.Repositories(opts.Project).Artifacts(name).List(ctx, &model.Query{PageSize: 100})
+		if err != nil {
+			return err
+		}
+		for _, artifact := range *artifacts {
+			if _, err := dr.V2.Repositories(opts.Project).Artifacts(name).Get(ctx, artifact.Digest); err != nil {
+				return fmt.Errorf("%s@%s is missing on the DR site: %v", repo.Name, artifact.Digest, err)
+			}
+		}
+	}
+	return nil
+}
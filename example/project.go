@@ -16,29 +16,30 @@ See the License for the specific language governing permissions and
 package example
 
 import (
+	"context"
 	"fmt"
 	"github.com/hujianxiong/go-harbor"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 )
 
-func Project(host, username, password string) error {
+func Project(ctx context.Context, host, username, password string) error {
 	clientSet, err := harbor.NewClientSet(host, username, password)
 	if err != nil {
 		return fmt.Errorf("get client set error:%v", err)
 	}
-	result, err := clientSet.V2.Get("15")
+	result, err := clientSet.V2.Get(ctx, "15")
 	if err != nil || len(result.Name) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 	query := model.Query{
 		PageSize: 2,
 	}
-	result1, err := clientSet.V2.List(&query)
+	result1, err := clientSet.V2.List(ctx, &query)
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 
-	err = clientSet.V2.Delete("11")
+	err = clientSet.V2.Delete(ctx, "11")
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
@@ -17,7 +17,7 @@ package example
 
 /*
 func TestProject(t *testing.T) {
-	err := Project("xxx.xx.xx", "username", "password")
+	err := Project(context.Background(), "xxx.xx.xx", "username", "password")
 	if err != nil {
 		t.Errorf("%v", err)
 	}
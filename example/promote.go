@@ -0,0 +1,104 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/hujianxiong/go-harbor/pkg/client"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"net/url"
+	"time"
+)
+
+// severities orders the scan severities from the lowest to the highest.
+var severities = []string{"None", "Unknown", "Negligible", "Low", "Medium", "High", "Critical"}
+
+// PromoteOptions describes an image pushed by CI that is promoted once scanned.
+type PromoteOptions struct {
+	// Project and Image locate the pushed image, e.g. staging and app/api:1.2.0.
+	Project string
+	Image   string
+	// TargetProject is the project the image is copied to when it passes the scan.
+	TargetProject string
+	// MaxSeverity is the highest severity allowed to promote the image, e.g. High.
+	MaxSeverity string
+}
+
+// PushScanPromote is the CI flow after a push: it waits for the image to be visible, scans
+// it, waits for the scan to complete and copies the image to the target project unless it
+// has vulnerabilities above the allowed severity.
+func PushScanPromote(ctx context.Context, clientSet *client2.Clientset, opts *PromoteOptions) error {
+	repository, reference := splitImage(opts.Image)
+	artifacts := clientSet.V2.Repositories(opts.Project).Artifacts(url.PathEscape(repository))
+
+	artifact, err := artifacts.WaitUntilVisible(ctx, reference)
+	if err != nil {
+		return err
+	}
+	if err := artifacts.Scan(ctx, artifact.Digest); err != nil {
+		return fmt.Errorf("scan %s error: %v", opts.Image, err)
+	}
+
+	var overview *model.ScanOverview
+	for overview == nil {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("scan of %s did not complete: %v", opts.Image, ctx.Err())
+		case <-time.After(5 * time.Second):
+		}
+		artifact, err = artifacts.GetWithQuery(ctx, artifact.Digest, &model.ArtifactQuery{WithScanOverview: true})
+		if err != nil {
+			return err
+		}
+		for _, o := range artifact.ScanOverview {
+			if o != nil && o.Done() {
+				overview = o
+			}
+		}
+	}
+	if overview.ScanStatus != model.ScanStatusSuccess {
+		return fmt.Errorf("scan of %s failed", opts.Image)
+	}
+	if severityIndex(overview.Severity) > severityIndex(opts.MaxSeverity) {
+		return fmt.Errorf("%s has %s vulnerabilities, %s at most are allowed", opts.Image, overview.Severity, opts.MaxSeverity)
+	}
+
+	err = clientSet.V2.RESTClient().Post().
+		Project(opts.TargetProject).
+		Resource("repositories").
+		Name(url.PathEscape(repository)).
+		Suffix("artifacts").
+		Params(map[string]string{"from": fmt.Sprintf("%s/%s@%s", opts.Project, repository, artifact.Digest)}).
+		Do(ctx).
+		Error()
+	if err != nil {
+		return fmt.Errorf("copy %s to project %s error: %v", opts.Image, opts.TargetProject, err)
+	}
+	return nil
+}
+
+func severityIndex(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severities)
+}
@@ -16,29 +16,30 @@ See the License for the specific language governing permissions and
 package example
 
 import (
+	"context"
 	"fmt"
 	"github.com/hujianxiong/go-harbor"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 )
 
-func Repositories(host, username, password string) error {
+func Repositories(ctx context.Context, host, username, password string) error {
 	clientSet, err := harbor.NewClientSet(host, username, password)
 	if err != nil {
 		return fmt.Errorf("get client set error:%v", err)
 	}
-	result, err := clientSet.V2.Repositories("bitsensor").Get("elastalert")
+	result, err := clientSet.V2.Repositories("bitsensor").Get(ctx, "elastalert")
 	if err != nil || len(result.Name) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 	query := model.Query{
 		PageSize: 2,
 	}
-	result1, err := clientSet.V2.Repositories("cloudos").List(&query)
+	result1, err := clientSet.V2.Repositories("cloudos").List(ctx, &query)
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 
-	err = clientSet.V2.Repositories("cloudos").Delete("cloudos-next-allinone")
+	err = clientSet.V2.Repositories("cloudos").Delete(ctx, "cloudos-next-allinone")
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
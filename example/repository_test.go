@@ -17,7 +17,7 @@ package example
 
 /*
 func TestRepositories2(t *testing.T) {
-	err := Repositories("xxx.xx.xx", "username", "password")
+	err := Repositories(context.Background(), "xxx.xx.xx", "username", "password")
 	if err != nil {
 		t.Errorf("%v", err)
 	}
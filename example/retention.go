@@ -0,0 +1,88 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"fmt"
+	client2 "github.com/hujianxiong/go-harbor/pkg/client"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"strconv"
+)
+
+// RetentionOptions describes the retention policy set up by RetentionSetup.
+type RetentionOptions struct {
+	Project string
+	// KeepLatest is the number of the most recently pushed artifacts kept per repository.
+	KeepLatest int
+	// Tags is the pattern of the tags the rule applies to, e.g. ** or release-*.
+	Tags string
+	// Cron is the schedule of the retention runs, e.g. "0 0 0 * * *".
+	Cron string
+}
+
+// RetentionSetup creates the retention policy of a project keeping the latest pushed
+// artifacts of every repository, or replaces it if the project already has one.
+func RetentionSetup(ctx context.Context, clientSet *client2.Clientset, opts *RetentionOptions) error {
+	project, err := clientSet.V2.Get(ctx, opts.Project)
+	if err != nil {
+		return fmt.Errorf("get project %s error: %v", opts.Project, err)
+	}
+	metadata, err := clientSet.Retention.GetMetadatas(ctx)
+	if err != nil {
+		return fmt.Errorf("get retention metadatas error: %v", err)
+	}
+	policy := &model.RetentionPolicy{
+		Algorithm: "or",
+		Rules: []*model.RetentionRule{{
+			Action:       "retain",
+			Template:     "latestPushedK",
+			Params:       map[string]interface{}{"latestPushedK": opts.KeepLatest},
+			TagSelectors: []*model.RetentionSelector{{Kind: "doublestar", Decoration: "matches", Pattern: opts.Tags}},
+			ScopeSelectors: map[string][]*model.RetentionSelector{
+				"repository": {{Kind: "doublestar", Decoration: "repoMatches", Pattern: "**"}},
+			},
+		}},
+		Trigger: &model.RetentionTrigger{
+			Kind:     "Schedule",
+			Settings: map[string]interface{}{"cron": opts.Cron},
+		},
+		Scope: &model.RetentionScope{Level: "project", Ref: project.ProjectID},
+	}
+	if err := metadata.Validate(policy); err != nil {
+		return fmt.Errorf("invalid retention policy: %v", err)
+	}
+
+	if id, ok := project.Metadata["retention_id"]; ok {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("invalid retention id %q of project %s", id, opts.Project)
+		}
+		return clientSet.V2.RESTClient().Put().
+			Resource("retentions").
+			Name(id).
+			Body(policy).
+			Do(ctx).
+			Error()
+	}
+	return clientSet.V2.RESTClient().Post().
+		Resource("retentions").
+		Body(policy).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,89 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor"
+	client2 "github.com/hujianxiong/go-harbor/pkg/client"
+	"os"
+	"strings"
+	"time"
+)
+
+// Scenario holds the parameters shared by the end to end scenarios. The scenarios run
+// against a live Harbor and are only compiled with the e2e build tag:
+//
+//	HARBOR_HOST=harbor.example.com HARBOR_USERNAME=admin HARBOR_PASSWORD=... \
+//	    go test -tags e2e -v ./example/
+type Scenario struct {
+	Host     string
+	Username string
+	Password string
+	// Project is the project the scenarios work in, it is created if needed.
+	Project string
+	// Timeout bounds the waits of a scenario, e.g. for a scan or a replication.
+	Timeout time.Duration
+}
+
+// ScenarioFromEnv reads the scenario parameters from the HARBOR_* environment variables.
+// ok is false if HARBOR_HOST is not set.
+func ScenarioFromEnv() (scenario *Scenario, ok bool) {
+	scenario = &Scenario{
+		Host:     os.Getenv("HARBOR_HOST"),
+		Username: os.Getenv("HARBOR_USERNAME"),
+		Password: os.Getenv("HARBOR_PASSWORD"),
+		Project:  Getenv("HARBOR_E2E_PROJECT", "go-harbor-e2e"),
+		Timeout:  10 * time.Minute,
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("HARBOR_E2E_TIMEOUT")); err == nil {
+		scenario.Timeout = timeout
+	}
+	return scenario, scenario.Host != ""
+}
+
+// Getenv returns the environment variable key, or def if it is not set.
+func Getenv(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return def
+}
+
+// ClientSet returns a client set for the scenario, failing fast if Harbor is unreachable
+// or rejects the credentials.
+func (s *Scenario) ClientSet(ctx context.Context) (*client2.Clientset, error) {
+	clientSet, err := harbor.NewClientSetAndPing(ctx, s.Host, s.Username, s.Password)
+	if err != nil {
+		return nil, fmt.Errorf("get client set error:%v", err)
+	}
+	return clientSet, nil
+}
+
+// splitImage splits repository[:tag|@digest] into the repository and the reference.
+func splitImage(image string) (repository, reference string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
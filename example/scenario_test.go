@@ -0,0 +1,159 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package example
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"os"
+	"strings"
+	"testing"
+)
+
+func scenarioFromEnv(t *testing.T) *Scenario {
+	scenario, ok := ScenarioFromEnv()
+	if !ok {
+		t.Skip("HARBOR_HOST is not set")
+	}
+	return scenario
+}
+
+func TestProjectBootstrap(t *testing.T) {
+	scenario := scenarioFromEnv(t)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.Timeout)
+	defer cancel()
+	clientSet, err := scenario.ClientSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientSet.Close()
+
+	groups := map[string]int{}
+	for _, group := range strings.Split(os.Getenv("HARBOR_E2E_GROUPS"), ",") {
+		if group != "" {
+			groups[group] = RoleDeveloper
+		}
+	}
+	err = ProjectBootstrap(ctx, clientSet, &BootstrapOptions{
+		Project:      scenario.Project,
+		StorageLimit: -1,
+		Groups:       groups,
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestPushScanPromote(t *testing.T) {
+	scenario := scenarioFromEnv(t)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.Timeout)
+	defer cancel()
+	image := os.Getenv("HARBOR_E2E_IMAGE")
+	if image == "" {
+		t.Skip("HARBOR_E2E_IMAGE, the image pushed to the project, is not set")
+	}
+	clientSet, err := scenario.ClientSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientSet.Close()
+
+	target := Getenv("HARBOR_E2E_TARGET_PROJECT", scenario.Project+"-release")
+	if err := ProjectBootstrap(ctx, clientSet, &BootstrapOptions{Project: target, StorageLimit: -1}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = PushScanPromote(ctx, clientSet, &PromoteOptions{
+		Project:       scenario.Project,
+		Image:         image,
+		TargetProject: target,
+		MaxSeverity:   Getenv("HARBOR_E2E_MAX_SEVERITY", "Critical"),
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestRetentionSetup(t *testing.T) {
+	scenario := scenarioFromEnv(t)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.Timeout)
+	defer cancel()
+	clientSet, err := scenario.ClientSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientSet.Close()
+
+	if err := ProjectBootstrap(ctx, clientSet, &BootstrapOptions{Project: scenario.Project, StorageLimit: -1}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = RetentionSetup(ctx, clientSet, &RetentionOptions{
+		Project:    scenario.Project,
+		KeepLatest: 10,
+		Tags:       "**",
+		Cron:       "0 0 0 * * *",
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestReplicationDRDrill(t *testing.T) {
+	scenario := scenarioFromEnv(t)
+	ctx, cancel := context.WithTimeout(context.Background(), scenario.Timeout)
+	defer cancel()
+	drScenario := &Scenario{
+		Host:     os.Getenv("HARBOR_DR_HOST"),
+		Username: os.Getenv("HARBOR_DR_USERNAME"),
+		Password: os.Getenv("HARBOR_DR_PASSWORD"),
+	}
+	if drScenario.Host == "" {
+		t.Skip("HARBOR_DR_HOST is not set")
+	}
+	primary, err := scenario.ClientSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+	dr, err := drScenario.ClientSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dr.Close()
+
+	if err := ProjectBootstrap(ctx, dr, &BootstrapOptions{Project: scenario.Project, StorageLimit: -1}); err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = ReplicationDRDrill(ctx, primary, dr, &DRDrillOptions{
+		Primary: &model.Registry{
+			Name: "go-harbor-e2e-primary",
+			Type: model.RegistryTypeHarbor,
+			URL:  "https://" + scenario.Host,
+			Credential: &model.RegistryCredential{
+				Type:         "basic",
+				AccessKey:    scenario.Username,
+				AccessSecret: scenario.Password,
+			},
+		},
+		Project:    scenario.Project,
+		PolicyName: "go-harbor-e2e-dr-drill",
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+}
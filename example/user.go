@@ -16,29 +16,30 @@ See the License for the specific language governing permissions and
 package example
 
 import (
+	"context"
 	"fmt"
 	"github.com/hujianxiong/go-harbor"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 )
 
-func Users(host, username, password string) error {
+func Users(ctx context.Context, host, username, password string) error {
 	clientSet, err := harbor.NewClientSet(host, username, password)
 	if err != nil {
 		return fmt.Errorf("get client set error:%v", err)
 	}
-	result, err := clientSet.User.Get("1")
+	result, err := clientSet.User.Get(ctx, "1")
 	if err != nil || len(result.Username) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 	query := model.Query{
 		PageSize: 2,
 	}
-	result1, err := clientSet.User.List(&query)
+	result1, err := clientSet.User.List(ctx, &query)
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
 
-	err = clientSet.User.Delete("3")
+	err = clientSet.User.Delete(ctx, "3")
 	if err != nil || len(*result1) == 0 {
 		return fmt.Errorf("%v", err)
 	}
@@ -16,7 +16,7 @@ See the License for the specific language governing permissions and
 package example
 
 /*func TestUsers(t *testing.T) {
-	err := Users("xxx.xx.xx", "username", "password")
+	err := Users(context.Background(), "xxx.xx.xx", "username", "password")
 	if err != nil {
 		t.Errorf("%v", err)
 	}
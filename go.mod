@@ -1,13 +1,50 @@
 module github.com/hujianxiong/go-harbor
 
-go 1.15
+go 1.18
 
 require (
-	github.com/elazarl/goproxy v0.0.0-20210110162100-a92cc753f88e // indirect
+	github.com/astaxie/beego v1.12.1
 	github.com/goharbor/harbor/src v0.0.0-20210407101726-c5d12ce8ee18
-	github.com/moul/http2curl v1.0.0 // indirect
 	github.com/parnurzeal/gorequest v0.2.15
+	github.com/theupdateframework/notary v0.6.1
 	golang.org/x/net v0.0.0-20210410081132-afb366fc7cd1
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	k8s.io/klog v1.0.0
 )
+
+require (
+	github.com/Knetic/govaluate v3.0.0+incompatible // indirect
+	github.com/agl/ed25519 v0.0.0-20170116200512-5312a6153412 // indirect
+	github.com/bmatcuk/doublestar v1.1.1 // indirect
+	github.com/casbin/casbin v1.7.0 // indirect
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible // indirect
+	github.com/docker/distribution v2.7.1+incompatible // indirect
+	github.com/docker/go v0.0.0-20160303222718-d30aec9fd63c // indirect
+	github.com/docker/libtrust v0.0.0-20160708172513-aabc10ec26b7 // indirect
+	github.com/elazarl/goproxy v0.0.0-20210110162100-a92cc753f88e // indirect
+	github.com/go-openapi/errors v0.19.2 // indirect
+	github.com/go-sql-driver/mysql v1.5.0 // indirect
+	github.com/gocraft/work v0.5.1 // indirect
+	github.com/golang-migrate/migrate/v4 v4.11.0 // indirect
+	github.com/gomodule/redigo v2.0.0+incompatible // indirect
+	github.com/google/uuid v1.1.1 // indirect
+	github.com/gorilla/mux v1.7.4 // indirect
+	github.com/hashicorp/errwrap v1.0.0 // indirect
+	github.com/hashicorp/go-multierror v1.1.0 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
+	github.com/lib/pq v1.3.0 // indirect
+	github.com/miekg/pkcs11 v0.0.0-20170220202408-7283ca79f35e // indirect
+	github.com/moul/http2curl v1.0.0 // indirect
+	github.com/opencontainers/go-digest v1.0.0 // indirect
+	github.com/opencontainers/image-spec v1.0.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/robfig/cron v1.0.0 // indirect
+	github.com/shiena/ansicolor v0.0.0-20151119151921-a422bbe96644 // indirect
+	github.com/sirupsen/logrus v1.4.2 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.0.0-rc.2 // indirect
+	github.com/vmihailenco/tagparser v0.1.2 // indirect
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9 // indirect
+	golang.org/x/sys v0.0.0-20210330210617-4fbd30eecc44 // indirect
+	golang.org/x/text v0.3.6 // indirect
+	gopkg.in/yaml.v2 v2.3.0 // indirect
+)
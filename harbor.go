@@ -1,13 +1,14 @@
 package harbor
 
 import (
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
 	"github.com/parnurzeal/gorequest"
 	"net/url"
 	"strings"
 )
 
 const (
-	libraryVersion = "2.0.0"
+	libraryVersion = rest2.Version
 	apiVersion     = "v2.0"
 	userAgent      = "go-harbor/" + libraryVersion
 )
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package harbor
+
+import (
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ClientSetOption customizes the configuration of the client sets created by NewClientSet
+// and NewClientSetAndPing.
+type ClientSetOption func(config *rest2.Config)
+
+// WithCAFile makes the client trust the PEM encoded root certificates of file, e.g. the CA
+// of a Harbor instance with a certificate signed by a private CA.
+func WithCAFile(file string) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.CAFile = file
+	}
+}
+
+// WithCAData makes the client trust the PEM encoded root certificates of data, it takes
+// precedence over WithCAFile.
+func WithCAData(data []byte) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.CAData = data
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the certificate of Harbor. For
+// testing only, prefer WithCAFile.
+func WithInsecureSkipVerify() ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Insecure = true
+	}
+}
+
+// WithServerName sets the server name sent for SNI and checked against the certificate of
+// Harbor when it differs from the host, e.g. when Harbor is reached by IP address.
+func WithServerName(serverName string) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.ServerName = serverName
+	}
+}
+
+// WithClientCertificateFiles authenticates the client with the PEM encoded certificate and
+// key of files, for Harbor deployments requiring mutual TLS. The certificate is reloaded
+// when the files change, so that rotated certificates are picked up.
+func WithClientCertificateFiles(certFile, keyFile string) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.CertFile = certFile
+		config.KeyFile = keyFile
+	}
+}
+
+// WithClientCertificate authenticates the client with the PEM encoded certificate and key,
+// it takes precedence over WithClientCertificateFiles.
+func WithClientCertificate(certPEM, keyPEM []byte) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.CertData = certPEM
+		config.KeyData = keyPEM
+	}
+}
+
+// WithProxy sends the requests through the proxy at proxyURL, except the ones to the hosts
+// matching noProxy, given in the NO_PROXY format, rather than through the proxy of the
+// environment. An empty proxyURL disables the proxy. An invalid proxyURL fails the
+// requests.
+func WithProxy(proxyURL string, noProxy ...string) ClientSetOption {
+	return func(config *rest2.Config) {
+		proxy, err := rest2.ProxyFor(proxyURL, noProxy...)
+		if err != nil {
+			proxy = func(*http.Request) (*url.URL, error) {
+				return nil, err
+			}
+		}
+		config.Proxy = proxy
+	}
+}
+
+// WithLogger logs every request of the clientset with logger, e.g. a *slog.Logger or a
+// logr.Logger, see rest.Logger.
+func WithLogger(logger rest2.Logger) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Logger = logger
+	}
+}
+
+// WithDebug writes every request of the clientset, as an equivalent curl command, and its
+// response to out with the secrets masked, see rest.DumpTransport.
+func WithDebug(out io.Writer) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Debug = out
+	}
+}
+
+// WithMetrics records the measurements of every request of the clientset with metrics,
+// see rest.Metrics.
+func WithMetrics(metrics rest2.Metrics) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Metrics = metrics
+	}
+}
+
+// WithUserAgent identifies the caller in the User-Agent of the requests of the clientset,
+// e.g. my-operator/1.2.0, the one of the SDK is appended to it.
+func WithUserAgent(userAgent string) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.UserAgent = userAgent
+	}
+}
+
+// WithConnectionPool sizes the pool of the connections to Harbor, keeping up to
+// maxIdleConnsPerHost idle connections open for reuse for idleConnTimeout. A zero value
+// keeps the default, see rest.DefaultMaxIdleConnsPerHost and rest.DefaultIdleConnTimeout.
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.MaxIdleConns = maxIdleConns
+		config.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		config.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithTLSHandshakeTimeout bounds the TLS handshake of the new connections to Harbor.
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2, enabled by default. Disabling it forces HTTP/1.1,
+// e.g. for a Harbor behind an ingress misbehaving with HTTP/2.
+func WithHTTP2(enabled bool) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.DisableHTTP2 = !enabled
+	}
+}
+
+// WithETagCache makes the GET requests of the clientset conditional with the ETag of
+// their response cached in cache, e.g. rest.NewETagCache(1000), so that polling an
+// unchanged resource gets a 304 Not Modified instead of its payload.
+func WithETagCache(cache rest2.ETagCache) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.ETagCache = cache
+	}
+}
+
+// WithHedging sends a second attempt of the GET requests of the clientset still waiting
+// for a response after delay, e.g. when a replica of Harbor behind a load balancer is
+// slow. Pick a delay around the 95th percentile of the latency, so that only the slowest
+// requests are sent twice.
+func WithHedging(delay time.Duration) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.HedgeDelay = delay
+	}
+}
+
+// WithAPIVersionPath sets the path of the API on the host instead of detecting it on first
+// use, e.g. rest.DefaultVersionApiPath for Harbor 2.x or rest.LegacyVersionApiPath for
+// Harbor 1.x.
+func WithAPIVersionPath(apiPath string) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.APIVersionPath = apiPath
+	}
+}
+
+// WithRedirectPolicy decides how the redirects of Harbor are followed, e.g. whether the
+// credentials are sent to the storage a download is redirected to, see rest.RedirectPolicy.
+func WithRedirectPolicy(policy rest2.RedirectPolicy) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.RedirectPolicy = &policy
+	}
+}
+
+// WithSession authenticates the clientset with a session of the UI of Harbor, logging in
+// once with the username and password, rather than with basic authentication, for the
+// APIs only reachable with a session.
+func WithSession() ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Session = true
+	}
+}
+
+// WithReadOnly refuses the mutating requests of the clientset before they are sent, e.g.
+// for a reporting tool which must never change Harbor, they fail with an error matching
+// rest.ErrReadOnly.
+func WithReadOnly() ClientSetOption {
+	return func(config *rest2.Config) {
+		config.ReadOnly = true
+	}
+}
+
+// WithDryRun captures the mutating requests of the clientset in recorder instead of
+// sending them, e.g. to show the changes a tool would apply, see rest.Recorder.
+func WithDryRun(recorder *rest2.Recorder) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.DryRun = recorder
+	}
+}
+
+// WithStrictDecoding decodes the responses with rest.StrictJSONSerializer if strict, failing
+// on the fields the models don't have, e.g. in integration tests against a new release of
+// Harbor, and with the lenient rest.JSONSerializer, the default, otherwise.
+func WithStrictDecoding(strict bool) ClientSetOption {
+	return func(config *rest2.Config) {
+		if strict {
+			config.Serializer = rest2.StrictJSONSerializer
+		} else {
+			config.Serializer = rest2.JSONSerializer
+		}
+	}
+}
+
+// WithRateLimiter limits the rate of the requests of the clientset with limiter instead of
+// the token bucket of QPS and Burst, e.g. flowcontrol.NewLeakyBucketRateLimiter to space
+// them evenly.
+func WithRateLimiter(limiter flowcontrol.RateLimiter) ClientSetOption {
+	return func(config *rest2.Config) {
+		config.RateLimiter = limiter
+	}
+}
+
+// WithLeakyBucket spaces the requests of the clientset evenly at qps requests per second,
+// without the bursts of the default token bucket which trip the rate limit of Harbor. It
+// panics if qps isn't positive.
+func WithLeakyBucket(qps float32) ClientSetOption {
+	return WithRateLimiter(flowcontrol.NewLeakyBucketRateLimiter(qps))
+}
+
+// WithFixedWindow allows up to limit requests of the clientset per window, e.g. 100 per
+// minute, matching a rate limit counted over fixed windows. It panics if limit or window
+// isn't positive.
+func WithFixedWindow(limit int, window time.Duration) ClientSetOption {
+	return WithRateLimiter(flowcontrol.NewFixedWindowRateLimiter(limit, window))
+}
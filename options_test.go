@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package harbor
+
+import (
+	"context"
+	"errors"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithReadOnly(t *testing.T) {
+	var mutations int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			atomic.AddInt32(&mutations, 1)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	clientSet, err := NewClientSet(server.URL, "admin", "Harbor12345", WithAPIVersionPath(rest2.DefaultVersionApiPath), WithReadOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientSet.V2.Get(context.Background(), "library"); err != nil {
+		t.Errorf("expected the read-only client set to read, got %v", err)
+	}
+	if err := clientSet.V2.Delete(context.Background(), "library"); !errors.Is(err, rest2.ErrReadOnly) {
+		t.Errorf("expected the deletion to be refused with ErrReadOnly, got %v", err)
+	}
+	if n := atomic.LoadInt32(&mutations); n != 0 {
+		t.Errorf("expected no mutating request to be sent, got %d", n)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+// Package otelharbor traces the requests of the go-harbor clients with OpenTelemetry. It is
+// a separate module so that the clients do not depend on OpenTelemetry.
+package otelharbor
+
+import (
+	harbor "github.com/hujianxiong/go-harbor"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// instrumentationName is the name of the tracer of the spans.
+const instrumentationName = "github.com/hujianxiong/go-harbor/otelharbor"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option customizes the tracing of the requests.
+type Option func(config *config)
+
+// WithTracerProvider creates the spans with provider rather than the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(config *config) {
+		config.tracerProvider = provider
+	}
+}
+
+// WithPropagators propagates the trace context with propagators rather than the global
+// propagators.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(config *config) {
+		config.propagators = propagators
+	}
+}
+
+// WrapTransport returns a transport middleware creating a client span per request, named
+// after its method and its route template, e.g. GET /api/v2.0/projects/{name}, with the
+// http.method, http.route and http.status_code attributes, and propagating the trace
+// context in the headers of the request. See rest.Config.Wrap and rest.RESTClient.Use.
+func WrapTransport(options ...Option) rest2.WrapperFunc {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return rest2.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			route, ok := rest2.RouteFrom(req.Context())
+			if !ok {
+				route = req.URL.Path
+			}
+			ctx, span := tracer.Start(req.Context(), req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.HTTPMethod(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.NetPeerName(req.URL.Hostname()),
+				))
+			defer span.End()
+
+			req = req.Clone(ctx)
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+			c.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+			span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+			if resp.StatusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// WithTracing traces the requests of the clientset, see WrapTransport.
+func WithTracing(options ...Option) harbor.ClientSetOption {
+	return func(config *rest2.Config) {
+		config.Wrap(WrapTransport(options...))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package otelharbor
+
+import (
+	"context"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrapTransport(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("traceparent")
+		if req.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	config := rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	WithTracing(WithTracerProvider(provider), WithPropagators(propagation.TraceContext{}))(config)
+	client, err := rest2.RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	if err := client.Get().Resource("projects").Name("library").Do(ctx).Error(); err != nil {
+		t.Fatal(err)
+	}
+	client.Delete().Resource("projects").Name("library").Do(ctx)
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected a span per request, got %d", len(spans))
+	}
+	get, del := spans[0], spans[1]
+	if get.Name() != "GET /api/v2.0/projects/{name}" || get.SpanKind() != trace.SpanKindClient {
+		t.Errorf("unexpected span %s of kind %v", get.Name(), get.SpanKind())
+	}
+	if get.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("the span should be a child of the span of the context")
+	}
+	attributes := attribute.NewSet(get.Attributes()...)
+	for key, expected := range map[attribute.Key]attribute.Value{
+		"http.method":      attribute.StringValue(http.MethodGet),
+		"http.route":       attribute.StringValue("/api/v2.0/projects/{name}"),
+		"http.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		if value, ok := attributes.Value(key); !ok || value != expected {
+			t.Errorf("expected %s=%s, got %s", key, expected.Emit(), value.Emit())
+		}
+	}
+	if del.Status().Code != codes.Error {
+		t.Errorf("a 5xx response should fail the span, got %v", del.Status())
+	}
+	if traceparent == "" || traceparent[36:52] != del.SpanContext().SpanID().String() {
+		t.Errorf("expected the trace context of the span to be propagated, got %q", traceparent)
+	}
+}
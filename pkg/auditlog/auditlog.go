@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package auditlog
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// AuditLogsInterface holds the methods of the audit log APIs.
+type AuditLogsInterface interface {
+	List(ctx context.Context, query *model.AuditLogQuery) (result *model.AuditLogList, err error)
+	ListProject(ctx context.Context, project string, query *model.AuditLogQuery) (result *model.AuditLogList, err error)
+}
+
+// AuditLogsClient is used to list the audit logs of Harbor.
+type AuditLogsClient struct {
+	restClient rest2.Interface
+}
+
+func NewAuditLogsClient(restClient *rest2.Config) (*AuditLogsClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLogsClient{restClient: client}, nil
+}
+
+// List lists the audit logs of the projects the current user is a member of, or of
+// all the projects for the system admin.
+func (a *AuditLogsClient) List(ctx context.Context, query *model.AuditLogQuery) (result *model.AuditLogList, err error) {
+	return a.list(ctx, a.restClient.List().
+		Resource("audit-logs"), query)
+}
+
+// ListProject lists the audit logs of a project.
+func (a *AuditLogsClient) ListProject(ctx context.Context, project string, query *model.AuditLogQuery) (result *model.AuditLogList, err error) {
+	return a.list(ctx, a.restClient.List().
+		Project(project).
+		Resource("logs"), query)
+}
+
+func (a *AuditLogsClient) list(ctx context.Context, request *rest2.Request, query *model.AuditLogQuery) (result *model.AuditLogList, err error) {
+	if query == nil {
+		query = &model.AuditLogQuery{}
+	}
+	params, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	result = &model.AuditLogList{}
+	response := request.
+		Params(params).
+		Do(ctx)
+	if err = response.IntoList(&result.Items); err != nil {
+		return nil, err
+	}
+	if total, ok := response.TotalCount(); ok {
+		result.Total = total
+	} else {
+		result.Total = int64(len(result.Items))
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package chart
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// chartRepoPath is the root of the ChartMuseum API of Harbor, it is not versioned.
+const chartRepoPath = "/api/chartrepo"
+
+// ChartsInterface holds the methods of the chart repository APIs.
+type ChartsInterface interface {
+	List(ctx context.Context, project string) (result *[]model.ChartInfo, err error)
+	Versions(ctx context.Context, project, name string) (result *[]model.ChartVersion, err error)
+	GetVersion(ctx context.Context, project, name, version string) (result *model.ChartVersionDetails, err error)
+	Delete(ctx context.Context, project, name string) (err error)
+	DeleteVersion(ctx context.Context, project, name, version string) (err error)
+	Upload(ctx context.Context, project, chartFile, provFile string) (err error)
+	UploadProvenance(ctx context.Context, project, provFile string) (err error)
+}
+
+// ChartsClient is used to interact with the chart repositories of the projects of Harbor
+// versions backed by ChartMuseum, which was removed in Harbor 2.8.
+type ChartsClient struct {
+	restClient rest2.Interface
+}
+
+func NewChartsClient(restClient *rest2.Config) (*ChartsClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &ChartsClient{restClient: client}, nil
+}
+
+// List lists the charts of a project.
+func (c *ChartsClient) List(ctx context.Context, project string) (result *[]model.ChartInfo, err error) {
+	result = &[]model.ChartInfo{}
+	err = c.restClient.Get().
+		AbsPath(chartRepoPath, project, "charts").
+		Do(ctx).
+		IntoList(result)
+	return
+}
+
+// Versions lists the versions of a chart.
+func (c *ChartsClient) Versions(ctx context.Context, project, name string) (result *[]model.ChartVersion, err error) {
+	result = &[]model.ChartVersion{}
+	err = c.restClient.Get().
+		AbsPath(chartRepoPath, project, "charts", name).
+		Do(ctx).
+		IntoList(result)
+	return
+}
+
+// GetVersion gets the details of a chart version, including its values and dependencies.
+func (c *ChartsClient) GetVersion(ctx context.Context, project, name, version string) (result *model.ChartVersionDetails, err error) {
+	result = &model.ChartVersionDetails{}
+	err = c.restClient.Get().
+		AbsPath(chartRepoPath, project, "charts", name, version).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete deletes all the versions of a chart.
+func (c *ChartsClient) Delete(ctx context.Context, project, name string) (err error) {
+	return c.restClient.Delete().
+		AbsPath(chartRepoPath, project, "charts", name).
+		Do(ctx).
+		Error()
+}
+
+func (c *ChartsClient) DeleteVersion(ctx context.Context, project, name, version string) (err error) {
+	return c.restClient.Delete().
+		AbsPath(chartRepoPath, project, "charts", name, version).
+		Do(ctx).
+		Error()
+}
+
+// Upload uploads the chart archive chartFile, a .tgz, to the chart repository of a
+// project along with its provenance file provFile, if not empty.
+func (c *ChartsClient) Upload(ctx context.Context, project, chartFile, provFile string) (err error) {
+	files := map[string]string{"chart": chartFile}
+	if provFile != "" {
+		files["prov"] = provFile
+	}
+	return c.upload(ctx, project, "charts", files)
+}
+
+// UploadProvenance uploads the provenance file of a chart already uploaded.
+func (c *ChartsClient) UploadProvenance(ctx context.Context, project, provFile string) (err error) {
+	return c.upload(ctx, project, "prov", map[string]string{"prov": provFile})
+}
+
+// upload posts files, keyed by form field, as a multipart form.
+func (c *ChartsClient) upload(ctx context.Context, project, endpoint string, files map[string]string) error {
+	return c.restClient.Post().
+		AbsPath(chartRepoPath, project, endpoint).
+		MultipartBody(nil, files).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/report"
+)
+
+// ChartImages builds the co-versioning report of the charts of project, see
+// report.ChartImages. Unless opts sets Hosts or RegistryHost, the images are resolved with
+// c.References, so that images pulled with either the API host or the pull host of Harbor
+// are looked up in Harbor and the others are reported as external.
+func (c *Clientset) ChartImages(ctx context.Context, project string, opts *report.ChartImageOptions) ([]report.ChartImageReport, error) {
+	options := report.ChartImageOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Hosts == nil && options.RegistryHost == "" {
+		options.Hosts = c.References
+	}
+	return report.ChartImages(ctx, c.Chart, c.V2, project, &options)
+}
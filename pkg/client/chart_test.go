@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCharts serves the chart app of the project library and its images, recording the
+// repositories looked up.
+type fakeCharts struct {
+	values map[string]interface{}
+
+	mu     sync.Mutex
+	lookup []string
+}
+
+func (f *fakeCharts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const artifacts = "/api/v2.0/projects/library/repositories/"
+	switch {
+	case r.URL.Path == "/api/chartrepo/library/charts":
+		json.NewEncoder(w).Encode([]model.ChartInfo{{Name: "app", LatestVersion: "1.0.0"}})
+	case r.URL.Path == "/api/chartrepo/library/charts/app/1.0.0":
+		json.NewEncoder(w).Encode(&model.ChartVersionDetails{Values: f.values})
+	case strings.HasPrefix(r.URL.Path, artifacts):
+		f.mu.Lock()
+		f.lookup = append(f.lookup, strings.Split(strings.TrimPrefix(r.URL.Path, artifacts), "/")[0])
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(&model.Artifact{ScanOverview: map[string]*model.ScanOverview{
+			"application/vnd.security.vulnerability.report; version=1.1": {ScanStatus: model.ScanStatusSuccess},
+		}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestChartImagesDefaultsToReferences(t *testing.T) {
+	charts := &fakeCharts{}
+	server := httptest.NewServer(charts)
+	defer server.Close()
+	apiHost := strings.TrimPrefix(server.URL, "http://")
+	charts.values = map[string]interface{}{
+		"app":     map[string]interface{}{"image": "registry.example.com/library/app:1.0"},
+		"sidecar": map[string]interface{}{"image": apiHost + "/library/sidecar:2.0"},
+		"proxy":   map[string]interface{}{"image": "docker.io/library/nginx:1.25"},
+	}
+	config := rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.PullHost = "registry.example.com"
+	cs, err := NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := cs.ChartImages(context.Background(), "library", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || len(reports[0].Images) != 3 {
+		t.Fatalf("expected 1 chart version with 3 images, got %+v", reports)
+	}
+	for _, image := range reports[0].Images {
+		external := strings.HasPrefix(image.Reference, "docker.io/")
+		if image.External != external || image.Exists == external {
+			t.Errorf("%s: expected external %v, got %+v", image.Reference, external, image)
+		}
+	}
+	if !reports[0].Healthy() {
+		t.Errorf("expected the chart to be healthy, got %+v", reports[0].Images)
+	}
+	sort.Strings(charts.lookup)
+	if strings.Join(charts.lookup, ",") != "app,sidecar" {
+		t.Errorf("expected only the images of Harbor to be looked up, got %v", charts.lookup)
+	}
+}
@@ -17,10 +17,24 @@ package client
 
 import (
 	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/auditlog"
+	"github.com/hujianxiong/go-harbor/pkg/chart"
+	"github.com/hujianxiong/go-harbor/pkg/jobservice"
+	"github.com/hujianxiong/go-harbor/pkg/ldap"
+	"github.com/hujianxiong/go-harbor/pkg/preheat"
 	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+	"github.com/hujianxiong/go-harbor/pkg/quota"
+	"github.com/hujianxiong/go-harbor/pkg/reference"
+	"github.com/hujianxiong/go-harbor/pkg/registry"
+	"github.com/hujianxiong/go-harbor/pkg/replication"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
 	flowcontrol2 "github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"github.com/hujianxiong/go-harbor/pkg/retention"
+	"github.com/hujianxiong/go-harbor/pkg/robot"
+	"github.com/hujianxiong/go-harbor/pkg/system"
 	"github.com/hujianxiong/go-harbor/pkg/user"
+	"github.com/hujianxiong/go-harbor/pkg/usergroup"
+	"github.com/hujianxiong/go-harbor/pkg/webhook"
 )
 
 type Interface interface {
@@ -30,8 +44,27 @@ type Interface interface {
 // Clientset contains the clients for groups. Each group has exactly one
 // version included in a Clientset.
 type Clientset struct {
-	V2   *project2.ProjectsV2Client
-	User *user.UsersClient
+	V2          *project2.ProjectsV2Client
+	User        *user.UsersClient
+	System      *system.SystemClient
+	AuditLog    *auditlog.AuditLogsClient
+	Registry    *registry.RegistriesClient
+	Replication *replication.ReplicationClient
+	UserGroup   *usergroup.UserGroupsClient
+	LDAP        *ldap.LdapClient
+	Robot       *robot.RobotsClient
+	Preheat     *preheat.PreheatClient
+	Webhook     *webhook.WebhooksClient
+	JobService  *jobservice.JobServiceClient
+	Retention   *retention.RetentionClient
+	Chart       *chart.ChartsClient
+	Quota       *quota.QuotasClient
+
+	// References builds and rewrites the references of the images of Harbor between the
+	// API host and the pull host of the configuration.
+	References *reference.HostRewriter
+
+	config *rest2.Config
 }
 
 func NewForConfig(c *rest2.Config) (*Clientset, error) {
@@ -42,7 +75,10 @@ func NewForConfig(c *rest2.Config) (*Clientset, error) {
 		}
 		configShallowCopy.RateLimiter = flowcontrol2.NewTokenBucketRateLimiter(configShallowCopy.QPS, configShallowCopy.Burst)
 	}
-	cs := &Clientset{}
+	cs := &Clientset{
+		config:     &configShallowCopy,
+		References: reference.NewHostRewriter(configShallowCopy.APIPath, configShallowCopy.PullHost),
+	}
 	var err error
 	cs.V2, err = project2.NewProjectsV1Client(&configShallowCopy)
 	if err != nil {
@@ -52,5 +88,63 @@ func NewForConfig(c *rest2.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.System, err = system.NewSystemClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.AuditLog, err = auditlog.NewAuditLogsClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Registry, err = registry.NewRegistriesClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Replication, err = replication.NewReplicationClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.UserGroup, err = usergroup.NewUserGroupsClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.LDAP, err = ldap.NewLdapClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Robot, err = robot.NewRobotsClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Preheat, err = preheat.NewPreheatClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Webhook, err = webhook.NewWebhooksClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.JobService, err = jobservice.NewJobServiceClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Retention, err = retention.NewRetentionClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Chart, err = chart.NewChartsClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.Quota, err = quota.NewQuotasClient(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 	return cs, nil
 }
+
+// Close zeroes the credentials shared by the clients of the client set, the client set
+// must not be used afterwards.
+func (c *Clientset) Close() {
+	c.config.Zero()
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"time"
+)
+
+// defaultQuotaPollInterval is the default interval the quota usage is polled at.
+const defaultQuotaPollInterval = 10 * time.Second
+
+// QuotaReleaseOptions holds the settings of WaitForQuotaRelease.
+type QuotaReleaseOptions struct {
+	// Baseline is the storage usage of the project before the deletions, in bytes.
+	// Defaults to the usage when the wait starts.
+	Baseline int64
+	// Bytes is the storage to free, the wait ends once the usage is Bytes below Baseline,
+	// or as soon as it drops if Bytes is 0.
+	Bytes int64
+	// RunGC starts a garbage collection, unless one is already running, and ends the wait
+	// once it is over even if less than Bytes were freed.
+	RunGC bool
+	// DeleteUntagged makes the garbage collection delete the untagged artifacts as well.
+	DeleteUntagged bool
+	// PollInterval is the interval the quota usage is polled at, 10 seconds by default.
+	PollInterval time.Duration
+}
+
+// QuotaRelease is the storage freed in a project.
+type QuotaRelease struct {
+	Baseline  int64 `json:"baseline"`
+	Used      int64 `json:"used"`
+	Reclaimed int64 `json:"reclaimed"`
+	// Released is set if the requested storage was freed.
+	Released bool `json:"released"`
+	// GC is the garbage collection waited for, if any.
+	GC *model.GCHistory `json:"gc,omitempty"`
+}
+
+// WaitForQuotaRelease waits for the storage of deleted artifacts to be released from the
+// quota of project, which only happens once the garbage collection deleted their blobs,
+// optionally running it. It returns the storage reclaimed so far along with the error
+// once ctx is done, without RunGC the wait only ends when the storage is freed or ctx is
+// done.
+func (c *Clientset) WaitForQuotaRelease(ctx context.Context, project string, opts *QuotaReleaseOptions) (*QuotaRelease, error) {
+	if opts == nil {
+		opts = &QuotaReleaseOptions{}
+	}
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultQuotaPollInterval
+	}
+	result := &QuotaRelease{Baseline: opts.Baseline}
+	released := func() (bool, error) {
+		used, err := c.storageUsed(ctx, project)
+		if err != nil {
+			return false, err
+		}
+		if result.Baseline <= 0 {
+			result.Baseline = used
+		}
+		result.Used = used
+		result.Reclaimed = result.Baseline - used
+		result.Released = result.Reclaimed > 0 && result.Reclaimed >= opts.Bytes
+		return result.Released, nil
+	}
+	if ok, err := released(); ok || err != nil {
+		return result, err
+	}
+
+	// started is the ID of the latest run before the one triggered, which may not be listed
+	// yet right after the trigger, the previous run being returned meanwhile
+	started := int64(-1)
+	if opts.RunGC {
+		gc, err := c.latestGC(ctx)
+		if err != nil {
+			return result, err
+		}
+		if gc == nil || gc.Done() {
+			if err := c.System.RunGC(ctx, opts.DeleteUntagged); err != nil {
+				return result, fmt.Errorf("run gc error: %v", err)
+			}
+			if started = 0; gc != nil {
+				started = gc.ID
+			}
+		} else {
+			result.GC = gc
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("wait for the quota release of project %s: %v", project, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+		if started >= 0 && result.GC == nil {
+			gc, err := c.latestGC(ctx)
+			if err != nil {
+				return result, err
+			}
+			if gc != nil && gc.ID > started {
+				result.GC = gc
+			}
+		} else if result.GC != nil && !result.GC.Done() {
+			gc, err := c.System.GetGC(ctx, result.GC.ID)
+			if err != nil {
+				return result, fmt.Errorf("get gc %d error: %v", result.GC.ID, err)
+			}
+			result.GC = gc
+		}
+		if ok, err := released(); ok || err != nil {
+			return result, err
+		}
+		if result.GC != nil && result.GC.Done() {
+			return result, nil
+		}
+	}
+}
+
+// storageUsed returns the storage used by project, in bytes.
+func (c *Clientset) storageUsed(ctx context.Context, project string) (int64, error) {
+	summary, err := c.V2.Summary(ctx, project)
+	if err != nil {
+		return 0, fmt.Errorf("get summary of project %s error: %v", project, err)
+	}
+	if summary.Quota == nil {
+		return 0, fmt.Errorf("project %s has no quota", project)
+	}
+	return summary.Quota.Used["storage"], nil
+}
+
+// latestGC returns the latest garbage collection run, nil if there is none.
+func (c *Clientset) latestGC(ctx context.Context) (*model.GCHistory, error) {
+	list, err := c.System.ListGC(ctx, &model.Query{Page: 1, PageSize: 1})
+	if err != nil {
+		return nil, fmt.Errorf("list gc error: %v", err)
+	}
+	if len(*list) == 0 {
+		return nil, nil
+	}
+	return &(*list)[0], nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGC is a Harbor whose garbage collection frees storage, the triggered run only being
+// listed after a delay.
+type fakeGC struct {
+	mu      sync.Mutex
+	used    int64
+	runs    []model.GCHistory
+	lag     int
+	started bool
+	polls   int
+}
+
+func (f *fakeGC) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body interface{}
+	switch req.URL.Path {
+	case "/api/v2.0/projects/library/summary":
+		body = &model.ProjectSummary{Quota: &model.ProjectSummaryQuota{Used: map[string]int64{"storage": f.used}}}
+	case "/api/v2.0/system/gc/schedule":
+		f.started, f.lag = true, 2
+		w.WriteHeader(http.StatusCreated)
+		return
+	case "/api/v2.0/system/gc":
+		if f.started {
+			if f.lag--; f.lag < 0 && f.runs[0].ID == 1 {
+				f.runs = append([]model.GCHistory{{ID: 2, JobStatus: model.TaskStatusRunning}}, f.runs...)
+			}
+		}
+		body = f.runs[:1]
+	case "/api/v2.0/system/gc/2":
+		if f.polls++; f.polls > 1 {
+			f.runs[0].JobStatus = model.TaskStatusSuccess
+			f.used = 800
+		}
+		body = f.runs[0]
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestWaitForQuotaReleaseRunGC(t *testing.T) {
+	harbor := &fakeGC{used: 1000, runs: []model.GCHistory{{ID: 1, JobStatus: model.TaskStatusSuccess}}}
+	server := httptest.NewServer(harbor)
+	defer server.Close()
+	cs, err := NewForConfig(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	release, err := cs.WaitForQuotaRelease(ctx, "library", &QuotaReleaseOptions{
+		Bytes:        100,
+		RunGC:        true,
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.GC == nil || release.GC.ID != 2 {
+		t.Errorf("expected to wait for the triggered run rather than the previous one, got %+v", release.GC)
+	}
+	if !release.Released || release.Reclaimed != 200 {
+		t.Errorf("expected 200 bytes to be reclaimed, got %+v", release)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"github.com/hujianxiong/go-harbor/pkg/robot"
+	"k8s.io/klog"
+	"sync"
+	"time"
+)
+
+// ScopedOptions holds the settings of the robot account of a scoped client set.
+type ScopedOptions struct {
+	// TTL is the lifetime of the robot account, it is deleted once it elapses.
+	TTL time.Duration
+	// Access is the access granted on the project, pulling its repositories by default.
+	Access []*model.Access
+	// Name is the name of the robot account, Harbor prefixing it with robot$<project>+.
+	// Defaults to a unique scoped-<timestamp> name.
+	Name        string
+	Description string
+}
+
+// ScopedClientset is a client set authenticated as a short-lived robot account of a
+// project, e.g. to hand temporary access to a subprocess.
+type ScopedClientset struct {
+	*Clientset
+
+	// Robot is the robot account the client set is authenticated as, without its secret.
+	Robot *model.RobotCreated
+
+	robots    *robot.RobotsClient
+	timer     *time.Timer
+	once      sync.Once
+	revokeErr error
+}
+
+// NewScoped creates a robot account of project allowed the access of opts only and returns
+// a client set authenticated as it. The robot account is deleted once opts.TTL elapses, or
+// on Release if earlier. Since Harbor expires robot accounts by days, the robot account
+// also expires server side on the day after the TTL in case the deletion fails, e.g.
+// because the process exited.
+func (c *Clientset) NewScoped(ctx context.Context, project string, opts *ScopedOptions) (*ScopedClientset, error) {
+	if opts == nil || opts.TTL <= 0 {
+		return nil, fmt.Errorf("a positive TTL is required for a scoped client set")
+	}
+	access := opts.Access
+	if len(access) == 0 {
+		access = []*model.Access{{Resource: model.ResourceRepository, Action: model.ActionPull}}
+	}
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("scoped-%d", time.Now().UnixNano())
+	}
+	days := int64((opts.TTL + 24*time.Hour - 1) / (24 * time.Hour))
+	account := model.NewProjectRobot(project, name, days, access...)
+	account.Description = opts.Description
+
+	// the secret is captured by the escrow of a dedicated client so that it only ends up in
+	// the config of the scoped client set
+	config := *c.config
+	robots, err := robot.NewRobotsClient(&config)
+	if err != nil {
+		return nil, err
+	}
+	robots.SetEscrow(robot.SecretEscrowFunc(func(created *model.RobotCreated, secret *rest2.Secret) error {
+		config.Username = created.Name
+		config.Password = rest2.NewSecret(secret.Reveal())
+		config.BearerToken = nil
+		config.BearerTokenFile = ""
+		return nil
+	}))
+	created, err := robots.Create(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("create scoped robot account of project %s error: %v", project, err)
+	}
+	cs, err := NewForConfig(&config)
+	if err != nil {
+		config.Zero()
+		if deleteErr := robots.Delete(ctx, created.ID); deleteErr != nil {
+			klog.Errorf("delete scoped robot account %s error: %v", created.Name, deleteErr)
+		}
+		return nil, err
+	}
+	scoped := &ScopedClientset{Clientset: cs, Robot: created, robots: robots}
+	scoped.timer = time.AfterFunc(opts.TTL, func() {
+		if err := scoped.revoke(); err != nil {
+			klog.Errorf("delete expired scoped robot account %s error: %v", created.Name, err)
+		}
+	})
+	return scoped, nil
+}
+
+// Release deletes the robot account before its TTL elapses and zeroes its secret, the
+// client set must not be used afterwards. It returns the error of the deletion, which only
+// happens once whether it is triggered by Release or by the TTL.
+func (s *ScopedClientset) Release() error {
+	s.timer.Stop()
+	return s.revoke()
+}
+
+// revoke deletes the robot account, without a deadline since it runs when the TTL elapses
+// as well.
+func (s *ScopedClientset) revoke() error {
+	s.once.Do(func() {
+		s.Close()
+		s.revokeErr = s.robots.Delete(context.Background(), s.Robot.ID)
+	})
+	return s.revokeErr
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeHarbor is a Harbor accepting the UI sessions of an admin and of the robot accounts
+// it creates, and recording the principal of each request.
+type fakeHarbor struct {
+	mu         sync.Mutex
+	passwords  map[string]string
+	principals map[string][]string
+}
+
+func (f *fakeHarbor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if req.URL.Path == "/c/login" {
+		principal := req.FormValue("principal")
+		if password, ok := f.passwords[principal]; !ok || password != req.FormValue("password") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: principal, Path: "/"})
+		return
+	}
+	cookie, err := req.Cookie("sid")
+	if err != nil || req.Header.Get("Authorization") != "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.principals[req.URL.Path] = append(f.principals[req.URL.Path], cookie.Value)
+	switch {
+	case req.Method == http.MethodGet:
+		w.Header().Set("X-Harbor-Csrf-Token", "token")
+		w.Write([]byte("{}"))
+	case req.Header.Get("X-Harbor-Csrf-Token") != "token":
+		w.WriteHeader(http.StatusForbidden)
+	case req.URL.Path == "/api/v2.0/robots":
+		f.passwords["robot$library+scoped"] = "robot-secret"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "name": "robot$library+scoped", "secret": "robot-secret"})
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (f *fakeHarbor) principalsOf(path string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.principals[path]
+}
+
+func TestScopedSession(t *testing.T) {
+	harbor := &fakeHarbor{
+		passwords:  map[string]string{"admin": "Harbor12345"},
+		principals: map[string][]string{},
+	}
+	server := httptest.NewServer(harbor)
+	defer server.Close()
+
+	config := rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Session = true
+	cs, err := NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	scoped, err := cs.NewScoped(ctx, "library", &ScopedOptions{TTL: time.Hour, Name: "scoped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principals := harbor.principalsOf("/api/v2.0/robots"); len(principals) != 1 || principals[0] != "admin" {
+		t.Errorf("expected the robot account to be created by the admin, got %v", principals)
+	}
+
+	if _, err := scoped.System.SystemInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.System.SystemInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	principals := harbor.principalsOf("/api/v2.0/systeminfo")
+	if len(principals) < 2 || principals[len(principals)-2] != "robot$library+scoped" || principals[len(principals)-1] != "admin" {
+		t.Errorf("expected the scoped client set to authenticate as the robot account, got %v", principals)
+	}
+
+	if err := scoped.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if principals := harbor.principalsOf("/api/v2.0/robots/1"); len(principals) != 1 || principals[0] != "admin" {
+		t.Errorf("expected the robot account to be deleted by the admin, got %v", principals)
+	}
+	if _, err := scoped.System.SystemInfo(ctx); err == nil {
+		t.Error("a released client set must not log in again")
+	}
+	if _, err := cs.System.SystemInfo(ctx); err != nil {
+		t.Errorf("releasing the scoped client set must not log the parent out, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package controller
+
+import (
+	"context"
+	flowcontrol2 "github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"k8s.io/klog"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWorkers        = 1
+	DefaultInitialBackoff = 5 * time.Millisecond
+	DefaultMaxBackoff     = 1000 * time.Second
+)
+
+// Result tells the controller whether a key should be reconciled again.
+type Result struct {
+	// Requeue requeues the key with the rate limited backoff.
+	Requeue bool
+	// RequeueAfter requeues the key after the given duration, it takes precedence over Requeue.
+	RequeueAfter time.Duration
+}
+
+// Reconciler drives the resource identified by key towards its desired state.
+// Returning an error requeues the key with the rate limited backoff.
+type Reconciler interface {
+	Reconcile(ctx context.Context, key string) (Result, error)
+}
+
+// ReconcilerFunc is a function implementing Reconciler.
+type ReconcilerFunc func(ctx context.Context, key string) (Result, error)
+
+func (f ReconcilerFunc) Reconcile(ctx context.Context, key string) (Result, error) {
+	return f(ctx, key)
+}
+
+// Lister lists the keys of all the resources the controller reconciles.
+type Lister func(ctx context.Context) ([]string, error)
+
+// Options holds the settings of a Controller.
+type Options struct {
+	// Workers is the number of keys reconciled concurrently, DefaultWorkers if zero.
+	Workers int
+	// Lister is called when the controller starts and on every resync to enqueue all the keys.
+	Lister Lister
+	// ResyncPeriod is the interval the Lister is called at, resync is disabled if zero.
+	ResyncPeriod time.Duration
+	// RateLimiter limits the overall rate of reconciliations, e.g. to protect Harbor
+	// when the reconciler issues API calls. Optional.
+	RateLimiter flowcontrol2.RateLimiter
+	// InitialBackoff and MaxBackoff bound the per key requeue backoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Controller runs a pool of workers reconciling the keys of a work queue.
+type Controller struct {
+	name       string
+	reconciler Reconciler
+	options    Options
+	queue      *Queue
+}
+
+// New creates a controller, name is used in log messages.
+func New(name string, reconciler Reconciler, options Options) *Controller {
+	if options.Workers <= 0 {
+		options.Workers = DefaultWorkers
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DefaultInitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DefaultMaxBackoff
+	}
+	return &Controller{
+		name:       name,
+		reconciler: reconciler,
+		options:    options,
+		queue:      NewQueue(options.InitialBackoff, options.MaxBackoff),
+	}
+}
+
+// Enqueue adds a key to reconcile, e.g. from a webhook event handler.
+func (c *Controller) Enqueue(key string) {
+	c.queue.Add(key)
+}
+
+// Queue returns the work queue of the controller.
+func (c *Controller) Queue() *Queue {
+	return c.queue
+}
+
+// Run starts the workers and the resync loop and blocks until ctx is done. The keys
+// being reconciled are completed before Run returns. The reconciler and the Lister are
+// called with flowcontrol.PriorityBatch, so that a priority rate limiter shared with
+// interactive calls serves them last, unless ctx has a priority.
+func (c *Controller) Run(ctx context.Context) {
+	if _, ok := flowcontrol2.PriorityFrom(ctx); !ok {
+		ctx = flowcontrol2.WithPriority(ctx, flowcontrol2.PriorityBatch)
+	}
+	klog.V(2).Infof("Starting controller %s with %d workers", c.name, c.options.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < c.options.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c.processNextKey(ctx) {
+			}
+		}()
+	}
+	if c.options.Lister != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.resync(ctx)
+		}()
+	}
+
+	<-ctx.Done()
+	klog.V(2).Infof("Shutting down controller %s", c.name)
+	c.queue.ShutDown()
+	wg.Wait()
+}
+
+// resync enqueues all the listed keys now and then every ResyncPeriod.
+func (c *Controller) resync(ctx context.Context) {
+	for {
+		keys, err := c.options.Lister(ctx)
+		if err != nil {
+			klog.Errorf("Controller %s failed to list keys: %v", c.name, err)
+		}
+		for _, key := range keys {
+			c.queue.Add(key)
+		}
+		if c.options.ResyncPeriod <= 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options.ResyncPeriod):
+		}
+	}
+}
+
+// processNextKey reconciles the next key of the queue, it returns false once the
+// queue is shut down.
+func (c *Controller) processNextKey(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if c.options.RateLimiter != nil {
+		if err := c.options.RateLimiter.Wait(ctx); err != nil {
+			// the controller is stopping, the key is reconciled on the next start
+			c.queue.Add(key)
+			return true
+		}
+	}
+	result, err := c.reconciler.Reconcile(ctx, key)
+	switch {
+	case err != nil:
+		klog.V(2).Infof("Controller %s failed to reconcile %q, requeuing: %v", c.name, key, err)
+		c.queue.AddRateLimited(key)
+	case result.RequeueAfter > 0:
+		c.queue.Forget(key)
+		c.queue.AddAfter(key, result.RequeueAfter)
+	case result.Requeue:
+		c.queue.AddRateLimited(key)
+	default:
+		c.queue.Forget(key)
+	}
+	return true
+}
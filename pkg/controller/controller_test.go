@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestControllerRequeuesOnError(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	done := make(chan struct{})
+	reconciler := ReconcilerFunc(func(ctx context.Context, key string) (Result, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[key]++
+		if attempts[key] < 3 {
+			return Result{}, fmt.Errorf("attempt %d failed", attempts[key])
+		}
+		close(done)
+		return Result{}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	c := New("test", reconciler, Options{
+		Workers: 2,
+		Lister: func(ctx context.Context) ([]string, error) {
+			return []string{"library"}, nil
+		},
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+	stopped := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(stopped)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("key was not reconciled successfully")
+	}
+	cancel()
+	<-stopped
+	if c.Queue().Backoff("library") != 0 {
+		t.Errorf("expected backoff to be reset after success")
+	}
+}
+
+func TestSplitRepositoryKey(t *testing.T) {
+	project, repository := SplitRepositoryKey("library/nginx/base")
+	if project != "library" || repository != "nginx/base" {
+		t.Errorf("unexpected split: %s %s", project, repository)
+	}
+}
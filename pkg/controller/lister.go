@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package controller
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+	"strings"
+)
+
+// listPageSize is the page size used by the listers.
+const listPageSize = 100
+
+// ProjectLister lists the names of all the projects as keys.
+func ProjectLister(projects *project2.ProjectsV2Client) Lister {
+	return func(ctx context.Context) ([]string, error) {
+		var keys []string
+		for page := int64(1); ctx.Err() == nil; page++ {
+			results, err := projects.List(ctx, &model.Query{Page: page, PageSize: listPageSize})
+			if err != nil {
+				return keys, err
+			}
+			for _, p := range *results {
+				keys = append(keys, p.Name)
+			}
+			if len(*results) < listPageSize {
+				break
+			}
+		}
+		return keys, ctx.Err()
+	}
+}
+
+// RepositoryLister lists the repositories of a project as "<project>/<repository>" keys.
+func RepositoryLister(projects *project2.ProjectsV2Client, project string) Lister {
+	return func(ctx context.Context) ([]string, error) {
+		var keys []string
+		for page := int64(1); ctx.Err() == nil; page++ {
+			results, err := projects.Repositories(project).List(ctx, &model.Query{Page: page, PageSize: listPageSize})
+			if err != nil {
+				return keys, err
+			}
+			for _, r := range *results {
+				// the repository name returned by Harbor is already prefixed with the project name
+				keys = append(keys, r.Name)
+			}
+			if len(*results) < listPageSize {
+				break
+			}
+		}
+		return keys, ctx.Err()
+	}
+}
+
+// SplitRepositoryKey splits a "<project>/<repository>" key.
+func SplitRepositoryKey(key string) (project, repository string) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package controller
+
+import (
+	clock2 "github.com/hujianxiong/go-harbor/pkg/rest/util/clock"
+	flowcontrol2 "github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"sync"
+	"time"
+)
+
+// Queue is a work queue of resource keys. A key waiting in the queue is only queued
+// once, and a key is never processed by two workers at the same time: a key added
+// while it is being processed is queued again when Done is called.
+type Queue struct {
+	cond *sync.Cond
+	// queue holds the keys in processing order
+	queue []string
+	// dirty holds the keys that need to be processed
+	dirty map[string]struct{}
+	// processing holds the keys currently processed by a worker
+	processing   map[string]struct{}
+	shuttingDown bool
+	stopCh       chan struct{}
+
+	clock   clock2.Clock
+	backoff *flowcontrol2.Backoff
+}
+
+// NewQueue creates a queue whose rate limited requeues back off exponentially from
+// initialBackoff up to maxBackoff per key.
+func NewQueue(initialBackoff, maxBackoff time.Duration) *Queue {
+	return newQueue(clock2.RealClock{}, initialBackoff, maxBackoff)
+}
+
+func newQueue(c clock2.Clock, initialBackoff, maxBackoff time.Duration) *Queue {
+	backoff := flowcontrol2.NewBackOff(initialBackoff, maxBackoff)
+	backoff.Clock = c
+	return &Queue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		dirty:      map[string]struct{}{},
+		processing: map[string]struct{}{},
+		stopCh:     make(chan struct{}),
+		clock:      c,
+		backoff:    backoff,
+	}
+}
+
+// Add marks key as needing processing.
+func (q *Queue) Add(key string) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[key]; ok {
+		return
+	}
+	q.dirty[key] = struct{}{}
+	if _, ok := q.processing[key]; ok {
+		return
+	}
+	q.queue = append(q.queue, key)
+	q.cond.Signal()
+}
+
+// AddAfter adds key to the queue once the given delay has passed.
+func (q *Queue) AddAfter(key string, delay time.Duration) {
+	if delay <= 0 {
+		q.Add(key)
+		return
+	}
+	go func() {
+		select {
+		case <-q.clock.After(delay):
+			q.Add(key)
+		case <-q.stopCh:
+		}
+	}()
+}
+
+// AddRateLimited adds key to the queue after its backoff delay, the delay doubles
+// every time the key is requeued until Forget is called.
+func (q *Queue) AddRateLimited(key string) {
+	q.backoff.Next(key, q.clock.Now())
+	q.AddAfter(key, q.backoff.Get(key))
+}
+
+// Forget resets the backoff of key, it should be called once key has been
+// processed successfully.
+func (q *Queue) Forget(key string) {
+	q.backoff.Reset(key)
+}
+
+// Backoff returns the current backoff delay of key.
+func (q *Queue) Backoff(key string) time.Duration {
+	return q.backoff.Get(key)
+}
+
+// Get blocks until a key can be processed. The caller must call Done with the key
+// once it has been processed. If shutdown is true the caller should exit.
+func (q *Queue) Get() (key string, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.queue) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		return "", true
+	}
+	key, q.queue = q.queue[0], q.queue[1:]
+	q.processing[key] = struct{}{}
+	delete(q.dirty, key)
+	return key, false
+}
+
+// Done marks key as done processing, if it was added again in the meantime it is
+// queued for processing.
+func (q *Queue) Done(key string) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, key)
+	if _, ok := q.dirty[key]; ok {
+		q.queue = append(q.queue, key)
+		q.cond.Signal()
+	}
+}
+
+// Len returns the number of keys waiting to be processed.
+func (q *Queue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.queue)
+}
+
+// ShutDown makes Get return shutdown once the queue is drained and drops the
+// pending delayed additions.
+func (q *Queue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	q.shuttingDown = true
+	close(q.stopCh)
+	q.cond.Broadcast()
+}
+
+// ShuttingDown returns true if ShutDown has been called.
+func (q *Queue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package controller
+
+import (
+	clock2 "github.com/hujianxiong/go-harbor/pkg/rest/util/clock"
+	"testing"
+	"time"
+)
+
+func TestQueueDeduplicates(t *testing.T) {
+	q := NewQueue(time.Millisecond, time.Second)
+	q.Add("a")
+	q.Add("b")
+	q.Add("a")
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 keys, got %d", q.Len())
+	}
+	key, _ := q.Get()
+	if key != "a" {
+		t.Fatalf("expected key a, got %s", key)
+	}
+	// adding a key being processed queues it again once done
+	q.Add("a")
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 key while a is processed, got %d", q.Len())
+	}
+	q.Done("a")
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 keys after done, got %d", q.Len())
+	}
+}
+
+func TestQueueShutDown(t *testing.T) {
+	q := NewQueue(time.Millisecond, time.Second)
+	q.Add("a")
+	q.ShutDown()
+	q.Add("b")
+	if key, shutdown := q.Get(); shutdown || key != "a" {
+		t.Fatalf("expected pending key a to be drained, got %q %v", key, shutdown)
+	}
+	if _, shutdown := q.Get(); !shutdown {
+		t.Fatalf("expected shutdown")
+	}
+}
+
+func TestQueueAddRateLimited(t *testing.T) {
+	fc := clock2.NewFakeClock(time.Now())
+	q := newQueue(fc, time.Second, 4*time.Second)
+	q.AddRateLimited("a")
+	if q.Backoff("a") != time.Second {
+		t.Fatalf("expected 1s backoff, got %v", q.Backoff("a"))
+	}
+	for !fc.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected key to wait for its backoff")
+	}
+	fc.Step(time.Second)
+	for i := 0; i < 100 && q.Len() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected key to be added after its backoff")
+	}
+	q.AddRateLimited("a")
+	if q.Backoff("a") != 2*time.Second {
+		t.Fatalf("expected 2s backoff, got %v", q.Backoff("a"))
+	}
+	q.Forget("a")
+	if q.Backoff("a") != 0 {
+		t.Fatalf("expected backoff to be reset, got %v", q.Backoff("a"))
+	}
+	q.ShutDown()
+}
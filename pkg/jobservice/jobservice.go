@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package jobservice
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// JobServiceInterface holds the methods of the job service dashboard APIs.
+type JobServiceInterface interface {
+	ListQueues(ctx context.Context) (results *[]model.JobQueue, err error)
+	PauseQueue(ctx context.Context, jobType string) (err error)
+	ResumeQueue(ctx context.Context, jobType string) (err error)
+	StopQueue(ctx context.Context, jobType string) (err error)
+	StopAll(ctx context.Context) (err error)
+	ListPools(ctx context.Context) (results *[]model.WorkerPool, err error)
+	ListWorkers(ctx context.Context, poolID string) (results *[]model.Worker, err error)
+	StopJob(ctx context.Context, jobID string) (err error)
+	GetJobLog(ctx context.Context, jobID string) (log []byte, err error)
+	ListSchedules(ctx context.Context, query *model.Query) (results *[]model.ScheduleTask, err error)
+	SchedulerPaused(ctx context.Context, jobType string) (paused bool, err error)
+}
+
+// JobServiceClient is used to monitor and drain the job service, it requires the system
+// admin role.
+type JobServiceClient struct {
+	restClient rest2.Interface
+}
+
+func NewJobServiceClient(restClient *rest2.Config) (*JobServiceClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &JobServiceClient{restClient: client}, nil
+}
+
+// ListQueues lists the job queues with their number of pending jobs.
+func (j *JobServiceClient) ListQueues(ctx context.Context) (results *[]model.JobQueue, err error) {
+	results = &[]model.JobQueue{}
+	err = j.restClient.List().
+		Resource("jobservice").
+		Suffix("queues").
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// PauseQueue stops dispatching the pending jobs of a type, model.JobTypeAll pausing all
+// the queues.
+func (j *JobServiceClient) PauseQueue(ctx context.Context, jobType string) (err error) {
+	return j.queueAction(ctx, jobType, model.JobActionPause)
+}
+
+// ResumeQueue resumes dispatching the pending jobs of a type.
+func (j *JobServiceClient) ResumeQueue(ctx context.Context, jobType string) (err error) {
+	return j.queueAction(ctx, jobType, model.JobActionResume)
+}
+
+// StopQueue stops the running jobs of a type and drops its pending ones.
+func (j *JobServiceClient) StopQueue(ctx context.Context, jobType string) (err error) {
+	return j.queueAction(ctx, jobType, model.JobActionStop)
+}
+
+// StopAll stops all the running jobs and drops all the pending ones.
+func (j *JobServiceClient) StopAll(ctx context.Context) (err error) {
+	return j.queueAction(ctx, model.JobTypeAll, model.JobActionStop)
+}
+
+func (j *JobServiceClient) queueAction(ctx context.Context, jobType, action string) error {
+	return j.restClient.Put().
+		Resource("jobservice").
+		Suffix("queues", jobType).
+		Body(&model.JobAction{Action: action}).
+		Do(ctx).
+		Error()
+}
+
+// ListPools lists the worker pools of the job service.
+func (j *JobServiceClient) ListPools(ctx context.Context) (results *[]model.WorkerPool, err error) {
+	results = &[]model.WorkerPool{}
+	err = j.restClient.List().
+		Resource("jobservice").
+		Suffix("pools").
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// ListWorkers lists the workers of a pool and the jobs they are running, poolID "all"
+// listing the workers of every pool.
+func (j *JobServiceClient) ListWorkers(ctx context.Context, poolID string) (results *[]model.Worker, err error) {
+	results = &[]model.Worker{}
+	err = j.restClient.List().
+		Resource("jobservice").
+		Suffix("pools", poolID, "workers").
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// StopJob stops a running job.
+func (j *JobServiceClient) StopJob(ctx context.Context, jobID string) (err error) {
+	return j.restClient.Put().
+		Resource("jobservice").
+		Suffix("jobs", jobID).
+		Body(&model.JobAction{Action: model.JobActionStop}).
+		Do(ctx).
+		Error()
+}
+
+// GetJobLog gets the log of a job.
+func (j *JobServiceClient) GetJobLog(ctx context.Context, jobID string) (log []byte, err error) {
+	log, _, err = j.restClient.Get().
+		Resource("jobservice").
+		Suffix("jobs", jobID, "log").
+		DoRaw(ctx)
+	return
+}
+
+// ListSchedules lists the scheduled tasks, e.g. the garbage collection and the scan all.
+func (j *JobServiceClient) ListSchedules(ctx context.Context, query *model.Query) (results *[]model.ScheduleTask, err error) {
+	if query == nil {
+		query = &model.Query{}
+	}
+	results = &[]model.ScheduleTask{}
+	err = j.restClient.List().
+		Resource("schedules").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// SchedulerPaused tells whether the schedules of a job type are paused, model.JobTypeAll
+// telling whether they all are.
+func (j *JobServiceClient) SchedulerPaused(ctx context.Context, jobType string) (paused bool, err error) {
+	status := &model.SchedulerStatus{}
+	err = j.restClient.Get().
+		Resource("schedules").
+		Suffix(jobType, "paused").
+		Do(ctx).
+		Into(status)
+	return status.Paused, err
+}
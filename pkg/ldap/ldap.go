@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package ldap
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// LdapInterface holds the methods of the LDAP APIs.
+type LdapInterface interface {
+	Ping(ctx context.Context, conf *model.LdapConf) (result *model.LdapPingResult, err error)
+	SearchUsers(ctx context.Context, username string) (results *[]model.LdapUser, err error)
+	SearchGroups(ctx context.Context, query *model.LdapGroupSearchQuery) (results *[]model.UserGroup, err error)
+	ImportUsers(ctx context.Context, uids ...string) (err error)
+}
+
+// LdapClient is used to validate the LDAP integration and onboard directory users.
+type LdapClient struct {
+	restClient rest2.Interface
+}
+
+func NewLdapClient(restClient *rest2.Config) (*LdapClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &LdapClient{restClient: client}, nil
+}
+
+// Ping checks the connection to the LDAP server with conf, or with the configuration of
+// Harbor if conf is nil. A failed connection is reported by the result, not as an error.
+func (l *LdapClient) Ping(ctx context.Context, conf *model.LdapConf) (result *model.LdapPingResult, err error) {
+	if conf == nil {
+		conf = &model.LdapConf{}
+	}
+	result = &model.LdapPingResult{}
+	err = l.restClient.Post().
+		Resource("ldap").
+		Suffix("ping").
+		Body(conf).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// SearchUsers searches the LDAP users by username, all users are returned if it is empty.
+func (l *LdapClient) SearchUsers(ctx context.Context, username string) (results *[]model.LdapUser, err error) {
+	results = &[]model.LdapUser{}
+	err = l.restClient.Get().
+		Resource("ldap").
+		Suffix("users", "search").
+		Param("username", username).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// SearchGroups searches the LDAP groups by name or DN.
+func (l *LdapClient) SearchGroups(ctx context.Context, query *model.LdapGroupSearchQuery) (results *[]model.UserGroup, err error) {
+	results = &[]model.UserGroup{}
+	err = l.restClient.Get().
+		Resource("ldap").
+		Suffix("groups", "search").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// ImportUsers imports the LDAP users with the given uids into Harbor. If some of them
+// can't be imported Harbor responds with 404 and the error lists the failed uids.
+func (l *LdapClient) ImportUsers(ctx context.Context, uids ...string) (err error) {
+	return l.restClient.Post().
+		Resource("ldap").
+		Suffix("users", "import").
+		Body(&model.LdapImportUsers{LdapUIDList: uids}).
+		Do(ctx).
+		Error()
+}
@@ -18,6 +18,8 @@ package model
 import (
 	"github.com/goharbor/harbor/src/controller/tag"
 	"github.com/goharbor/harbor/src/pkg/artifact"
+	"strings"
+	"time"
 )
 
 type Artifact struct {
@@ -25,7 +27,51 @@ type Artifact struct {
 	Tags          []*tag.Tag               `json:"tags"`           // the list of tags that attached to the artifact
 	AdditionLinks map[string]*AdditionLink `json:"addition_links"` // the resource link for build history(image), values.yaml(chart), dependency(chart), etc
 	Labels        []*Label                 `json:"labels"`
+	ScanOverview  map[string]*ScanOverview `json:"scan_overview,omitempty"` // the scan overview keyed by the report mime type
+	Accessories   []*Accessory             `json:"accessories,omitempty"`   // the signatures, SBOMs, etc attached to the artifact
+}
+
+// Artifact fields, to select with ListFields
+const (
+	ArtifactFieldID           = "id"
+	ArtifactFieldDigest       = "digest"
+	ArtifactFieldSize         = "size"
+	ArtifactFieldType         = "type"
+	ArtifactFieldPushTime     = "push_time"
+	ArtifactFieldPullTime     = "pull_time"
+	ArtifactFieldRepositoryID = "repository_id"
+	ArtifactFieldTags         = "tags"
+	ArtifactFieldLabels       = "labels"
+)
+
+// Accessory types
+const (
+	AccessoryTypeCosignSignature   = "signature.cosign"
+	AccessoryTypeNotationSignature = "signature.notation"
+	AccessoryTypeNydus             = "accelerator.nydus"
+	AccessoryTypeHarborSBOM        = "harbor.sbom"
+	AccessoryTypeSubject           = "subject.accessory"
+)
+
+// Accessory is an artifact attached to a subject artifact, e.g. a cosign signature
+type Accessory struct {
+	ID                int64     `json:"id"`
+	ArtifactID        int64     `json:"artifact_id"`
+	SubjectArtifactID int64     `json:"subject_artifact_id"`
+	Size              int64     `json:"size"`
+	Digest            string    `json:"digest"`
+	Type              string    `json:"type"`
+	Icon              string    `json:"icon,omitempty"`
+	CreationTime      time.Time `json:"creation_time"`
+	// SubjectArtifactDigest and SubjectArtifactRepo identify the subject artifact, they
+	// are only returned by Harbor 2.8 and later.
+	SubjectArtifactDigest string `json:"subject_artifact_digest,omitempty"`
+	SubjectArtifactRepo   string `json:"subject_artifact_repo,omitempty"`
+}
 
+// IsSignature returns true if the accessory is a signature of its subject artifact.
+func (a *Accessory) IsSignature() bool {
+	return strings.HasPrefix(a.Type, "signature.")
 }
 
 // AdditionLink is a link via that the addition can be fetched
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// harborTimeFormat is the time format of range values in the q query parameter
+const harborTimeFormat = "2006-01-02 15:04:05"
+
+// AuditLog is an operation recorded by Harbor
+type AuditLog struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`      // the user who did the operation
+	Resource     string    `json:"resource"`      // the resource, e.g. library/nginx:1.19
+	ResourceType string    `json:"resource_type"` // the type of the resource, e.g. artifact
+	Operation    string    `json:"operation"`     // the operation, e.g. create, delete or pull
+	OpTime       time.Time `json:"op_time"`
+}
+
+// AuditLogList is a page of audit logs
+type AuditLogList struct {
+	Total int64       `json:"total"` // the total number of audit logs matching the query
+	Items []*AuditLog `json:"items"`
+}
+
+// AuditLogQuery holds the filters of audit log listing, empty filters are ignored
+type AuditLogQuery struct {
+	Page     int64
+	PageSize int64
+
+	Operation    string    // exact match, e.g. create, delete or pull
+	Resource     string    // fuzzy match
+	ResourceType string    // exact match, e.g. artifact, repository or project
+	Username     string    // exact match
+	From         time.Time // the operations done after From
+	To           time.Time // the operations done before To
+}
+
+// Query renders the filters into the Harbor q query parameter, the values are escaped
+// so they can't change the filter.
+func (a *AuditLogQuery) Query() (*Query, error) {
+	b := NewQ()
+	if a.Operation != "" {
+		b.Exact("operation", a.Operation)
+	}
+	if a.Resource != "" {
+		b.Fuzzy("resource", a.Resource)
+	}
+	if a.ResourceType != "" {
+		b.Exact("resource_type", a.ResourceType)
+	}
+	if a.Username != "" {
+		b.Exact("username", a.Username)
+	}
+	if !a.From.IsZero() || !a.To.IsZero() {
+		var from, to interface{}
+		if !a.From.IsZero() {
+			from = a.From
+		}
+		if !a.To.IsZero() {
+			to = a.To
+		}
+		b.Range("op_time", from, to)
+	}
+	q, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("audit log query error: %v", err)
+	}
+	return &Query{
+		Page:     a.Page,
+		PageSize: a.PageSize,
+		Q:        q,
+	}, nil
+}
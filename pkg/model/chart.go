@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// ChartInfo keeps the information of the chart
+type ChartInfo struct {
+	Name          string    `json:"name"`
+	TotalVersions uint32    `json:"total_versions"`
+	LatestVersion string    `json:"latest_version"`
+	Created       time.Time `json:"created"`
+	Updated       time.Time `json:"updated"`
+	Icon          string    `json:"icon"`
+	Home          string    `json:"home"`
+	Deprecated    bool      `json:"deprecated"`
+}
+
+// ChartMetadata is the Chart.yaml content of a chart version
+type ChartMetadata struct {
+	Name        string            `json:"name"`
+	Home        string            `json:"home,omitempty"`
+	Sources     []string          `json:"sources,omitempty"`
+	Version     string            `json:"version"`
+	Description string            `json:"description,omitempty"`
+	Keywords    []string          `json:"keywords,omitempty"`
+	Icon        string            `json:"icon,omitempty"`
+	APIVersion  string            `json:"apiVersion,omitempty"`
+	AppVersion  string            `json:"appVersion,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Type        string            `json:"type,omitempty"`
+}
+
+// ChartVersion is a version of a chart as listed by the chart repository
+type ChartVersion struct {
+	ChartMetadata
+	URLs    []string  `json:"urls"`
+	Created time.Time `json:"created,omitempty"`
+	Removed bool      `json:"removed,omitempty"`
+	Digest  string    `json:"digest,omitempty"`
+	Labels  []*Label  `json:"labels"`
+}
+
+// ChartDependency is a dependency declared by a chart version
+type ChartDependency struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Repository string `json:"repository"`
+}
+
+// ChartVersionDetails keeps the detailed data info of the chart version
+type ChartVersionDetails struct {
+	Metadata     *ChartVersion          `json:"metadata"`
+	Dependencies []*ChartDependency     `json:"dependencies"`
+	Values       map[string]interface{} `json:"values"`
+	Files        map[string]string      `json:"files"`
+	Security     *ChartSecurityReport   `json:"security"`
+	Labels       []*Label               `json:"labels"`
+}
+
+// ChartSecurityReport keeps the info related with security
+type ChartSecurityReport struct {
+	Signature *ChartSignature `json:"signature"`
+}
+
+// ChartSignature used to indicate the chart is signed or not
+type ChartSignature struct {
+	Signed   bool   `json:"signed"`
+	Provfile string `json:"prov_file"`
+}
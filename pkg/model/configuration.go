@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// Authentication modes
+const (
+	AuthModeDB   = "db_auth"
+	AuthModeLDAP = "ldap_auth"
+	AuthModeUAA  = "uaa_auth"
+	AuthModeHTTP = "http_auth"
+	AuthModeOIDC = "oidc_auth"
+)
+
+// Project creation restrictions
+const (
+	ProjectCreationRestrictionAdminOnly = "adminonly"
+	ProjectCreationRestrictionEveryone  = "everyone"
+)
+
+// Configurations holds the system settings to update, nil fields are left unchanged
+type Configurations struct {
+	AuthMode                   *string `json:"auth_mode,omitempty"`
+	ProjectCreationRestriction *string `json:"project_creation_restriction,omitempty"` // adminonly or everyone
+	ReadOnly                   *bool   `json:"read_only,omitempty"`
+	SelfRegistration           *bool   `json:"self_registration,omitempty"`
+	TokenExpiration            *int64  `json:"token_expiration,omitempty"`     // the expiration of the token in minutes
+	RobotTokenDuration         *int64  `json:"robot_token_duration,omitempty"` // the default expiration of robot accounts in days
+	RobotNamePrefix            *string `json:"robot_name_prefix,omitempty"`
+	NotificationEnable         *bool   `json:"notification_enable,omitempty"`
+	QuotaPerProjectEnable      *bool   `json:"quota_per_project_enable,omitempty"`
+	StoragePerProject          *int64  `json:"storage_per_project,omitempty"` // the default storage quota of new projects in bytes, -1 is unlimited
+
+	LdapURL                      *string `json:"ldap_url,omitempty"`
+	LdapBaseDN                   *string `json:"ldap_base_dn,omitempty"`
+	LdapFilter                   *string `json:"ldap_filter,omitempty"`
+	LdapScope                    *int64  `json:"ldap_scope,omitempty"`
+	LdapSearchDN                 *string `json:"ldap_search_dn,omitempty"`
+	LdapSearchPassword           *string `json:"ldap_search_password,omitempty"`
+	LdapTimeout                  *int64  `json:"ldap_timeout,omitempty"`
+	LdapUID                      *string `json:"ldap_uid,omitempty"`
+	LdapVerifyCert               *bool   `json:"ldap_verify_cert,omitempty"`
+	LdapGroupAdminDN             *string `json:"ldap_group_admin_dn,omitempty"`
+	LdapGroupAttributeName       *string `json:"ldap_group_attribute_name,omitempty"`
+	LdapGroupBaseDN              *string `json:"ldap_group_base_dn,omitempty"`
+	LdapGroupMembershipAttribute *string `json:"ldap_group_membership_attribute,omitempty"`
+	LdapGroupSearchFilter        *string `json:"ldap_group_search_filter,omitempty"`
+	LdapGroupSearchScope         *int64  `json:"ldap_group_search_scope,omitempty"`
+
+	OIDCName         *string `json:"oidc_name,omitempty"`
+	OIDCEndpoint     *string `json:"oidc_endpoint,omitempty"`
+	OIDCClientID     *string `json:"oidc_client_id,omitempty"`
+	OIDCClientSecret *string `json:"oidc_client_secret,omitempty"`
+	OIDCGroupsClaim  *string `json:"oidc_groups_claim,omitempty"`
+	OIDCAdminGroup   *string `json:"oidc_admin_group,omitempty"`
+	OIDCScope        *string `json:"oidc_scope,omitempty"`
+	OIDCUserClaim    *string `json:"oidc_user_claim,omitempty"`
+	OIDCVerifyCert   *bool   `json:"oidc_verify_cert,omitempty"`
+	OIDCAutoOnboard  *bool   `json:"oidc_auto_onboard,omitempty"`
+
+	HTTPAuthProxyEndpoint            *string `json:"http_authproxy_endpoint,omitempty"`
+	HTTPAuthProxyTokenReviewEndpoint *string `json:"http_authproxy_tokenreview_endpoint,omitempty"`
+	HTTPAuthProxyAdminGroups         *string `json:"http_authproxy_admin_groups,omitempty"`
+	HTTPAuthProxyVerifyCert          *bool   `json:"http_authproxy_verify_cert,omitempty"`
+	HTTPAuthProxySkipSearch          *bool   `json:"http_authproxy_skip_search,omitempty"`
+	HTTPAuthProxyServerCertificate   *string `json:"http_authproxy_server_certificate,omitempty"`
+
+	UAAClientID     *string `json:"uaa_client_id,omitempty"`
+	UAAClientSecret *string `json:"uaa_client_secret,omitempty"`
+	UAAEndpoint     *string `json:"uaa_endpoint,omitempty"`
+	UAAVerifyCert   *bool   `json:"uaa_verify_cert,omitempty"`
+}
+
+// StringConfigItem is a string setting as returned by Harbor
+type StringConfigItem struct {
+	Value    string `json:"value"`
+	Editable bool   `json:"editable"`
+}
+
+// BoolConfigItem is a boolean setting as returned by Harbor
+type BoolConfigItem struct {
+	Value    bool `json:"value"`
+	Editable bool `json:"editable"`
+}
+
+// IntegerConfigItem is an integer setting as returned by Harbor
+type IntegerConfigItem struct {
+	Value    int64 `json:"value"`
+	Editable bool  `json:"editable"`
+}
+
+// ConfigurationsResponse holds the system settings as returned by Harbor, secrets
+// such as the LDAP search password are never returned
+type ConfigurationsResponse struct {
+	AuthMode                   *StringConfigItem  `json:"auth_mode,omitempty"`
+	ProjectCreationRestriction *StringConfigItem  `json:"project_creation_restriction,omitempty"`
+	ReadOnly                   *BoolConfigItem    `json:"read_only,omitempty"`
+	SelfRegistration           *BoolConfigItem    `json:"self_registration,omitempty"`
+	TokenExpiration            *IntegerConfigItem `json:"token_expiration,omitempty"`
+	RobotTokenDuration         *IntegerConfigItem `json:"robot_token_duration,omitempty"`
+	RobotNamePrefix            *StringConfigItem  `json:"robot_name_prefix,omitempty"`
+	NotificationEnable         *BoolConfigItem    `json:"notification_enable,omitempty"`
+	QuotaPerProjectEnable      *BoolConfigItem    `json:"quota_per_project_enable,omitempty"`
+	StoragePerProject          *IntegerConfigItem `json:"storage_per_project,omitempty"`
+
+	LdapURL                      *StringConfigItem  `json:"ldap_url,omitempty"`
+	LdapBaseDN                   *StringConfigItem  `json:"ldap_base_dn,omitempty"`
+	LdapFilter                   *StringConfigItem  `json:"ldap_filter,omitempty"`
+	LdapScope                    *IntegerConfigItem `json:"ldap_scope,omitempty"`
+	LdapSearchDN                 *StringConfigItem  `json:"ldap_search_dn,omitempty"`
+	LdapTimeout                  *IntegerConfigItem `json:"ldap_timeout,omitempty"`
+	LdapUID                      *StringConfigItem  `json:"ldap_uid,omitempty"`
+	LdapVerifyCert               *BoolConfigItem    `json:"ldap_verify_cert,omitempty"`
+	LdapGroupAdminDN             *StringConfigItem  `json:"ldap_group_admin_dn,omitempty"`
+	LdapGroupAttributeName       *StringConfigItem  `json:"ldap_group_attribute_name,omitempty"`
+	LdapGroupBaseDN              *StringConfigItem  `json:"ldap_group_base_dn,omitempty"`
+	LdapGroupMembershipAttribute *StringConfigItem  `json:"ldap_group_membership_attribute,omitempty"`
+	LdapGroupSearchFilter        *StringConfigItem  `json:"ldap_group_search_filter,omitempty"`
+	LdapGroupSearchScope         *IntegerConfigItem `json:"ldap_group_search_scope,omitempty"`
+
+	OIDCName        *StringConfigItem `json:"oidc_name,omitempty"`
+	OIDCEndpoint    *StringConfigItem `json:"oidc_endpoint,omitempty"`
+	OIDCClientID    *StringConfigItem `json:"oidc_client_id,omitempty"`
+	OIDCGroupsClaim *StringConfigItem `json:"oidc_groups_claim,omitempty"`
+	OIDCAdminGroup  *StringConfigItem `json:"oidc_admin_group,omitempty"`
+	OIDCScope       *StringConfigItem `json:"oidc_scope,omitempty"`
+	OIDCUserClaim   *StringConfigItem `json:"oidc_user_claim,omitempty"`
+	OIDCVerifyCert  *BoolConfigItem   `json:"oidc_verify_cert,omitempty"`
+	OIDCAutoOnboard *BoolConfigItem   `json:"oidc_auto_onboard,omitempty"`
+
+	HTTPAuthProxyEndpoint            *StringConfigItem `json:"http_authproxy_endpoint,omitempty"`
+	HTTPAuthProxyTokenReviewEndpoint *StringConfigItem `json:"http_authproxy_tokenreview_endpoint,omitempty"`
+	HTTPAuthProxyAdminGroups         *StringConfigItem `json:"http_authproxy_admin_groups,omitempty"`
+	HTTPAuthProxyVerifyCert          *BoolConfigItem   `json:"http_authproxy_verify_cert,omitempty"`
+	HTTPAuthProxySkipSearch          *BoolConfigItem   `json:"http_authproxy_skip_search,omitempty"`
+	HTTPAuthProxyServerCertificate   *StringConfigItem `json:"http_authproxy_server_certificate,omitempty"`
+
+	UAAClientID   *StringConfigItem `json:"uaa_client_id,omitempty"`
+	UAAEndpoint   *StringConfigItem `json:"uaa_endpoint,omitempty"`
+	UAAVerifyCert *BoolConfigItem   `json:"uaa_verify_cert,omitempty"`
+}
+
+// OIDCPing is the OIDC endpoint to check before saving it in the configurations
+type OIDCPing struct {
+	URL        string `json:"url"`
+	VerifyCert bool   `json:"verify_cert"`
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// CVEAllowlist is a list of CVEs ignored when checking the vulnerability severity of images
+// before they are pulled, either system wide or for a project
+type CVEAllowlist struct {
+	ID        int64 `json:"id,omitempty"`
+	ProjectID int64 `json:"project_id"`
+	// ExpiresAt is the unix time the allowlist expires at, nil if it never expires.
+	ExpiresAt    *int64             `json:"expires_at,omitempty"`
+	Items        []CVEAllowlistItem `json:"items"`
+	CreationTime time.Time          `json:"creation_time,omitempty"`
+	UpdateTime   time.Time          `json:"update_time,omitempty"`
+}
+
+// CVEAllowlistItem is a CVE of an allowlist, e.g. CVE-2020-1234
+type CVEAllowlistItem struct {
+	CVEID string `json:"cve_id"`
+}
+
+// NewCVEAllowlist returns an allowlist of the given CVEs, expiring at expiresAt unless it
+// is the zero time.
+func NewCVEAllowlist(expiresAt time.Time, cves ...string) *CVEAllowlist {
+	allowlist := &CVEAllowlist{Items: []CVEAllowlistItem{}}
+	if !expiresAt.IsZero() {
+		unix := expiresAt.Unix()
+		allowlist.ExpiresAt = &unix
+	}
+	for _, cve := range cves {
+		allowlist.Items = append(allowlist.Items, CVEAllowlistItem{CVEID: cve})
+	}
+	return allowlist
+}
+
+// Expired returns true if the allowlist expired at now, an expired allowlist is ignored.
+func (l *CVEAllowlist) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && now.Unix() >= *l.ExpiresAt
+}
+
+// Contains returns true if the CVE is in the allowlist.
+func (l *CVEAllowlist) Contains(cve string) bool {
+	for _, item := range l.Items {
+		if item.CVEID == cve {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectMetadataReuseSysCVEAllowlist is the project metadata telling whether the project
+// uses the system CVE allowlist instead of its own
+const ProjectMetadataReuseSysCVEAllowlist = "reuse_sys_cve_allowlist"
+
+// ProjectCVEAllowlist is the CVE allowlist in effect for a project
+type ProjectCVEAllowlist struct {
+	// ReuseSystem is true if the project uses the system allowlist, Allowlist is then
+	// the project's own allowlist which is ignored.
+	ReuseSystem bool          `json:"reuse_system"`
+	Allowlist   *CVEAllowlist `json:"allowlist"`
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// Statuses of the executions and tasks of the Harbor task manager, used by preheat
+// and other asynchronous jobs
+const (
+	TaskStatusPending = "Pending"
+	TaskStatusRunning = "Running"
+	TaskStatusStopped = "Stopped"
+	TaskStatusError   = "Error"
+	TaskStatusSuccess = "Success"
+)
+
+// Execution is a run of a policy, made of one or more tasks
+type Execution struct {
+	ID            int64                  `json:"id"`
+	VendorType    string                 `json:"vendor_type"`
+	VendorID      int64                  `json:"vendor_id"`
+	Status        string                 `json:"status"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	Metrics       *ExecutionMetrics      `json:"metrics,omitempty"`
+	Trigger       string                 `json:"trigger"`
+	ExtraAttrs    map[string]interface{} `json:"extra_attrs,omitempty"`
+	StartTime     string                 `json:"start_time"`
+	EndTime       string                 `json:"end_time,omitempty"`
+}
+
+// Done returns true if the execution is over, whether it succeeded or not.
+func (e *Execution) Done() bool {
+	return e.Status == TaskStatusStopped || e.Status == TaskStatusError || e.Status == TaskStatusSuccess
+}
+
+// ExecutionMetrics counts the tasks of an execution by status
+type ExecutionMetrics struct {
+	TaskCount          int64 `json:"task_count"`
+	SuccessTaskCount   int64 `json:"success_task_count"`
+	ErrorTaskCount     int64 `json:"error_task_count"`
+	PendingTaskCount   int64 `json:"pending_task_count"`
+	RunningTaskCount   int64 `json:"running_task_count"`
+	ScheduledTaskCount int64 `json:"scheduled_task_count"`
+	StoppedTaskCount   int64 `json:"stopped_task_count"`
+}
+
+// Task is a job of an execution
+type Task struct {
+	ID            int64                  `json:"id"`
+	ExecutionID   int64                  `json:"execution_id"`
+	Status        string                 `json:"status"`
+	StatusMessage string                 `json:"status_message,omitempty"`
+	RunCount      int32                  `json:"run_count"`
+	ExtraAttrs    map[string]interface{} `json:"extra_attrs,omitempty"`
+	CreationTime  string                 `json:"creation_time"`
+	StartTime     string                 `json:"start_time"`
+	UpdateTime    string                 `json:"update_time"`
+	EndTime       string                 `json:"end_time,omitempty"`
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// GC schedule types
+const (
+	ScheduleTypeHourly = "Hourly"
+	ScheduleTypeDaily  = "Daily"
+	ScheduleTypeWeekly = "Weekly"
+	ScheduleTypeCustom = "Custom"
+	ScheduleTypeManual = "Manual"
+	ScheduleTypeNone   = "None"
+)
+
+// GCHistory is a run of the garbage collection, its status being one of the TaskStatus
+type GCHistory struct {
+	ID            int64     `json:"id"`
+	JobName       string    `json:"job_name"`
+	JobKind       string    `json:"job_kind"`
+	JobParameters string    `json:"job_parameters"`
+	Schedule      *Schedule `json:"schedule,omitempty"`
+	JobStatus     string    `json:"job_status"`
+	Deleted       bool      `json:"deleted"`
+	CreationTime  time.Time `json:"creation_time"`
+	UpdateTime    time.Time `json:"update_time"`
+}
+
+// Done returns true if the garbage collection is over, whether it succeeded or not.
+func (g *GCHistory) Done() bool {
+	return g.JobStatus == TaskStatusStopped || g.JobStatus == TaskStatusError || g.JobStatus == TaskStatusSuccess
+}
+
+// Schedule is when a system job runs, Cron being only used by the Custom type
+type Schedule struct {
+	Type string `json:"type"`
+	Cron string `json:"cron,omitempty"`
+}
+
+// GCSchedule is the schedule and the parameters of the garbage collection
+type GCSchedule struct {
+	Schedule   *Schedule              `json:"schedule"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// NewManualGC returns the schedule running a garbage collection once, right away,
+// deleting the untagged artifacts as well if deleteUntagged is set.
+func NewManualGC(deleteUntagged bool) *GCSchedule {
+	return &GCSchedule{
+		Schedule:   &Schedule{Type: ScheduleTypeManual},
+		Parameters: map[string]interface{}{"delete_untagged": deleteUntagged},
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "encoding/base64"
+
+// Icon is the icon of an artifact type, e.g. the Helm icon of charts
+type Icon struct {
+	ContentType string `json:"content-type"`
+	Content     string `json:"content"` // base64 encoded
+}
+
+// Data returns the decoded image of the icon.
+func (i *Icon) Data() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(i.Content)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Job queue actions
+const (
+	JobActionStop   = "stop"
+	JobActionPause  = "pause"
+	JobActionResume = "resume"
+)
+
+// JobTypeAll is the job type standing for all the job queues in queue actions
+const JobTypeAll = "all"
+
+// JobQueue is the queue of the pending jobs of a type in the job service
+type JobQueue struct {
+	JobType string `json:"job_type"`
+	Count   int64  `json:"count"`   // the number of pending jobs
+	Latency int64  `json:"latency"` // the waiting time of the oldest pending job, in seconds
+	Paused  bool   `json:"paused"`
+}
+
+// JobAction is the body of the job and queue actions
+type JobAction struct {
+	Action string `json:"action"`
+}
+
+// WorkerPool is a pool of workers of the job service
+type WorkerPool struct {
+	PID          int64     `json:"pid"`
+	WorkerPoolID string    `json:"worker_pool_id"`
+	StartAt      time.Time `json:"start_at"`
+	HeartbeatAt  time.Time `json:"heartbeat_at"`
+	Concurrency  int64     `json:"concurrency"`
+	Host         string    `json:"host"`
+}
+
+// Worker is a worker of the job service, JobID being empty if it is idle
+type Worker struct {
+	ID        string    `json:"id"`
+	PoolID    string    `json:"pool_id"`
+	JobName   string    `json:"job_name"`
+	JobID     string    `json:"job_id"`
+	StartAt   time.Time `json:"start_at"`
+	CheckIn   string    `json:"check_in"`
+	CheckInAt time.Time `json:"checkin_at"`
+}
+
+// ScheduleTask is a task scheduled by a cron in the job service, e.g. a garbage collection
+type ScheduleTask struct {
+	ID         int64     `json:"id"`
+	VendorType string    `json:"vendor_type"`
+	VendorID   int64     `json:"vendor_id"`
+	Cron       string    `json:"cron"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// SchedulerStatus tells whether the schedules of a job type are paused
+type SchedulerStatus struct {
+	Paused bool `json:"paused"`
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// LdapConf is the LDAP configuration to check with a ping, unset fields are taken from
+// the current configuration of Harbor.
+type LdapConf struct {
+	LdapURL               string `json:"ldap_url,omitempty"`
+	LdapSearchDN          string `json:"ldap_search_dn,omitempty"`
+	LdapSearchPassword    string `json:"ldap_search_password,omitempty"`
+	LdapBaseDN            string `json:"ldap_base_dn,omitempty"`
+	LdapFilter            string `json:"ldap_filter,omitempty"`
+	LdapUID               string `json:"ldap_uid,omitempty"`
+	LdapScope             int64  `json:"ldap_scope,omitempty"`
+	LdapConnectionTimeout int64  `json:"ldap_connection_timeout,omitempty"`
+	LdapVerifyCert        *bool  `json:"ldap_verify_cert,omitempty"`
+}
+
+// LdapPingResult is the result of an LDAP ping
+type LdapPingResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// LdapUser is a user found in the LDAP directory
+type LdapUser struct {
+	Username string `json:"username"`
+	Realname string `json:"realname"`
+	Email    string `json:"email"`
+}
+
+// LdapImportUsers holds the uids of the LDAP users to import into Harbor
+type LdapImportUsers struct {
+	LdapUIDList []string `json:"ldap_uid_list"`
+}
+
+// LdapGroupSearchQuery holds the parameters of LDAP group search, by name or by DN
+type LdapGroupSearchQuery struct {
+	GroupName string `json:"groupname,omitempty"`
+	GroupDN   string `json:"groupdn,omitempty"`
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "fmt"
+
+// Resource is a resource permissions grant access to
+type Resource string
+
+// Action is an action allowed on a resource
+type Action string
+
+// Resources of the project level permissions
+const (
+	ResourceRepository       Resource = "repository"
+	ResourceArtifact         Resource = "artifact"
+	ResourceArtifactLabel    Resource = "artifact-label"
+	ResourceTag              Resource = "tag"
+	ResourceScan             Resource = "scan"
+	ResourceSBOM             Resource = "sbom"
+	ResourceHelmChart        Resource = "helm-chart"
+	ResourceHelmChartVersion Resource = "helm-chart-version"
+	ResourceAccessory        Resource = "accessory"
+)
+
+// Actions
+const (
+	ActionPull   Action = "pull"
+	ActionPush   Action = "push"
+	ActionCreate Action = "create"
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionList   Action = "list"
+	ActionStop   Action = "stop"
+)
+
+// PermissionCatalog lists the access that can be granted at the system and project levels
+type PermissionCatalog struct {
+	System  []*Access `json:"system"`
+	Project []*Access `json:"project"`
+}
+
+// DefaultPermissionCatalog is the catalog of the project level permissions of the robot
+// accounts of Harbor 2.x, used to validate permissions against servers which don't expose
+// theirs. It doesn't list the system level permissions, which are not validated.
+var DefaultPermissionCatalog = &PermissionCatalog{
+	Project: []*Access{
+		{Resource: ResourceRepository, Action: ActionPull},
+		{Resource: ResourceRepository, Action: ActionPush},
+		{Resource: ResourceRepository, Action: ActionDelete},
+		{Resource: ResourceRepository, Action: ActionList},
+		{Resource: ResourceArtifact, Action: ActionRead},
+		{Resource: ResourceArtifact, Action: ActionList},
+		{Resource: ResourceArtifact, Action: ActionDelete},
+		{Resource: ResourceArtifactLabel, Action: ActionCreate},
+		{Resource: ResourceArtifactLabel, Action: ActionDelete},
+		{Resource: ResourceTag, Action: ActionCreate},
+		{Resource: ResourceTag, Action: ActionDelete},
+		{Resource: ResourceTag, Action: ActionList},
+		{Resource: ResourceScan, Action: ActionCreate},
+		{Resource: ResourceScan, Action: ActionRead},
+		{Resource: ResourceScan, Action: ActionStop},
+		{Resource: ResourceSBOM, Action: ActionCreate},
+		{Resource: ResourceSBOM, Action: ActionRead},
+		{Resource: ResourceSBOM, Action: ActionStop},
+		{Resource: ResourceHelmChart, Action: ActionRead},
+		{Resource: ResourceHelmChartVersion, Action: ActionCreate},
+		{Resource: ResourceHelmChartVersion, Action: ActionDelete},
+		{Resource: ResourceAccessory, Action: ActionList},
+	},
+}
+
+// Access returns the access of a level, RobotLevelSystem or RobotLevelProject.
+func (c *PermissionCatalog) Access(level string) []*Access {
+	if level == RobotLevelSystem {
+		return c.System
+	}
+	return c.Project
+}
+
+// Actions returns the actions allowed on a resource at a level, in the order of the catalog.
+func (c *PermissionCatalog) Actions(level string, resource Resource) []Action {
+	var actions []Action
+	for _, access := range c.Access(level) {
+		if access.Resource == resource {
+			actions = append(actions, access.Action)
+		}
+	}
+	return actions
+}
+
+// Resources returns the resources of a level, in the order of the catalog.
+func (c *PermissionCatalog) Resources(level string) []Resource {
+	var resources []Resource
+	seen := map[Resource]bool{}
+	for _, access := range c.Access(level) {
+		if !seen[access.Resource] {
+			seen[access.Resource] = true
+			resources = append(resources, access.Resource)
+		}
+	}
+	return resources
+}
+
+// Validate checks that every access granted by the permissions is listed in the catalog
+// for their level, the levels the catalog lists nothing for are not checked.
+func (c *PermissionCatalog) Validate(permissions []*RobotPermission) error {
+	for _, permission := range permissions {
+		var allowed []*Access
+		switch permission.Kind {
+		case RobotLevelSystem:
+			allowed = c.System
+		case RobotLevelProject:
+			allowed = c.Project
+		default:
+			return fmt.Errorf("invalid permission kind %q, expected %s or %s", permission.Kind, RobotLevelSystem, RobotLevelProject)
+		}
+		if len(allowed) == 0 {
+			continue
+		}
+		for _, access := range permission.Access {
+			if err := validateAccess(allowed, access); err != nil {
+				return fmt.Errorf("%s permission on %s: %v", permission.Kind, permission.Namespace, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateAccess(allowed []*Access, access *Access) error {
+	var actions []Action
+	for _, a := range allowed {
+		if a.Resource != access.Resource {
+			continue
+		}
+		if a.Action == access.Action {
+			return nil
+		}
+		actions = append(actions, a.Action)
+	}
+	if len(actions) == 0 {
+		return fmt.Errorf("unknown resource %q", access.Resource)
+	}
+	return fmt.Errorf("action %q is not allowed on %s, expected one of %v", access.Action, access.Resource, actions)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "encoding/json"
+
+// Preheat provider vendors
+const (
+	PreheatVendorDragonfly = "dragonfly"
+	PreheatVendorKraken    = "kraken"
+)
+
+// Preheat instance authentication modes
+const (
+	PreheatAuthModeNone   = "NONE"
+	PreheatAuthModeBasic  = "BASIC"
+	PreheatAuthModeOAuth  = "OAUTH"
+	PreheatAuthModeCustom = "CUSTOM"
+)
+
+// PreheatInstance is an instance of a P2P preheat provider, e.g. a Dragonfly supernode
+type PreheatInstance struct {
+	ID          int64  `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Vendor      string `json:"vendor"`
+	Endpoint    string `json:"endpoint"`
+	AuthMode    string `json:"auth_mode"`
+	// AuthInfo holds the credential of AuthMode, e.g. username and password for BASIC,
+	// token for OAUTH or header_key and header_value for CUSTOM.
+	AuthInfo       map[string]string `json:"auth_info,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Enabled        bool              `json:"enabled"`
+	Default        bool              `json:"default"`
+	Insecure       bool              `json:"insecure"`
+	SetupTimestamp int64             `json:"setup_timestamp,omitempty"`
+}
+
+// Preheat filter types
+const (
+	PreheatFilterRepository    = "repository"
+	PreheatFilterTag           = "tag"
+	PreheatFilterLabel         = "label"
+	PreheatFilterSignature     = "signature"
+	PreheatFilterVulnerability = "vulnerability"
+)
+
+// Preheat trigger types
+const (
+	PreheatTriggerManual     = "manual"
+	PreheatTriggerScheduled  = "scheduled"
+	PreheatTriggerEventBased = "event_based"
+)
+
+// PreheatPolicy selects the artifacts of a project to preheat with a provider instance.
+// Filters and Trigger are JSON documents, set them with SetFilters and SetTrigger
+type PreheatPolicy struct {
+	ID           int64  `json:"id,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderID   int64  `json:"provider_id"`
+	ProviderName string `json:"provider_name,omitempty"`
+	Filters      string `json:"filters"`
+	Trigger      string `json:"trigger"`
+	Enabled      bool   `json:"enabled"`
+	CreationTime string `json:"creation_time,omitempty"`
+	UpdateTime   string `json:"update_time,omitempty"`
+}
+
+// PreheatFilter selects the artifacts to preheat, e.g. the repositories matching a pattern
+type PreheatFilter struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// PreheatTrigger defines when a preheat policy is executed, Cron is only used by
+// scheduled triggers
+type PreheatTrigger struct {
+	Type     string `json:"type"`
+	Settings struct {
+		Cron string `json:"cron,omitempty"`
+	} `json:"trigger_setting"`
+}
+
+// SetFilters encodes the filters of the policy.
+func (p *PreheatPolicy) SetFilters(filters ...*PreheatFilter) error {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	p.Filters = string(data)
+	return nil
+}
+
+// SetTrigger encodes the trigger of the policy.
+func (p *PreheatPolicy) SetTrigger(trigger *PreheatTrigger) error {
+	data, err := json.Marshal(trigger)
+	if err != nil {
+		return err
+	}
+	p.Trigger = string(data)
+	return nil
+}
+
+// PreheatProvider is a preheat backend supported by Harbor, e.g. Dragonfly
+type PreheatProvider struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Icon        string   `json:"icon,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+	Version     string   `json:"version"`
+	Source      string   `json:"source,omitempty"`
+}
+
+// ProjectPreheatProvider is a provider instance available to the preheat policies of a project
+type ProjectPreheatProvider struct {
+	ID       int64  `json:"id"`
+	Provider string `json:"provider"`
+	Enabled  bool   `json:"enabled"`
+	Default  bool   `json:"default"`
+}
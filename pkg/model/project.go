@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// ProjectSummary holds the repository count, quota usage and member counts of a project
+type ProjectSummary struct {
+	RepoCount         int64                `json:"repo_count"`
+	ChartCount        int64                `json:"chart_count"`
+	ProjectAdminCount int64                `json:"project_admin_count"`
+	MaintainerCount   int64                `json:"maintainer_count"`
+	DeveloperCount    int64                `json:"developer_count"`
+	GuestCount        int64                `json:"guest_count"`
+	LimitedGuestCount int64                `json:"limited_guest_count"`
+	Quota             *ProjectSummaryQuota `json:"quota,omitempty"`
+	// Registry is the upstream registry of a proxy cache project.
+	Registry *Registry `json:"registry,omitempty"`
+}
+
+// ProjectSummaryQuota is the quota of a project and its usage, keyed by resource, e.g. storage
+type ProjectSummaryQuota struct {
+	Hard map[string]int64 `json:"hard"`
+	Used map[string]int64 `json:"used"`
+}
+
+// MemberCount returns the total number of members of the project.
+func (s *ProjectSummary) MemberCount() int64 {
+	return s.ProjectAdminCount + s.MaintainerCount + s.DeveloperCount + s.GuestCount + s.LimitedGuestCount
+}
+
+// ProjectDeletable tells whether a project can be deleted, Message explaining why not,
+// e.g. because it still has repositories, charts or replication policies
+type ProjectDeletable struct {
+	Deletable bool   `json:"deletable"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryBuilder builds the q query parameter of Harbor, a comma separated list of terms:
+//
+//	k=v          exact match
+//	k=~v         fuzzy match
+//	k=[min~max]  range, a bound being optional
+//	k={v1 v2}    union, any of the values
+//	k=(v1 v2)    intersection, all of the values
+//
+// e.g. NewQ().Fuzzy("name", "nginx").Range("creation_time", from, nil).Build(). The values
+// are escaped, the ones which can't be expressed, e.g. holding a comma, fail the build.
+type QueryBuilder struct {
+	terms []string
+	err   error
+}
+
+// NewQ starts a q query parameter.
+func NewQ() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Exact matches the resources whose key equals value.
+func (b *QueryBuilder) Exact(key string, value interface{}) *QueryBuilder {
+	v, err := formatQValue(value)
+	if err == nil && v == "" {
+		err = fmt.Errorf("empty value")
+	}
+	if err == nil && strings.ContainsAny(v[:1], `~[{(\`) {
+		// a leading backslash tells Harbor the value is not a pattern
+		v = `\` + v
+	}
+	return b.add(key, v, err)
+}
+
+// Fuzzy matches the resources whose key contains value.
+func (b *QueryBuilder) Fuzzy(key, value string) *QueryBuilder {
+	v, err := formatQValue(value)
+	if err == nil && v == "" {
+		err = fmt.Errorf("empty value")
+	}
+	return b.add(key, "~"+v, err)
+}
+
+// Range matches the resources whose key is between min and max, inclusive. A nil bound is
+// open, at least one of them must be set.
+func (b *QueryBuilder) Range(key string, min, max interface{}) *QueryBuilder {
+	if min == nil && max == nil {
+		return b.add(key, "", fmt.Errorf("a range needs a bound"))
+	}
+	var bounds [2]string
+	for i, bound := range []interface{}{min, max} {
+		if bound == nil {
+			continue
+		}
+		v, err := formatQValue(bound)
+		if err == nil && strings.ContainsAny(v, "~ ") && !isTime(bound) {
+			err = fmt.Errorf("range bound %q can't hold a tilde or a space", v)
+		}
+		if err != nil {
+			return b.add(key, "", err)
+		}
+		bounds[i] = v
+	}
+	return b.add(key, "["+bounds[0]+"~"+bounds[1]+"]", nil)
+}
+
+// Union matches the resources whose key is any of the values.
+func (b *QueryBuilder) Union(key string, values ...interface{}) *QueryBuilder {
+	list, err := formatQList(values)
+	return b.add(key, "{"+list+"}", err)
+}
+
+// Intersection matches the resources whose key, a list such as the labels, holds all the
+// values.
+func (b *QueryBuilder) Intersection(key string, values ...interface{}) *QueryBuilder {
+	list, err := formatQList(values)
+	return b.add(key, "("+list+")", err)
+}
+
+// Build returns the q query parameter, or the error of the first invalid term.
+func (b *QueryBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return strings.Join(b.terms, ","), nil
+}
+
+func (b *QueryBuilder) add(key, value string, err error) *QueryBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err == nil && (key == "" || strings.ContainsAny(key, "=,")) {
+		err = fmt.Errorf("invalid key")
+	}
+	if err != nil {
+		b.err = fmt.Errorf("q term %s error: %v", key, err)
+		return b
+	}
+	b.terms = append(b.terms, key+"="+value)
+	return b
+}
+
+// formatQValue formats a value of a term. Harbor unescapes the q parameter once more
+// after the query string, so the percent and plus signs are escaped, and splits it on
+// commas, which can't be escaped.
+func formatQValue(value interface{}) (string, error) {
+	var v string
+	switch t := value.(type) {
+	case string:
+		v = t
+	case time.Time:
+		v = t.UTC().Format(harborTimeFormat)
+	case int:
+		v = strconv.Itoa(t)
+	case int64:
+		v = strconv.FormatInt(t, 10)
+	case float64:
+		v = strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		v = strconv.FormatBool(t)
+	case fmt.Stringer:
+		v = t.String()
+	default:
+		v = fmt.Sprint(t)
+	}
+	if strings.Contains(v, ",") {
+		return "", fmt.Errorf("value %q can't hold a comma", v)
+	}
+	return strings.NewReplacer("%", "%25", "+", "%2B").Replace(v), nil
+}
+
+func formatQList(values []interface{}) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("empty list")
+	}
+	list := make([]string, len(values))
+	for i, value := range values {
+		v, err := formatQValue(value)
+		if err == nil && (v == "" || strings.Contains(v, " ")) {
+			err = fmt.Errorf("list value %q can't be empty or hold a space", v)
+		}
+		if err != nil {
+			return "", err
+		}
+		list[i] = v
+	}
+	return strings.Join(list, " "), nil
+}
+
+func isTime(value interface{}) bool {
+	_, ok := value.(time.Time)
+	return ok
+}
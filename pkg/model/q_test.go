@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	from := time.Date(2021, 4, 7, 10, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name     string
+		builder  *QueryBuilder
+		expected string
+	}{
+		{"empty", NewQ(), ""},
+		{"exact", NewQ().Exact("name", "library").Exact("project_id", int64(3)), "name=library,project_id=3"},
+		{"exact pattern", NewQ().Exact("name", "~nginx").Exact("tags", "{a}"), `name=\~nginx,tags=\{a}`},
+		{"escaped", NewQ().Exact("name", "50%+off"), "name=50%25%2Boff"},
+		{"fuzzy", NewQ().Fuzzy("name", "nginx"), "name=~nginx"},
+		{"range", NewQ().Range("cvss_score_v3", 7.5, nil).Range("op_time", from, from.Add(time.Hour)), "cvss_score_v3=[7.5~],op_time=[2021-04-07 10:00:00~2021-04-07 11:00:00]"},
+		{"union", NewQ().Union("severity", "Critical", "High"), "severity={Critical High}"},
+		{"intersection", NewQ().Intersection("labels", 1, 2), "labels=(1 2)"},
+	} {
+		q, err := test.builder.Build()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if q != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, q)
+		}
+	}
+
+	for name, builder := range map[string]*QueryBuilder{
+		"comma":         NewQ().Exact("name", "a,b"),
+		"empty value":   NewQ().Exact("name", ""),
+		"empty key":     NewQ().Fuzzy("", "a"),
+		"open range":    NewQ().Range("size", nil, nil),
+		"range tilde":   NewQ().Range("name", "a~b", nil),
+		"list space":    NewQ().Union("name", "a b"),
+		"empty list":    NewQ().Intersection("labels"),
+		"first error":   NewQ().Exact("name", "a,b").Exact("id", 1),
+		"invalid later": NewQ().Exact("id", 1).Union("tags"),
+	} {
+		if q, err := builder.Build(); err == nil {
+			t.Errorf("%s: expected an error, got %s", name, q)
+		}
+	}
+}
+
+func TestAuditLogQuery(t *testing.T) {
+	from := time.Date(2021, 4, 7, 10, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name     string
+		query    *AuditLogQuery
+		expected string
+	}{
+		{"empty", &AuditLogQuery{}, ""},
+		{"filters", &AuditLogQuery{Operation: "delete", Resource: "library/nginx", ResourceType: "artifact", Username: "admin"},
+			"operation=delete,resource=~library/nginx,resource_type=artifact,username=admin"},
+		{"pattern username", &AuditLogQuery{Username: "~admin"}, `username=\~admin`},
+		{"from", &AuditLogQuery{From: from}, "op_time=[2021-04-07 10:00:00~]"},
+		{"range", &AuditLogQuery{From: from, To: from.Add(time.Hour)}, "op_time=[2021-04-07 10:00:00~2021-04-07 11:00:00]"},
+	} {
+		q, err := test.query.Query()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if q.Q != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, q.Q)
+		}
+	}
+
+	if _, err := (&AuditLogQuery{Resource: "a,b"}).Query(); err == nil {
+		t.Errorf("expected an error for a resource with a comma")
+	}
+}
@@ -15,9 +15,21 @@ See the License for the specific language governing permissions and
 
 package model
 
-
 type Query struct {
 	PageSize int64  `json:"page_size,omitempty"`
 	Page     int64  `json:"page,omitempty"`
 	Q        string `json:"q,omitempty"`
+	// Sort is the order of the listing, see Sort
+	Sort string `json:"sort,omitempty"`
+}
+
+// ArtifactQuery holds the optional parameters for getting or listing artifacts
+type ArtifactQuery struct {
+	Query
+	WithTag             bool `json:"with_tag,omitempty"`
+	WithLabel           bool `json:"with_label,omitempty"`
+	WithScanOverview    bool `json:"with_scan_overview,omitempty"`
+	WithSignature       bool `json:"with_signature,omitempty"`
+	WithImmutableStatus bool `json:"with_immutable_status,omitempty"`
+	WithAccessory       bool `json:"with_accessory,omitempty"`
 }
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Quota reference types
+const (
+	QuotaReferenceProject = "project"
+)
+
+// Quota is the storage quota of a referenced object, i.e. of a project
+type Quota struct {
+	ID           int64            `json:"id"`
+	Ref          *QuotaRef        `json:"ref"`
+	Hard         map[string]int64 `json:"hard"`
+	Used         map[string]int64 `json:"used"`
+	CreationTime time.Time        `json:"creation_time"`
+	UpdateTime   time.Time        `json:"update_time"`
+}
+
+// QuotaRef is the object a quota applies to
+type QuotaRef struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	OwnerName string `json:"owner_name"`
+}
+
+// QuotaQuery holds the filters of quota listing
+type QuotaQuery struct {
+	Query
+	Reference   string `json:"reference,omitempty"`
+	ReferenceID string `json:"reference_id,omitempty"`
+}
+
+// QuotaUpdate is the body of a quota update, the limits being keyed by resource, -1
+// meaning unlimited
+type QuotaUpdate struct {
+	Hard map[string]int64 `json:"hard"`
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Registry types supported by Harbor replication
+const (
+	RegistryTypeHarbor         = "harbor"
+	RegistryTypeDockerHub      = "docker-hub"
+	RegistryTypeDockerRegistry = "docker-registry"
+	RegistryTypeQuay           = "quay"
+	RegistryTypeGitLab         = "gitlab"
+	RegistryTypeAwsEcr         = "aws-ecr"
+	RegistryTypeAzureAcr       = "azure-acr"
+	RegistryTypeGoogleGcr      = "google-gcr"
+)
+
+// Registry is a registry endpoint used by replication and proxy cache projects
+type Registry struct {
+	ID           int64               `json:"id,omitempty"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description,omitempty"`
+	Type         string              `json:"type"`
+	URL          string              `json:"url"`
+	Credential   *RegistryCredential `json:"credential,omitempty"`
+	Insecure     bool                `json:"insecure"`
+	Status       string              `json:"status,omitempty"`
+	CreationTime time.Time           `json:"creation_time,omitempty"`
+	UpdateTime   time.Time           `json:"update_time,omitempty"`
+}
+
+// RegistryCredential is the credential used to access a registry endpoint
+type RegistryCredential struct {
+	Type         string `json:"type"` // basic or oauth
+	AccessKey    string `json:"access_key"`
+	AccessSecret string `json:"access_secret"`
+}
+
+// RegistryPing is the registry endpoint to check, either an existing one by ID or
+// a new one by its type, URL and credential
+type RegistryPing struct {
+	ID             *int64 `json:"id,omitempty"`
+	Type           string `json:"type,omitempty"`
+	URL            string `json:"url,omitempty"`
+	CredentialType string `json:"credential_type,omitempty"`
+	AccessKey      string `json:"access_key,omitempty"`
+	AccessSecret   string `json:"access_secret,omitempty"`
+	Insecure       *bool  `json:"insecure,omitempty"`
+}
+
+// RegistryHealth is the result of pinging a registry endpoint from Harbor
+type RegistryHealth struct {
+	ID      int64         `json:"id"`
+	Name    string        `json:"name"`
+	URL     string        `json:"url"`
+	Healthy bool          `json:"healthy"`
+	Latency time.Duration `json:"latency"` // the duration of the ping, as seen by the client
+	Error   string        `json:"error,omitempty"`
+}
+
+// RegistryHealthSummary is the health of all the registry endpoints
+type RegistryHealthSummary struct {
+	Registries []*RegistryHealth `json:"registries"`
+	Healthy    int               `json:"healthy"`
+	Unhealthy  int               `json:"unhealthy"`
+}
+
+// AllHealthy returns true if every registry endpoint is reachable.
+func (s *RegistryHealthSummary) AllHealthy() bool {
+	return s.Unhealthy == 0
+}
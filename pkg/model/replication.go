@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Replication trigger types
+const (
+	TriggerTypeManual     = "manual"
+	TriggerTypeScheduled  = "scheduled"
+	TriggerTypeEventBased = "event_based"
+)
+
+// Replication filter types
+const (
+	FilterTypeName     = "name"
+	FilterTypeTag      = "tag"
+	FilterTypeLabel    = "label"
+	FilterTypeResource = "resource"
+)
+
+// Replication execution statuses
+const (
+	ExecutionStatusInProgress = "InProgress"
+	ExecutionStatusSucceed    = "Succeed"
+	ExecutionStatusFailed     = "Failed"
+	ExecutionStatusStopped    = "Stopped"
+)
+
+// ReplicationPolicy replicates the resources matching its filters from the source
+// registry to the destination registry. A nil registry means the local Harbor.
+type ReplicationPolicy struct {
+	ID            int64                `json:"id,omitempty"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description,omitempty"`
+	SrcRegistry   *Registry            `json:"src_registry,omitempty"`
+	DestRegistry  *Registry            `json:"dest_registry,omitempty"`
+	DestNamespace string               `json:"dest_namespace,omitempty"`
+	Filters       []*ReplicationFilter `json:"filters"`
+	Trigger       *ReplicationTrigger  `json:"trigger"`
+	Deletion      bool                 `json:"deletion"`
+	Override      bool                 `json:"override"`
+	Enabled       bool                 `json:"enabled"`
+	CreationTime  time.Time            `json:"creation_time,omitempty"`
+	UpdateTime    time.Time            `json:"update_time,omitempty"`
+}
+
+// ReplicationFilter selects the resources to replicate
+type ReplicationFilter struct {
+	Type       string      `json:"type"`
+	Value      interface{} `json:"value"`
+	Decoration string      `json:"decoration,omitempty"` // matches or excludes
+}
+
+// ReplicationTrigger defines when a replication policy is executed
+type ReplicationTrigger struct {
+	Type            string                      `json:"type"`
+	TriggerSettings *ReplicationTriggerSettings `json:"trigger_settings,omitempty"`
+}
+
+// ReplicationTriggerSettings holds the cron of scheduled triggers
+type ReplicationTriggerSettings struct {
+	Cron string `json:"cron,omitempty"`
+}
+
+// ReplicationExecution is a run of a replication policy
+type ReplicationExecution struct {
+	ID         int64     `json:"id"`
+	PolicyID   int64     `json:"policy_id"`
+	Status     string    `json:"status"`
+	StatusText string    `json:"status_text"`
+	Trigger    string    `json:"trigger"`
+	Total      int64     `json:"total"`
+	Failed     int64     `json:"failed"`
+	Succeed    int64     `json:"succeed"`
+	InProgress int64     `json:"in_progress"`
+	Stopped    int64     `json:"stopped"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time,omitempty"`
+}
+
+// Done returns true if the execution is not in progress anymore
+func (e *ReplicationExecution) Done() bool {
+	return e.Status != "" && e.Status != ExecutionStatusInProgress
+}
+
+// ReplicationExecutionQuery holds the filters of replication execution listing
+type ReplicationExecutionQuery struct {
+	Query
+	PolicyID int64  `json:"policy_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Trigger  string `json:"trigger,omitempty"`
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "fmt"
+
+// RetentionPolicy is the tag retention policy of a project
+type RetentionPolicy struct {
+	ID        int64             `json:"id,omitempty"`
+	Algorithm string            `json:"algorithm"` // how the rules are combined, e.g. or
+	Rules     []*RetentionRule  `json:"rules"`
+	Trigger   *RetentionTrigger `json:"trigger"`
+	Scope     *RetentionScope   `json:"scope"`
+}
+
+// RetentionRule retains or deletes the artifacts selected by its selectors according to
+// its template, e.g. latestPushedK keeping the K most recently pushed ones
+type RetentionRule struct {
+	ID             int                             `json:"id,omitempty"`
+	Priority       int                             `json:"priority,omitempty"`
+	Disabled       bool                            `json:"disabled,omitempty"`
+	Action         string                          `json:"action"`
+	Template       string                          `json:"template"`
+	Params         map[string]interface{}          `json:"params"`
+	TagSelectors   []*RetentionSelector            `json:"tag_selectors"`
+	ScopeSelectors map[string][]*RetentionSelector `json:"scope_selectors"`
+}
+
+// RetentionSelector selects the tags or repositories matching Pattern, Decoration being
+// e.g. matches or excludes
+type RetentionSelector struct {
+	Kind       string `json:"kind"`
+	Decoration string `json:"decoration"`
+	Pattern    string `json:"pattern"`
+	Extras     string `json:"extras,omitempty"`
+}
+
+// RetentionTrigger runs a retention policy, e.g. the Schedule kind with a cron setting
+type RetentionTrigger struct {
+	Kind       string                 `json:"kind"`
+	Settings   map[string]interface{} `json:"settings"`
+	References map[string]interface{} `json:"references,omitempty"`
+}
+
+// RetentionScope is the project a retention policy applies to
+type RetentionScope struct {
+	Level string `json:"level"`
+	Ref   int64  `json:"ref"`
+}
+
+// RetentionMetadata lists the rule templates and the selectors supported by Harbor
+type RetentionMetadata struct {
+	Templates      []*RetentionRuleTemplate `json:"templates"`
+	ScopeSelectors []*RetentionSelectorMeta `json:"scope_selectors"`
+	TagSelectors   []*RetentionSelectorMeta `json:"tag_selectors"`
+}
+
+// RetentionRuleTemplate is a rule template and its parameters
+type RetentionRuleTemplate struct {
+	RuleTemplate string                `json:"rule_template"`
+	DisplayText  string                `json:"display_text"`
+	Action       string                `json:"action"`
+	Params       []*RetentionRuleParam `json:"params"`
+}
+
+// RetentionRuleParam is a parameter of a rule template, e.g. an int COUNT
+type RetentionRuleParam struct {
+	Type     string `json:"type"`
+	Unit     string `json:"unit"`
+	Required bool   `json:"required"`
+}
+
+// RetentionSelectorMeta is a kind of selector and its decorations
+type RetentionSelectorMeta struct {
+	DisplayText string   `json:"display_text"`
+	Kind        string   `json:"kind"`
+	Decorations []string `json:"decorations"`
+}
+
+// Template returns the rule template named name, nil if it isn't supported.
+func (m *RetentionMetadata) Template(name string) *RetentionRuleTemplate {
+	for _, template := range m.Templates {
+		if template.RuleTemplate == name {
+			return template
+		}
+	}
+	return nil
+}
+
+// Validate checks that the rules of policy only use the templates and selectors supported
+// by Harbor, and set the required parameters of their template.
+func (m *RetentionMetadata) Validate(policy *RetentionPolicy) error {
+	for i, rule := range policy.Rules {
+		template := m.Template(rule.Template)
+		if template == nil {
+			return fmt.Errorf("rule %d: unsupported template %q", i, rule.Template)
+		}
+		if template.Action != "" && rule.Action != template.Action {
+			return fmt.Errorf("rule %d: template %s only supports the %s action, got %q", i, rule.Template, template.Action, rule.Action)
+		}
+		if requiresParam(template) {
+			if _, ok := rule.Params[rule.Template]; !ok {
+				return fmt.Errorf("rule %d: missing %s parameter", i, rule.Template)
+			}
+		}
+		for _, selector := range rule.TagSelectors {
+			if err := validateSelector(m.TagSelectors, selector); err != nil {
+				return fmt.Errorf("rule %d: tag selector: %v", i, err)
+			}
+		}
+		for scope, selectors := range rule.ScopeSelectors {
+			for _, selector := range selectors {
+				if err := validateSelector(m.ScopeSelectors, selector); err != nil {
+					return fmt.Errorf("rule %d: %s selector: %v", i, scope, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// requiresParam returns true if the template has a required parameter, Harbor keying the
+// parameter of a rule by the name of its template.
+func requiresParam(template *RetentionRuleTemplate) bool {
+	for _, param := range template.Params {
+		if param.Required {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSelector(supported []*RetentionSelectorMeta, selector *RetentionSelector) error {
+	for _, meta := range supported {
+		if meta.Kind != selector.Kind {
+			continue
+		}
+		for _, decoration := range meta.Decorations {
+			if decoration == selector.Decoration {
+				return nil
+			}
+		}
+		return fmt.Errorf("unsupported decoration %q of kind %s", selector.Decoration, selector.Kind)
+	}
+	return fmt.Errorf("unsupported kind %q", selector.Kind)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Robot account levels
+const (
+	RobotLevelSystem  = "system"
+	RobotLevelProject = "project"
+)
+
+// Robot is a robot account, its secret is only returned when it is created or refreshed
+type Robot struct {
+	ID           int64              `json:"id,omitempty"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description,omitempty"`
+	Level        string             `json:"level"`
+	Duration     int64              `json:"duration"`
+	Disable      bool               `json:"disable"`
+	ExpiresAt    int64              `json:"expires_at,omitempty"`
+	Editable     bool               `json:"editable,omitempty"`
+	Permissions  []*RobotPermission `json:"permissions"`
+	CreationTime time.Time          `json:"creation_time,omitempty"`
+	UpdateTime   time.Time          `json:"update_time,omitempty"`
+}
+
+// RobotPermission grants access to the resources of a namespace, the project name for
+// project level robot accounts
+type RobotPermission struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Access    []*Access `json:"access"`
+}
+
+// Access is an action allowed on a resource, e.g. pull on repository
+type Access struct {
+	Resource Resource `json:"resource"`
+	Action   Action   `json:"action"`
+	Effect   string   `json:"effect,omitempty"`
+}
+
+// Permissions is the access granted to an identity, e.g. to the current user
+type Permissions []Access
+
+// Allows returns true if the action is allowed on the resource, unless denied explicitly.
+func (p Permissions) Allows(resource Resource, action Action) bool {
+	allowed := false
+	for _, access := range p {
+		if access.Resource != resource || access.Action != action {
+			continue
+		}
+		if access.Effect == "deny" {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
+}
+
+// RobotCreated is the robot account returned on creation, with its secret
+type RobotCreated struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Secret       string    `json:"secret"`
+	CreationTime time.Time `json:"creation_time"`
+	ExpiresAt    int64     `json:"expires_at"`
+}
+
+// RobotSecret is the body and the result of a robot account secret refresh, an empty
+// secret making Harbor generate one
+type RobotSecret struct {
+	Secret string `json:"secret"`
+}
+
+// NewProjectRobot returns a project level robot account allowed the access on the project.
+// duration is the number of days the robot account is valid, -1 never expires.
+func NewProjectRobot(project, name string, duration int64, access ...*Access) *Robot {
+	return &Robot{
+		Name:     name,
+		Level:    RobotLevelProject,
+		Duration: duration,
+		Permissions: []*RobotPermission{{
+			Kind:      RobotLevelProject,
+			Namespace: project,
+			Access:    access,
+		}},
+	}
+}
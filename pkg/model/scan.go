@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "time"
+
+// Scan statuses of a vulnerability report
+const (
+	ScanStatusPending = "Pending"
+	ScanStatusRunning = "Running"
+	ScanStatusSuccess = "Success"
+	ScanStatusError   = "Error"
+	ScanStatusStopped = "Stopped"
+)
+
+// Scan types, the default one being the vulnerability scan
+const (
+	ScanTypeVulnerability = "vulnerability"
+	ScanTypeSBOM          = "sbom"
+)
+
+// ScanRequest is the optional body of a scan request
+type ScanRequest struct {
+	ScanType string `json:"scan_type"`
+}
+
+// SBOM is the software bill of materials of an artifact, an SPDX or CycloneDX document
+// whose format is given by MediaType
+type SBOM struct {
+	MediaType string
+	Data      []byte
+}
+
+// ScanOverview is the summary of a vulnerability report, keyed by report mime type
+// in the scan_overview field of an artifact
+type ScanOverview struct {
+	ReportID        string                `json:"report_id"`
+	ScanStatus      string                `json:"scan_status"`
+	Severity        string                `json:"severity"`
+	Duration        int64                 `json:"duration"`
+	Summary         *VulnerabilitySummary `json:"summary"`
+	StartTime       time.Time             `json:"start_time"`
+	EndTime         time.Time             `json:"end_time"`
+	CompletePercent int                   `json:"complete_percent"`
+	Scanner         *Scanner              `json:"scanner,omitempty"`
+}
+
+// Done returns true if the scan is over, whether it succeeded or not.
+func (o *ScanOverview) Done() bool {
+	return o.ScanStatus == ScanStatusSuccess || o.ScanStatus == ScanStatusError || o.ScanStatus == ScanStatusStopped
+}
+
+// VulnerabilitySummary contains the total number of the found vulnerabilities
+// and the number of each severity level
+type VulnerabilitySummary struct {
+	Total   int            `json:"total"`
+	Fixable int            `json:"fixable"`
+	Summary map[string]int `json:"summary"`
+}
+
+// Scanner is the basic information of the scanner that produced a report
+type Scanner struct {
+	Name    string `json:"name"`
+	Vendor  string `json:"vendor"`
+	Version string `json:"version"`
+}
+
+// VulnerabilityReport is a vulnerability report of an artifact, keyed by report mime type
+// in the vulnerabilities addition of the artifact
+type VulnerabilityReport struct {
+	GeneratedAt     time.Time            `json:"generated_at"`
+	Scanner         *Scanner             `json:"scanner,omitempty"`
+	Severity        string               `json:"severity"`
+	Vulnerabilities []*VulnerabilityItem `json:"vulnerabilities"`
+}
+
+// VulnerabilityItem is a vulnerability found in a package of an artifact
+type VulnerabilityItem struct {
+	ID            string   `json:"id"`
+	Package       string   `json:"package"`
+	Version       string   `json:"version"`
+	FixVersion    string   `json:"fix_version"`
+	Severity      string   `json:"severity"`
+	Description   string   `json:"description"`
+	Links         []string `json:"links"`
+	CWEIDs        []string `json:"cwe_ids,omitempty"`
+	PreferredCVSS *CVSS    `json:"preferred_cvss,omitempty"`
+}
+
+// CVSS holds the CVSS scores and vectors of a vulnerability
+type CVSS struct {
+	ScoreV2  *float64 `json:"score_v2,omitempty"`
+	ScoreV3  *float64 `json:"score_v3,omitempty"`
+	VectorV2 string   `json:"vector_v2"`
+	VectorV3 string   `json:"vector_v3"`
+}
+
+// ScannerRegistration is a scanner registered in Harbor
+type ScannerRegistration struct {
+	UUID             string    `json:"uuid"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	URL              string    `json:"url"`
+	Disabled         bool      `json:"disabled"`
+	IsDefault        bool      `json:"is_default"`
+	Auth             string    `json:"auth"` // the authentication of the scanner adapter, e.g. Basic, Bearer or X-ScannerAdapter-API-Key
+	AccessCredential string    `json:"access_credential,omitempty"`
+	SkipCertVerify   bool      `json:"skip_certVerify"`
+	UseInternalAddr  bool      `json:"use_internal_addr"`
+	Health           string    `json:"health,omitempty"`
+	Adapter          string    `json:"adapter,omitempty"`
+	Vendor           string    `json:"vendor,omitempty"`
+	Version          string    `json:"version,omitempty"`
+	CreateTime       time.Time `json:"create_time"`
+	UpdateTime       time.Time `json:"update_time"`
+}
+
+// ProjectScanner is the scanner registration assigned to a project
+type ProjectScanner struct {
+	UUID string `json:"uuid"`
+}
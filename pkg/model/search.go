@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import "github.com/goharbor/harbor/src/common/models"
+
+// Search holds the projects, repositories and charts matching a search
+type Search struct {
+	Projects     []*models.Project   `json:"project"`
+	Repositories []*SearchRepository `json:"repository"`
+	Charts       []*SearchChart      `json:"chart,omitempty"` // only returned by chartmuseum enabled Harbor
+}
+
+// SearchRepository is a repository matching a search
+type SearchRepository struct {
+	ProjectID      int64  `json:"project_id"`
+	ProjectName    string `json:"project_name"`
+	ProjectPublic  bool   `json:"project_public"`
+	RepositoryName string `json:"repository_name"`
+	PullCount      int64  `json:"pull_count"`
+	ArtifactCount  int64  `json:"artifact_count"`
+}
+
+// SearchChart is a chart version matching a search
+type SearchChart struct {
+	Name  string        `json:"Name"`
+	Score float64       `json:"Score"`
+	Chart *ChartVersion `json:"Chart"`
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecuritySummary is the fleet wide vulnerability summary of the security hub, the
+// dangerous CVEs and artifacts being only returned when asked for
+type SecuritySummary struct {
+	CriticalCount      int64                `json:"critical_cnt"`
+	HighCount          int64                `json:"high_cnt"`
+	MediumCount        int64                `json:"medium_cnt"`
+	LowCount           int64                `json:"low_cnt"`
+	NoneCount          int64                `json:"none_cnt"`
+	UnknownCount       int64                `json:"unknown_cnt"`
+	FixableCount       int64                `json:"fixable_cnt"`
+	TotalVulnerability int64                `json:"total_vuls"`
+	TotalArtifact      int64                `json:"total_artifact"`
+	ScannedCount       int64                `json:"scanned_cnt"`
+	DangerousCVEs      []*DangerousCVE      `json:"dangerous_cves,omitempty"`
+	DangerousArtifacts []*DangerousArtifact `json:"dangerous_artifacts,omitempty"`
+}
+
+// DangerousCVE is one of the CVEs with the highest CVSS scores
+type DangerousCVE struct {
+	CVEID       string  `json:"cve_id"`
+	Severity    string  `json:"severity"`
+	CVSSScoreV3 float64 `json:"cvss_score_v3"`
+	Description string  `json:"desc"`
+	Package     string  `json:"package"`
+	Version     string  `json:"version"`
+}
+
+// DangerousArtifact is one of the artifacts with the most critical vulnerabilities
+type DangerousArtifact struct {
+	ProjectID      int64  `json:"project_id"`
+	RepositoryName string `json:"repository_name"`
+	Digest         string `json:"digest"`
+	CriticalCount  int64  `json:"critical_cnt"`
+	HighCount      int64  `json:"high_cnt"`
+	MediumCount    int64  `json:"medium_cnt"`
+}
+
+// SecurityVulnerability is a vulnerability of an artifact listed by the security hub
+type SecurityVulnerability struct {
+	ProjectID      int64    `json:"project_id"`
+	RepositoryName string   `json:"repository_name"`
+	Digest         string   `json:"digest"`
+	Tags           []string `json:"tags"`
+	CVEID          string   `json:"cve_id"`
+	Severity       string   `json:"severity"`
+	Status         string   `json:"status"`
+	CVSSScoreV3    *float64 `json:"cvss_v3_score,omitempty"`
+	Package        string   `json:"package"`
+	Version        string   `json:"version"`
+	FixedVersion   string   `json:"fixed_version"`
+	Description    string   `json:"desc"`
+	Links          []string `json:"links"`
+}
+
+// SecurityVulnerabilityQuery holds the filters of the security hub vulnerability listing,
+// empty filters are ignored
+type SecurityVulnerabilityQuery struct {
+	Page     int64
+	PageSize int64
+
+	CVEID          string // exact match
+	Severity       string // exact match, e.g. Critical or High
+	Package        string // fuzzy match
+	ProjectID      int64
+	RepositoryName string // fuzzy match
+	Digest         string // exact match
+	Tag            string // fuzzy match
+	// the CVSS v3 score range, a zero bound being open
+	CVSSScoreV3From float64
+	CVSSScoreV3To   float64
+}
+
+// Query renders the filters into the Harbor q query parameter
+func (s *SecurityVulnerabilityQuery) Query() *Query {
+	var q []string
+	if s.CVEID != "" {
+		q = append(q, "cve_id="+s.CVEID)
+	}
+	if s.Severity != "" {
+		q = append(q, "severity="+s.Severity)
+	}
+	if s.Package != "" {
+		q = append(q, "package=~"+s.Package)
+	}
+	if s.ProjectID != 0 {
+		q = append(q, fmt.Sprintf("project_id=%d", s.ProjectID))
+	}
+	if s.RepositoryName != "" {
+		q = append(q, "repository_name=~"+s.RepositoryName)
+	}
+	if s.Digest != "" {
+		q = append(q, "digest="+s.Digest)
+	}
+	if s.Tag != "" {
+		q = append(q, "tag=~"+s.Tag)
+	}
+	if s.CVSSScoreV3From != 0 || s.CVSSScoreV3To != 0 {
+		var from, to string
+		if s.CVSSScoreV3From != 0 {
+			from = fmt.Sprint(s.CVSSScoreV3From)
+		}
+		if s.CVSSScoreV3To != 0 {
+			to = fmt.Sprint(s.CVSSScoreV3To)
+		}
+		q = append(q, fmt.Sprintf("cvss_score_v3=[%s~%s]", from, to))
+	}
+	return &Query{
+		Page:     s.Page,
+		PageSize: s.PageSize,
+		Q:        strings.Join(q, ","),
+	}
+}
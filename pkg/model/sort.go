@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort starts the sort parameter of a listing, a comma separated list of fields, the
+// descending ones prefixed with a minus, e.g.
+//
+//	Sort.Desc("creation_time").Then(Sort.Asc("name")).Build()
+//
+// renders -creation_time,name. The ascending fields are left unprefixed, a plus being
+// decoded as a space in a query string.
+var Sort SortOrder
+
+// SortOrder is the order of a listing, the zero value sorting by the default order of
+// Harbor. It is immutable, each method returning a new order.
+type SortOrder struct {
+	keys []sortKey
+	err  error
+}
+
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// SortableFields holds the fields the listings of a resource can be sorted by, keyed by
+// the resource of the endpoint. SortOrder.For checks the fields against it.
+var SortableFields = map[string][]string{
+	"artifacts":    {"digest", "pull_time", "push_time", "size", "type"},
+	"audit-logs":   {"op_time", "operation", "resource", "resource_type", "username"},
+	"projects":     {"creation_time", "name", "project_id", "update_time"},
+	"quotas":       {"creation_time", "hard.storage", "update_time", "used.storage"},
+	"registries":   {"creation_time", "name", "update_time"},
+	"repositories": {"creation_time", "name", "pull_count", "update_time"},
+	"robots":       {"creation_time", "name", "update_time"},
+	"usergroups":   {"creation_time", "group_name"},
+	"users":        {"creation_time", "update_time", "username"},
+}
+
+// Asc sorts by field in ascending order after the fields already in the order.
+func (s SortOrder) Asc(field string) SortOrder {
+	return s.add(field, false)
+}
+
+// Desc sorts by field in descending order after the fields already in the order.
+func (s SortOrder) Desc(field string) SortOrder {
+	return s.add(field, true)
+}
+
+// Then sorts by the fields of next after the fields already in the order.
+func (s SortOrder) Then(next SortOrder) SortOrder {
+	if s.err != nil {
+		return s
+	}
+	if next.err != nil {
+		return SortOrder{err: next.err}
+	}
+	for _, key := range next.keys {
+		s = s.add(key.field, key.desc)
+	}
+	return s
+}
+
+// Build renders the sort parameter, empty for the zero order, or fails on the first invalid
+// or duplicate field of the order.
+func (s SortOrder) Build() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	keys := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		keys[i] = key.field
+		if key.desc {
+			keys[i] = "-" + key.field
+		}
+	}
+	return strings.Join(keys, ","), nil
+}
+
+// For renders the sort parameter like Build, checking the fields against the SortableFields
+// of resource, e.g. "projects". The fields of a resource missing from SortableFields are
+// not checked.
+func (s SortOrder) For(resource string) (string, error) {
+	sorting, err := s.Build()
+	if err != nil {
+		return "", err
+	}
+	fields, ok := SortableFields[resource]
+	if !ok {
+		return sorting, nil
+	}
+	for _, key := range s.keys {
+		if !contains(fields, key.field) {
+			return "", fmt.Errorf("%s can't be sorted by %s, only by %s", resource, key.field, strings.Join(fields, ", "))
+		}
+	}
+	return sorting, nil
+}
+
+// String renders the sort parameter, empty if the order holds an invalid field.
+func (s SortOrder) String() string {
+	sorting, _ := s.Build()
+	return sorting
+}
+
+func (s SortOrder) add(field string, desc bool) SortOrder {
+	if s.err != nil {
+		return s
+	}
+	if !isSortField(field) {
+		return SortOrder{err: fmt.Errorf("invalid sort field %q", field)}
+	}
+	for _, key := range s.keys {
+		if key.field == field {
+			return SortOrder{err: fmt.Errorf("duplicate sort field %q", field)}
+		}
+	}
+	keys := make([]sortKey, 0, len(s.keys)+1)
+	return SortOrder{keys: append(append(keys, s.keys...), sortKey{field: field, desc: desc})}
+}
+
+// isSortField reports whether field is a column name, possibly qualified, e.g. used.storage.
+func isSortField(field string) bool {
+	if field == "" {
+		return false
+	}
+	for _, c := range field {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
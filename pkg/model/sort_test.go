@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    SortOrder
+		expected string
+	}{
+		{"default", Sort, ""},
+		{"ascending", Sort.Asc("creation_time"), "creation_time"},
+		{"descending", Sort.Desc("creation_time"), "-creation_time"},
+		{"chained", Sort.Desc("creation_time").Asc("name"), "-creation_time,name"},
+		{"then", Sort.Desc("used.storage").Then(Sort.Asc("creation_time").Desc("update_time")), "-used.storage,creation_time,-update_time"},
+	}
+	for _, test := range tests {
+		sorting, err := test.order.Build()
+		if err != nil || sorting != test.expected {
+			t.Errorf("%s: unexpected sort %q, %v", test.name, sorting, err)
+		}
+	}
+
+	// the orders are immutable
+	base := Sort.Asc("name")
+	base.Desc("creation_time")
+	if sorting := base.Asc("update_time").String(); sorting != "name,update_time" {
+		t.Errorf("unexpected sort %q", sorting)
+	}
+}
+
+func TestSortInvalid(t *testing.T) {
+	for _, order := range []SortOrder{
+		Sort.Asc(""),
+		Sort.Asc("name,id"),
+		Sort.Desc("-name"),
+		Sort.Asc("name").Desc("name"),
+		Sort.Asc("name").Then(Sort.Desc("name")),
+		Sort.Asc("name").Then(Sort.Asc("a b")),
+	} {
+		if sorting, err := order.Build(); err == nil {
+			t.Errorf("expected an error for %q", sorting)
+		}
+	}
+}
+
+func TestSortFor(t *testing.T) {
+	if sorting, err := Sort.Desc("creation_time").Asc("name").For("projects"); err != nil || sorting != "-creation_time,name" {
+		t.Errorf("unexpected sort %q, %v", sorting, err)
+	}
+	if _, err := Sort.Desc("push_time").For("projects"); err == nil {
+		t.Error("expected projects not to be sortable by push_time")
+	}
+	if sorting, err := Sort.Asc("anything").For("unknown"); err != nil || sorting != "anything" {
+		t.Errorf("unexpected sort %q, %v", sorting, err)
+	}
+}
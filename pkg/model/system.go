@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// Statistic holds the project and repository counts relevant to the current user
+type Statistic struct {
+	PrivateProjectCount     int64 `json:"private_project_count"`     // the count of the private projects
+	PrivateRepoCount        int64 `json:"private_repo_count"`        // the count of the private repositories
+	PublicProjectCount      int64 `json:"public_project_count"`      // the count of the public projects
+	PublicRepoCount         int64 `json:"public_repo_count"`         // the count of the public repositories
+	TotalProjectCount       int64 `json:"total_project_count"`       // the count of the total projects, only be seen by the system admin
+	TotalRepoCount          int64 `json:"total_repo_count"`          // the count of the total repositories, only be seen by the system admin
+	TotalStorageConsumption int64 `json:"total_storage_consumption"` // the total storage consumption of blobs, only be seen by the system admin
+}
+
+// OverallHealthStatus is the health status of Harbor and of each of its components
+type OverallHealthStatus struct {
+	Status     string                   `json:"status"`
+	Components []*ComponentHealthStatus `json:"components"`
+}
+
+// ComponentHealthStatus is the health status of a Harbor component, e.g. core, database or registry
+type ComponentHealthStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Healthy returns true if the overall status is healthy
+func (o *OverallHealthStatus) Healthy() bool {
+	return o.Status == "healthy"
+}
+
+// Unhealthy returns the components whose status is not healthy
+func (o *OverallHealthStatus) Unhealthy() []*ComponentHealthStatus {
+	var components []*ComponentHealthStatus
+	for _, c := range o.Components {
+		if c.Status != "healthy" {
+			components = append(components, c)
+		}
+	}
+	return components
+}
+
+// GeneralInfo is the system information of Harbor, the version is only returned to the
+// authenticated users
+type GeneralInfo struct {
+	HarborVersion               string `json:"harbor_version,omitempty"`
+	ExternalURL                 string `json:"external_url,omitempty"`
+	RegistryURL                 string `json:"registry_url,omitempty"`
+	AuthMode                    string `json:"auth_mode,omitempty"`
+	PrimaryAuthMode             bool   `json:"primary_auth_mode,omitempty"`
+	ProjectCreationRestriction  string `json:"project_creation_restriction,omitempty"`
+	SelfRegistration            bool   `json:"self_registration,omitempty"`
+	HasCARoot                   bool   `json:"has_ca_root,omitempty"`
+	ReadOnly                    bool   `json:"read_only,omitempty"`
+	WithNotary                  bool   `json:"with_notary,omitempty"`
+	WithChartmuseum             bool   `json:"with_chartmuseum,omitempty"`
+	NotificationEnable          bool   `json:"notification_enable,omitempty"`
+	RegistryStorageProviderName string `json:"registry_storage_provider_name,omitempty"`
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// UserCreationReq is the user to create when the authentication mode is database
+type UserCreationReq struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Realname string `json:"realname"`
+	Password string `json:"password"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// UserProfile holds the editable profile of a user
+type UserProfile struct {
+	Email    string `json:"email,omitempty"`
+	Realname string `json:"realname,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// PasswordReq changes the password of a user, OldPassword is not required when an
+// administrator resets the password of another user
+type PasswordReq struct {
+	OldPassword string `json:"old_password,omitempty"`
+	NewPassword string `json:"new_password"`
+}
+
+// SysAdminFlag grants or revokes the system administrator role
+type SysAdminFlag struct {
+	SysAdminFlag bool `json:"sysadmin_flag"`
+}
+
+// UserSearch is a user returned by the search endpoint
+type UserSearch struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+}
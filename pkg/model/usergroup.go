@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+// User group types
+const (
+	UserGroupTypeLDAP = 1
+	UserGroupTypeHTTP = 2
+	UserGroupTypeOIDC = 3
+)
+
+// UserGroup is a group of users that can be added as a project member. LDAP groups are
+// identified by their DN, OIDC and HTTP groups by their name.
+type UserGroup struct {
+	ID          int64  `json:"id,omitempty"`
+	GroupName   string `json:"group_name,omitempty"`
+	GroupType   int    `json:"group_type"`
+	LdapGroupDN string `json:"ldap_group_dn,omitempty"`
+}
+
+// UserGroupSearchItem is a user group returned by the search endpoint
+type UserGroupSearchItem struct {
+	ID        int64  `json:"id"`
+	GroupName string `json:"group_name"`
+	GroupType int    `json:"group_type"`
+}
+
+// UserGroupQuery holds the filters of user group listing
+type UserGroupQuery struct {
+	Query
+	LdapGroupDN string `json:"ldap_group_dn,omitempty"`
+}
+
+// UserGroupSearchQuery holds the parameters of user group search
+type UserGroupSearchQuery struct {
+	Query
+	GroupName string `json:"groupname"`
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ServerVersion is the release of a Harbor, e.g. v2.9.1-5f6e2fb8
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// Build is the suffix of the version, e.g. -5f6e2fb8 or -rc1
+	Build string
+}
+
+var serverVersionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?(.*)$`)
+
+// ParseServerVersion parses a Harbor version, e.g. v2.9.1-5f6e2fb8 or 2.10.0, a missing
+// patch number being 0.
+func ParseServerVersion(version string) (*ServerVersion, error) {
+	matches := serverVersionPattern.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid Harbor version %q", version)
+	}
+	v := &ServerVersion{Build: matches[4]}
+	for i, n := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if matches[i+1] == "" {
+			continue
+		}
+		number, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Harbor version %q: %v", version, err)
+		}
+		*n = number
+	}
+	return v, nil
+}
+
+// MustParseServerVersion is like ParseServerVersion but panics if version is invalid, for
+// the versions known at compile time.
+func MustParseServerVersion(version string) *ServerVersion {
+	v, err := ParseServerVersion(version)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same release as or newer than other,
+// ignoring the build suffix.
+func (v *ServerVersion) Compare(other *ServerVersion) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		switch {
+		case diff < 0:
+			return -1
+		case diff > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns true if v is the release major.minor.patch or a newer one.
+func (v *ServerVersion) AtLeast(major, minor, patch int) bool {
+	return v.Compare(&ServerVersion{Major: major, Minor: minor, Patch: patch}) >= 0
+}
+
+// Supports returns true if the release v has the feature.
+func (v *ServerVersion) Supports(feature Feature) bool {
+	return v.Compare(feature.Since) >= 0
+}
+
+func (v *ServerVersion) String() string {
+	return fmt.Sprintf("v%d.%d.%d%s", v.Major, v.Minor, v.Patch, v.Build)
+}
+
+// Feature is a capability of Harbor introduced by a release
+type Feature struct {
+	Name  string
+	Since *ServerVersion
+}
+
+// The features the SDK checks the release of Harbor for.
+var (
+	// FeatureAccessories lists the accessories of the artifacts, e.g. their signatures,
+	// see ArtifactQuery.WithAccessory
+	FeatureAccessories = Feature{Name: "artifact accessories", Since: MustParseServerVersion("v2.5.0")}
+	// FeatureSecurityHub summarizes the vulnerabilities of all the artifacts
+	FeatureSecurityHub = Feature{Name: "security hub", Since: MustParseServerVersion("v2.9.0")}
+)
+
+// UnsupportedFeatureError is returned by a call needing a feature the release of Harbor
+// doesn't have
+type UnsupportedFeatureError struct {
+	Feature Feature
+	Version *ServerVersion
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s requires Harbor %s or newer, the server is %s", e.Feature.Name, e.Feature.Since, e.Version)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	for version, expected := range map[string]ServerVersion{
+		"v2.9.1-5f6e2fb8": {Major: 2, Minor: 9, Patch: 1, Build: "-5f6e2fb8"},
+		"2.10.0":          {Major: 2, Minor: 10},
+		"v1.10":           {Major: 1, Minor: 10},
+		"v2.8.0-rc1":      {Major: 2, Minor: 8, Build: "-rc1"},
+	} {
+		v, err := ParseServerVersion(version)
+		if err != nil || *v != expected {
+			t.Errorf("unexpected version %+v for %s: %v", v, version, err)
+		}
+	}
+	for _, version := range []string{"", "dev", "v2"} {
+		if _, err := ParseServerVersion(version); err == nil {
+			t.Errorf("expected an error for %q", version)
+		}
+	}
+	if s := MustParseServerVersion("2.9.1").String(); s != "v2.9.1" {
+		t.Errorf("unexpected string %s", s)
+	}
+}
+
+func TestServerVersionCompare(t *testing.T) {
+	v := MustParseServerVersion("v2.9.1-5f6e2fb8")
+	for other, expected := range map[string]int{"v2.9.1": 0, "v2.10.0": -1, "v2.8.4": 1, "v3.0.0": -1, "v1.10.17": 1} {
+		if c := v.Compare(MustParseServerVersion(other)); c != expected {
+			t.Errorf("expected %s compared to %s to be %d, got %d", v, other, expected, c)
+		}
+	}
+	if !v.AtLeast(2, 9, 0) || v.AtLeast(2, 9, 2) {
+		t.Errorf("unexpected AtLeast of %s", v)
+	}
+	if !v.Supports(FeatureSecurityHub) || MustParseServerVersion("v2.4.3").Supports(FeatureAccessories) {
+		t.Error("unexpected supported features")
+	}
+	err := &UnsupportedFeatureError{Feature: FeatureSecurityHub, Version: MustParseServerVersion("v2.8.4")}
+	if err.Error() != "security hub requires Harbor v2.9.0 or newer, the server is v2.8.4" {
+		t.Errorf("unexpected error %q", err)
+	}
+}
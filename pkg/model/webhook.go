@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook notify types
+const (
+	WebhookNotifyTypeHTTP  = "http"
+	WebhookNotifyTypeSlack = "slack"
+)
+
+// WebhookPolicy sends the events of a project to its targets
+type WebhookPolicy struct {
+	ID           int64            `json:"id,omitempty"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	ProjectID    int64            `json:"project_id"`
+	Targets      []*WebhookTarget `json:"targets"`
+	EventTypes   []string         `json:"event_types"`
+	Creator      string           `json:"creator,omitempty"`
+	CreationTime time.Time        `json:"creation_time,omitempty"`
+	UpdateTime   time.Time        `json:"update_time,omitempty"`
+	Enabled      bool             `json:"enabled"`
+}
+
+// WebhookTarget is an endpoint events are sent to
+type WebhookTarget struct {
+	Type           string `json:"type"`
+	Address        string `json:"address"`
+	AuthHeader     string `json:"auth_header,omitempty"`
+	SkipCertVerify bool   `json:"skip_cert_verify"`
+}
+
+// WebhookJob is the delivery of an event to the targets of a policy, JobDetail holds the
+// payload that was sent
+type WebhookJob struct {
+	ID           int64     `json:"id"`
+	PolicyID     int64     `json:"policy_id"`
+	EventType    string    `json:"event_type"`
+	NotifyType   string    `json:"notify_type"`
+	Status       string    `json:"status"`
+	JobDetail    string    `json:"job_detail"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+// Failed returns true if the delivery failed, the statuses differ between Harbor versions.
+func (j *WebhookJob) Failed() bool {
+	status := strings.ToLower(j.Status)
+	return status == "error" || status == "failed"
+}
+
+// WebhookJobQuery holds the filters of webhook job listing
+type WebhookJobQuery struct {
+	Query
+	PolicyID int64  `json:"policy_id"`
+	Status   string `json:"status,omitempty"`
+}
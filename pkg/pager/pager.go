@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+// Package pager walks the pages of the list endpoints of Harbor.
+package pager
+
+import (
+	"context"
+	"fmt"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"reflect"
+)
+
+// DefaultPageSize is the page size used when none is given, the maximum of Harbor.
+const DefaultPageSize int64 = 100
+
+// PageFunc lists the page of number page, starting at 1, with pageSize items and returns
+// the number of items of the page, e.g. by calling the List method of a service with the
+// page and the page size of its query set. It must list with ctx, which collects the
+// paging metadata of the response.
+type PageFunc func(ctx context.Context, page, pageSize int64) (items int, err error)
+
+// ListFunc lists the page of number page, starting at 1, with pageSize items and returns
+// them as a pointer to a slice, like the List methods of the services do.
+type ListFunc func(ctx context.Context, page, pageSize int64) (items interface{}, err error)
+
+// EachPage calls fn for every page of a list, until the last one. The last page is told
+// by the Link header of the response, or by the total count if the server sends no Link
+// header, or else by a page shorter than pageSize. A pageSize of 0 means
+// DefaultPageSize.
+func EachPage(ctx context.Context, pageSize int64, fn PageFunc) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	for page := int64(1); ; page++ {
+		// the total is unknown unless fn lists with ctx
+		pagination := rest2.Pagination{Total: -1}
+		items, err := fn(rest2.WithPagination(ctx, &pagination), page, pageSize)
+		if err != nil {
+			return err
+		}
+		if !hasNext(&pagination, page, pageSize, items) {
+			return nil
+		}
+	}
+}
+
+// ListAll lists all the items of a list into result, a pointer to a slice of the type of
+// the items, calling list for every page, see EachPage. E.g. to list all the projects:
+//
+//	var projects []models.Project
+//	err := pager.ListAll(ctx, 0, &projects, func(ctx context.Context, page, pageSize int64) (interface{}, error) {
+//		return clientSet.V2.List(ctx, &model.Query{Page: page, PageSize: pageSize})
+//	})
+func ListAll(ctx context.Context, pageSize int64, result interface{}, list ListFunc) error {
+	all := reflect.ValueOf(result)
+	if all.Kind() != reflect.Ptr || all.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice, got %T", result)
+	}
+	all = all.Elem()
+	return EachPage(ctx, pageSize, func(ctx context.Context, page, pageSize int64) (int, error) {
+		items, err := list(ctx, page, pageSize)
+		if err != nil {
+			return 0, err
+		}
+		value := reflect.ValueOf(items)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return 0, nil
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Slice || value.Type() != all.Type() {
+			return 0, fmt.Errorf("expected the page to be a %v, got %T", all.Type(), items)
+		}
+		all.Set(reflect.AppendSlice(all, value))
+		return value.Len(), nil
+	})
+}
+
+// hasNext returns true if there is a page after the page of number page.
+func hasNext(pagination *rest2.Pagination, page, pageSize int64, items int) bool {
+	switch {
+	case items == 0:
+		return false
+	case pagination.HasNext():
+		return true
+	case pagination.Total >= 0:
+		return page*pageSize < pagination.Total
+	}
+	return int64(items) >= pageSize
+}
+
+// All lists all the items of a list, calling list for every page, see EachPage. It is the
+// typed equivalent of ListAll, e.g. to list all the projects:
+//
+//	projects, err := pager.All(ctx, 0, func(ctx context.Context, page, pageSize int64) (*[]models.Project, error) {
+//		return clientSet.V2.List(ctx, &model.Query{Page: page, PageSize: pageSize})
+//	})
+func All[T any](ctx context.Context, pageSize int64, list func(ctx context.Context, page, pageSize int64) (*[]T, error)) ([]T, error) {
+	var all []T
+	err := EachPage(ctx, pageSize, func(ctx context.Context, page, pageSize int64) (int, error) {
+		items, err := list(ctx, page, pageSize)
+		if err != nil || items == nil {
+			return 0, err
+		}
+		all = append(all, *items...)
+		return len(*items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
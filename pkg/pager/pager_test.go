@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package pager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type project struct {
+	Name string `json:"name"`
+}
+
+// newServer serves total projects with the pagination headers of Harbor, or only with
+// the total count, or with neither.
+func newServer(total int, headers string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(req.URL.Query().Get("page_size"))
+		projects := []project{}
+		for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+			projects = append(projects, project{Name: fmt.Sprintf("p%d", i)})
+		}
+		if headers != "" {
+			w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		}
+		if headers == "link" && page*pageSize < total {
+			w.Header().Set("Link", fmt.Sprintf(`</api/v2.0/projects?page=%d&page_size=%d>; rel="next"`, page+1, pageSize))
+		}
+		json.NewEncoder(w).Encode(projects)
+	}))
+}
+
+func TestListAll(t *testing.T) {
+	for _, headers := range []string{"link", "total", ""} {
+		for _, total := range []int{0, 3, 5, 7} {
+			server := newServer(total, headers)
+			client, err := rest2.RESTClientFor(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var requests int
+			var projects []project
+			err = ListAll(context.Background(), 5, &projects, func(ctx context.Context, page, pageSize int64) (interface{}, error) {
+				requests++
+				result := &[]project{}
+				err := client.Get().
+					Resource("projects").
+					Param("page", strconv.FormatInt(page, 10)).
+					Param("page_size", strconv.FormatInt(pageSize, 10)).
+					Do(ctx).
+					Into(result)
+				return result, err
+			})
+			server.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(projects) != total {
+				t.Errorf("%q %d: expected all the projects, got %v", headers, total, projects)
+			}
+			for i, p := range projects {
+				if p.Name != fmt.Sprintf("p%d", i) {
+					t.Errorf("%q %d: unexpected project %d %v", headers, total, i, p)
+				}
+			}
+			// without headers a full last page can't be told from a full page
+			expected := (total + 4) / 5
+			if expected == 0 || (headers == "" && total%5 == 0) {
+				expected++
+			}
+			if requests != expected {
+				t.Errorf("%q %d: expected %d requests, got %d", headers, total, expected, requests)
+			}
+		}
+	}
+}
+
+func TestEachPage(t *testing.T) {
+	var sizes []int64
+	failure := errors.New("failure")
+	err := EachPage(context.Background(), 0, func(ctx context.Context, page, pageSize int64) (int, error) {
+		sizes = append(sizes, pageSize)
+		if page == 2 {
+			return 0, failure
+		}
+		return int(pageSize), nil
+	})
+	if err != failure {
+		t.Errorf("expected the error of the page, got %v", err)
+	}
+	if !reflect.DeepEqual(sizes, []int64{DefaultPageSize, DefaultPageSize}) {
+		t.Errorf("unexpected pages %v", sizes)
+	}
+
+	var names []string
+	err = ListAll(context.Background(), 1, &names, func(ctx context.Context, page, pageSize int64) (interface{}, error) {
+		return &[]int{1}, nil
+	})
+	if err == nil {
+		t.Error("expected the type mismatch to fail")
+	}
+}
+
+func TestAll(t *testing.T) {
+	names, err := All(context.Background(), 2, func(ctx context.Context, page, pageSize int64) (*[]string, error) {
+		pages := [][]string{{"a", "b"}, {"c"}}
+		return &pages[page-1], nil
+	})
+	if err != nil || !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected items %v, %v", names, err)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package preheat
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"strconv"
+)
+
+// GetPolicy gets a preheat policy of a project by name.
+func (p *PreheatClient) GetPolicy(ctx context.Context, project, name string) (result *model.PreheatPolicy, err error) {
+	result = &model.PreheatPolicy{}
+	err = p.restClient.Get().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", name).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (p *PreheatClient) ListPolicies(ctx context.Context, project string, query *model.Query) (results *[]model.PreheatPolicy, err error) {
+	results = &[]model.PreheatPolicy{}
+	err = p.restClient.List().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (p *PreheatClient) CreatePolicy(ctx context.Context, project string, policy *model.PreheatPolicy) (err error) {
+	return p.restClient.Post().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies").
+		Body(policy).
+		Do(ctx).
+		Error()
+}
+
+func (p *PreheatClient) UpdatePolicy(ctx context.Context, project, name string, policy *model.PreheatPolicy) (err error) {
+	return p.restClient.Put().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", name).
+		Body(policy).
+		Do(ctx).
+		Error()
+}
+
+func (p *PreheatClient) DeletePolicy(ctx context.Context, project, name string) (err error) {
+	return p.restClient.Delete().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", name).
+		Do(ctx).
+		Error()
+}
+
+// ExecutePolicy manually executes a preheat policy, the execution runs asynchronously.
+func (p *PreheatClient) ExecutePolicy(ctx context.Context, project, name string) (err error) {
+	return p.restClient.Post().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", name).
+		Body(&model.PreheatPolicy{Name: name}).
+		Do(ctx).
+		Error()
+}
+
+// ListExecutions lists the executions of a preheat policy, latest first.
+func (p *PreheatClient) ListExecutions(ctx context.Context, project, policy string, query *model.Query) (results *[]model.Execution, err error) {
+	results = &[]model.Execution{}
+	err = p.restClient.List().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", policy, "executions").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (p *PreheatClient) GetExecution(ctx context.Context, project, policy string, id int64) (result *model.Execution, err error) {
+	result = &model.Execution{}
+	err = p.restClient.Get().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", policy, "executions", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ListTasks lists the tasks of an execution, one per artifact preheated.
+func (p *PreheatClient) ListTasks(ctx context.Context, project, policy string, executionID int64, query *model.Query) (results *[]model.Task, err error) {
+	results = &[]model.Task{}
+	err = p.restClient.List().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", policy, "executions", strconv.FormatInt(executionID, 10), "tasks").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// GetTaskLog gets the log of a task, e.g. to debug a failed preheat.
+func (p *PreheatClient) GetTaskLog(ctx context.Context, project, policy string, executionID, taskID int64) (log []byte, err error) {
+	log, _, err = p.restClient.Get().
+		Project(project).
+		Resource("preheat").
+		Suffix("policies", policy, "executions", strconv.FormatInt(executionID, 10),
+			"tasks", strconv.FormatInt(taskID, 10), "logs").
+		DoRaw(ctx)
+	return
+}
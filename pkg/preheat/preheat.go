@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package preheat
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// PreheatInterface holds the methods of the P2P preheat APIs.
+type PreheatInterface interface {
+	GetInstance(ctx context.Context, name string) (result *model.PreheatInstance, err error)
+	ListInstances(ctx context.Context, query *model.Query) (results *[]model.PreheatInstance, err error)
+	CreateInstance(ctx context.Context, instance *model.PreheatInstance) (err error)
+	UpdateInstance(ctx context.Context, name string, instance *model.PreheatInstance) (err error)
+	DeleteInstance(ctx context.Context, name string) (err error)
+	PingInstance(ctx context.Context, instance *model.PreheatInstance) (err error)
+	ListProviders(ctx context.Context) (results *[]model.PreheatProvider, err error)
+	ListProjectProviders(ctx context.Context, project string) (results *[]model.ProjectPreheatProvider, err error)
+	GetPolicy(ctx context.Context, project, name string) (result *model.PreheatPolicy, err error)
+	ListPolicies(ctx context.Context, project string, query *model.Query) (results *[]model.PreheatPolicy, err error)
+	CreatePolicy(ctx context.Context, project string, policy *model.PreheatPolicy) (err error)
+	UpdatePolicy(ctx context.Context, project, name string, policy *model.PreheatPolicy) (err error)
+	DeletePolicy(ctx context.Context, project, name string) (err error)
+	ExecutePolicy(ctx context.Context, project, name string) (err error)
+	ListExecutions(ctx context.Context, project, policy string, query *model.Query) (results *[]model.Execution, err error)
+	GetExecution(ctx context.Context, project, policy string, id int64) (result *model.Execution, err error)
+	ListTasks(ctx context.Context, project, policy string, executionID int64, query *model.Query) (results *[]model.Task, err error)
+	GetTaskLog(ctx context.Context, project, policy string, executionID, taskID int64) (log []byte, err error)
+}
+
+// PreheatClient is used to manage P2P preheating, which distributes images to the nodes
+// of a Dragonfly or Kraken network before they are pulled.
+type PreheatClient struct {
+	restClient rest2.Interface
+}
+
+func NewPreheatClient(restClient *rest2.Config) (*PreheatClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &PreheatClient{restClient: client}, nil
+}
+
+func (p *PreheatClient) GetInstance(ctx context.Context, name string) (result *model.PreheatInstance, err error) {
+	result = &model.PreheatInstance{}
+	err = p.restClient.Get().
+		Resource("p2p").
+		Suffix("preheat", "instances", name).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (p *PreheatClient) ListInstances(ctx context.Context, query *model.Query) (results *[]model.PreheatInstance, err error) {
+	results = &[]model.PreheatInstance{}
+	err = p.restClient.List().
+		Resource("p2p").
+		Suffix("preheat", "instances").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (p *PreheatClient) CreateInstance(ctx context.Context, instance *model.PreheatInstance) (err error) {
+	return p.restClient.Post().
+		Resource("p2p").
+		Suffix("preheat", "instances").
+		Body(instance).
+		Do(ctx).
+		Error()
+}
+
+func (p *PreheatClient) UpdateInstance(ctx context.Context, name string, instance *model.PreheatInstance) (err error) {
+	return p.restClient.Put().
+		Resource("p2p").
+		Suffix("preheat", "instances", name).
+		Body(instance).
+		Do(ctx).
+		Error()
+}
+
+func (p *PreheatClient) DeleteInstance(ctx context.Context, name string) (err error) {
+	return p.restClient.Delete().
+		Resource("p2p").
+		Suffix("preheat", "instances", name).
+		Do(ctx).
+		Error()
+}
+
+// PingInstance checks that Harbor can reach the provider instance, either an existing one
+// by ID or a new one by its vendor, endpoint and credential.
+func (p *PreheatClient) PingInstance(ctx context.Context, instance *model.PreheatInstance) (err error) {
+	return p.restClient.Post().
+		Resource("p2p").
+		Suffix("preheat", "instances", "ping").
+		Body(instance).
+		Do(ctx).
+		Error()
+}
+
+// ListProviders lists the preheat backends supported by Harbor, instances can be created
+// for them.
+func (p *PreheatClient) ListProviders(ctx context.Context) (results *[]model.PreheatProvider, err error) {
+	results = &[]model.PreheatProvider{}
+	err = p.restClient.List().
+		Resource("p2p").
+		Suffix("preheat", "providers").
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// ListProjectProviders lists the provider instances the preheat policies of a project
+// can use.
+func (p *PreheatClient) ListProjectProviders(ctx context.Context, project string) (results *[]model.ProjectPreheatProvider, err error) {
+	results = &[]model.ProjectPreheatProvider{}
+	err = p.restClient.List().
+		Project(project).
+		Resource("preheat").
+		Suffix("providers").
+		Do(ctx).
+		IntoList(results)
+	return
+}
@@ -16,15 +16,40 @@ See the License for the specific language governing permissions and
 package project
 
 import (
+	"context"
 	"fmt"
+	"github.com/goharbor/harbor/src/controller/tag"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// visibleInitialBackoff and visibleMaxBackoff bound the polling interval of WaitUntilVisible.
+	visibleInitialBackoff = 500 * time.Millisecond
+	visibleMaxBackoff     = 10 * time.Second
 )
 
 type ArtifactInterface interface {
-	Get(name string) (result *model.Artifact, err error)
-	Delete(name string) (err error)
-	List(query *model.Query) (result *[]model.Artifact, err error)
+	Get(ctx context.Context, name string) (result *model.Artifact, err error)
+	GetWithQuery(ctx context.Context, name string, query *model.ArtifactQuery) (result *model.Artifact, err error)
+	Delete(ctx context.Context, name string) (err error)
+	WaitUntilVisible(ctx context.Context, name string) (result *model.Artifact, err error)
+	List(ctx context.Context, query *model.Query) (result *[]model.Artifact, err error)
+	ListWithQuery(ctx context.Context, query *model.ArtifactQuery) (result *[]model.Artifact, err error)
+	ListFields(ctx context.Context, query *model.Query, fields ...string) (result *[]model.Artifact, err error)
+	Manifest(ctx context.Context, reference string) (result *model.Manifest, err error)
+	Scan(ctx context.Context, reference string) (err error)
+	Vulnerabilities(ctx context.Context, reference string) (result map[string]*model.VulnerabilityReport, err error)
+	Icon(ctx context.Context, reference string) (result *model.Icon, err error)
+	ListAccessories(ctx context.Context, reference string, query *model.Query) (result *[]model.Accessory, err error)
+	SBOM(ctx context.Context, reference string) (result *model.SBOM, err error)
+	GenerateSBOM(ctx context.Context, reference string) (err error)
+	ListTags(ctx context.Context, reference string, query *model.Query) (result *[]tag.Tag, err error)
+	CreateTag(ctx context.Context, reference, name string) (err error)
+	DeleteTag(ctx context.Context, reference, name string) (err error)
 }
 
 type artifact struct {
@@ -42,38 +67,230 @@ func newArtifacts(rest rest2.Interface, project, repository string) *artifact {
 	}
 }
 
-func (r *artifact) Get(name string) (result *model.Artifact, err error) {
+func (r *artifact) Get(ctx context.Context, name string) (result *model.Artifact, err error) {
 	result = &model.Artifact{}
 	err = r.client.Get().
 		Project(r.project).
 		Resource("repositories").
 		Name(r.repository).
 		Suffix(fmt.Sprintf("/artifacts/%s", name)).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
-func (r *artifact) List(query *model.Query) (result *[]model.Artifact, err error) {
+// GetWithQuery gets the artifact by reference (tag or digest) with the optional
+// with_* parameters, e.g. to include the scan overview.
+func (r *artifact) GetWithQuery(ctx context.Context, name string, query *model.ArtifactQuery) (result *model.Artifact, err error) {
+	result = &model.Artifact{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s", name)).
+		Params(query).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (r *artifact) List(ctx context.Context, query *model.Query) (result *[]model.Artifact, err error) {
 	result = &[]model.Artifact{}
 	err = r.client.Get().
 		Project(r.project).
 		Resource("repositories").
 		Name(r.repository).
 		Suffix("/artifacts").
-		Params(*query).
-		Do().
-		Into(result)
+		Params(query).
+		Do(ctx).
+		IntoList(result)
 	return
 }
 
-func (r *artifact) Delete(name string) (err error) {
+// ListWithQuery lists the artifacts with the optional with_* parameters, e.g. to include
+// the tags with their signature status.
+func (r *artifact) ListWithQuery(ctx context.Context, query *model.ArtifactQuery) (result *[]model.Artifact, err error) {
+	result = &[]model.Artifact{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix("/artifacts").
+		Params(query).
+		Do(ctx).
+		IntoList(result)
+	return
+}
+
+// ListFields lists the artifacts only decoding the given fields, e.g. model.ArtifactFieldID
+// and model.ArtifactFieldDigest, the other ones are left empty. It uses much less memory
+// than List for inventories of large repositories.
+func (r *artifact) ListFields(ctx context.Context, query *model.Query, fields ...string) (result *[]model.Artifact, err error) {
+	result = &[]model.Artifact{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix("/artifacts").
+		Params(query).
+		Do(ctx).
+		IntoListFields(result, fields...)
+	return
+}
+
+func (r *artifact) Delete(ctx context.Context, name string) (err error) {
 	err = r.client.Delete().
 		Project(r.project).
 		Resource("repositories").
 		Name(r.repository).
 		Suffix(fmt.Sprintf("/artifacts/%s", name)).
-		Do().
+		Do(ctx).
 		Error()
 	return
 }
+
+// WaitUntilVisible polls the artifact by reference (tag or digest), backing off exponentially,
+// until Harbor returns it or ctx is done. Right after a push the artifact may not be visible
+// through the API yet while Harbor extracts its metadata, so pipelines should wait for it
+// before scanning or labeling it. Only a missing artifact is polled again, any other error,
+// e.g. of a permission or of a malformed reference, is returned right away.
+func (r *artifact) WaitUntilVisible(ctx context.Context, name string) (result *model.Artifact, err error) {
+	backoff := visibleInitialBackoff
+	for {
+		result, err = r.Get(ctx, name)
+		if err == nil {
+			return result, nil
+		}
+		if !rest2.IsNotFound(err) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("artifact %s/%s@%s is not visible: %v", r.project, r.repository, name, err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > visibleMaxBackoff {
+			backoff = visibleMaxBackoff
+		}
+	}
+}
+
+// Manifest gets the manifest or the index of the artifact by reference (tag or digest)
+// from the registry API of Harbor, e.g. to read the sizes of its layers.
+func (r *artifact) Manifest(ctx context.Context, reference string) (result *model.Manifest, err error) {
+	repository, err := url.PathUnescape(r.repository)
+	if err != nil {
+		return nil, err
+	}
+	result = &model.Manifest{}
+	err = r.client.Get().
+		AbsPath("/v2", r.project, repository, "manifests", reference).
+		SetHeader("Accept", strings.Join([]string{
+			model.MediaTypeOCIManifest,
+			model.MediaTypeOCIIndex,
+			model.MediaTypeDockerManifest,
+			model.MediaTypeDockerManifestList,
+		}, ", ")).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Scan triggers a vulnerability scan of the artifact by reference (tag or digest), the scan
+// runs asynchronously and its status is reported in the scan overview of the artifact.
+func (r *artifact) Scan(ctx context.Context, reference string) (err error) {
+	return r.scan(ctx, reference).Error()
+}
+
+func (r *artifact) scan(ctx context.Context, reference string) rest2.Result {
+	return r.client.Post().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/scan", reference)).
+		Do(ctx)
+}
+
+// Vulnerabilities gets the vulnerability reports of the artifact by reference (tag or
+// digest), keyed by report mime type, empty if the artifact hasn't been scanned.
+func (r *artifact) Vulnerabilities(ctx context.Context, reference string) (result map[string]*model.VulnerabilityReport, err error) {
+	result = map[string]*model.VulnerabilityReport{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/additions/vulnerabilities", reference)).
+		Do(ctx).
+		Into(&result)
+	return
+}
+
+// Icon gets the icon of the type of the artifact by reference (tag or digest), e.g. the
+// Helm icon for a chart, nil if the artifact has no icon.
+func (r *artifact) Icon(ctx context.Context, reference string) (result *model.Icon, err error) {
+	artifact, err := r.Get(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if artifact.Icon == "" {
+		return nil, nil
+	}
+	result = &model.Icon{}
+	err = r.client.Get().
+		Resource("icons").
+		Name(artifact.Icon).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ListAccessories lists the accessories attached to the artifact by reference (tag or
+// digest), e.g. its cosign signatures, SBOMs and nydus conversions, query filtering them
+// e.g. by type with q=type=signature.cosign.
+func (r *artifact) ListAccessories(ctx context.Context, reference string, query *model.Query) (result *[]model.Accessory, err error) {
+	if query == nil {
+		query = &model.Query{}
+	}
+	result = &[]model.Accessory{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/accessories", reference)).
+		Params(query).
+		Do(ctx).
+		IntoList(result)
+	return
+}
+
+// SBOM gets the software bill of materials of the artifact by reference (tag or digest) as
+// generated by Harbor 2.10 and later, see GenerateSBOM.
+func (r *artifact) SBOM(ctx context.Context, reference string) (result *model.SBOM, err error) {
+	result = &model.SBOM{}
+	result.Data, err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/additions/sbom", reference)).
+		Do(ctx).
+		ContentType(&result.MediaType).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GenerateSBOM triggers the generation of the software bill of materials of the artifact by
+// reference (tag or digest), it runs asynchronously like a scan and requires Harbor 2.10
+// or later with a scanner supporting SBOMs.
+func (r *artifact) GenerateSBOM(ctx context.Context, reference string) (err error) {
+	return r.client.Post().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/scan", reference)).
+		Body(&model.ScanRequest{ScanType: model.ScanTypeSBOM}).
+		Do(ctx).
+		Error()
+}
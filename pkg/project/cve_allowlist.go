@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"strconv"
+)
+
+// projectCVEAllowlist is the subset of the project model carrying its CVE allowlist, it
+// is used both to read the project and as the body to update it.
+type projectCVEAllowlist struct {
+	Metadata     map[string]string   `json:"metadata,omitempty"`
+	CVEAllowlist *model.CVEAllowlist `json:"cve_allowlist,omitempty"`
+}
+
+// GetCVEAllowlist gets the CVE allowlist of a project and whether the project uses the
+// system allowlist instead.
+func (p *ProjectsV2Client) GetCVEAllowlist(ctx context.Context, name string) (result *model.ProjectCVEAllowlist, err error) {
+	project := &projectCVEAllowlist{}
+	err = p.restClient.Get().
+		Resource("projects").
+		Name(name).
+		Do(ctx).
+		Into(project)
+	if err != nil {
+		return nil, err
+	}
+	// the system allowlist is used unless the project opted out explicitly
+	reuse, parseErr := strconv.ParseBool(project.Metadata[model.ProjectMetadataReuseSysCVEAllowlist])
+	result = &model.ProjectCVEAllowlist{
+		ReuseSystem: parseErr != nil || reuse,
+		Allowlist:   project.CVEAllowlist,
+	}
+	if result.Allowlist == nil {
+		result.Allowlist = &model.CVEAllowlist{Items: []model.CVEAllowlistItem{}}
+	}
+	return result, nil
+}
+
+// SetCVEAllowlist makes the project use its own CVE allowlist instead of the system one and
+// replaces it with allowlist.
+func (p *ProjectsV2Client) SetCVEAllowlist(ctx context.Context, name string, allowlist *model.CVEAllowlist) (err error) {
+	return p.updateCVEAllowlist(ctx, name, &projectCVEAllowlist{
+		Metadata:     map[string]string{model.ProjectMetadataReuseSysCVEAllowlist: "false"},
+		CVEAllowlist: allowlist,
+	})
+}
+
+// UseSystemCVEAllowlist makes the project use the system CVE allowlist, its own allowlist is
+// kept but ignored.
+func (p *ProjectsV2Client) UseSystemCVEAllowlist(ctx context.Context, name string) (err error) {
+	return p.updateCVEAllowlist(ctx, name, &projectCVEAllowlist{
+		Metadata: map[string]string{model.ProjectMetadataReuseSysCVEAllowlist: "true"},
+	})
+}
+
+func (p *ProjectsV2Client) updateCVEAllowlist(ctx context.Context, name string, body *projectCVEAllowlist) error {
+	return p.restClient.Put().
+		Resource("projects").
+		Name(name).
+		Body(body).
+		Do(ctx).
+		Error()
+}
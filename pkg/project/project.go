@@ -16,6 +16,7 @@ See the License for the specific language governing permissions and
 package project
 
 import (
+	"context"
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
@@ -32,31 +33,56 @@ type ProjectsV2Client struct {
 	restClient rest2.Interface
 }
 
-func (p *ProjectsV2Client) Get(name string) (result *models.Project, err error) {
+func (p *ProjectsV2Client) Get(ctx context.Context, name string) (result *models.Project, err error) {
 	result = &models.Project{}
 	err = p.restClient.Get().
 		Resource("projects").
 		Name(name).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
-func (p *ProjectsV2Client) List(query *model.Query) (results *[]models.Project, err error) {
+// Summary gets the repository count, quota usage and member counts of a project.
+func (p *ProjectsV2Client) Summary(ctx context.Context, name string) (result *model.ProjectSummary, err error) {
+	result = &model.ProjectSummary{}
+	err = p.restClient.Get().
+		Resource("projects").
+		Name(name).
+		Suffix("summary").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Deletable checks whether a project can be deleted, i.e. it has no repository, chart
+// or policy left, deleting it otherwise fails with a 412.
+func (p *ProjectsV2Client) Deletable(ctx context.Context, name string) (result *model.ProjectDeletable, err error) {
+	result = &model.ProjectDeletable{}
+	err = p.restClient.Get().
+		Resource("projects").
+		Name(name).
+		Suffix("_deletable").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (p *ProjectsV2Client) List(ctx context.Context, query *model.Query) (results *[]models.Project, err error) {
 	results = &[]models.Project{}
 	err = p.restClient.List().
 		Resource("projects").
-		Params(*query).
-		Do().
-		Into(results)
+		Params(query).
+		Do(ctx).
+		IntoList(results)
 	return
 }
 
-func (p *ProjectsV2Client) Delete(name string) (err error) {
+func (p *ProjectsV2Client) Delete(ctx context.Context, name string) (err error) {
 	err = p.restClient.Delete().
 		Resource("projects").
 		Name(name).
-		Do().
+		Do(ctx).
 		Error()
 	return
 }
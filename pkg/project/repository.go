@@ -16,15 +16,16 @@ See the License for the specific language governing permissions and
 package project
 
 import (
+	"context"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
 )
 
 type RepositoryInterface interface {
 	Artifacts(repository string) *artifact
-	List(query *model.Query) (result *[]model.RepoRecord, err error)
-	Get(name string) (result *model.RepoRecord, err error)
-	Delete(name string) (err error)
+	List(ctx context.Context, query *model.Query) (result *[]model.RepoRecord, err error)
+	Get(ctx context.Context, name string) (result *model.RepoRecord, err error)
+	Delete(ctx context.Context, name string) (err error)
 	//Put()
 }
 
@@ -43,34 +44,34 @@ func newRepositories(
 	}
 }
 
-func (r *repository) Get(name string) (result *model.RepoRecord, err error) {
+func (r *repository) Get(ctx context.Context, name string) (result *model.RepoRecord, err error) {
 	result = &model.RepoRecord{}
 	err = r.client.Get().
 		Project(r.project).
 		Resource("repositories").
 		Name(name).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
-func (r *repository) List(query *model.Query) (result *[]model.RepoRecord, err error) {
+func (r *repository) List(ctx context.Context, query *model.Query) (result *[]model.RepoRecord, err error) {
 	result = &[]model.RepoRecord{}
 	err = r.client.Get().
 		Project(r.project).
 		Resource("repositories").
-		Params(*query).
-		Do().
-		Into(result)
+		Params(query).
+		Do(ctx).
+		IntoList(result)
 	return
 }
 
-func (r *repository) Delete(name string) (err error) {
+func (r *repository) Delete(ctx context.Context, name string) (err error) {
 	err = r.client.Delete().
 		Project(r.project).
 		Resource("repositories").
 		Name(name).
-		Do().
+		Do(ctx).
 		Error()
 	return
 }
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// DefaultRetagConcurrency is the default number of artifacts tagged at the same time.
+const DefaultRetagConcurrency = 10
+
+// RetagConflictPolicy tells BulkRetag what to do when the new tag is already on another
+// artifact of the repository.
+type RetagConflictPolicy string
+
+const (
+	// RetagSkip leaves the tag on the other artifact.
+	RetagSkip RetagConflictPolicy = "skip"
+	// RetagOverwrite moves the tag unless it is immutable, it is skipped otherwise. If the
+	// tag can't be added to the artifact, it is put back on the other one.
+	RetagOverwrite RetagConflictPolicy = "overwrite-if-not-immutable"
+	// RetagFail fails the item and cancels the items not started yet.
+	RetagFail RetagConflictPolicy = "fail"
+)
+
+// Statuses of the items of BulkRetag
+const (
+	RetagStatusCreated     = "created"
+	RetagStatusOverwritten = "overwritten"
+	// RetagStatusUnchanged is the status of the artifacts which already have the tag.
+	RetagStatusUnchanged = "unchanged"
+	RetagStatusSkipped   = "skipped"
+	RetagStatusFailed    = "failed"
+)
+
+// RetagMapping adds Tag to an artifact.
+type RetagMapping struct {
+	Project    string `json:"project"`
+	Repository string `json:"repository"`
+	// Reference is the tag or the digest of the artifact.
+	Reference string `json:"reference"`
+	Tag       string `json:"tag"`
+}
+
+// RetagResult is the outcome of a mapping.
+type RetagResult struct {
+	RetagMapping
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RetagOptions holds the optional settings of BulkRetag.
+type RetagOptions struct {
+	// Concurrency is the maximum number of artifacts tagged at the same time,
+	// DefaultRetagConcurrency by default.
+	Concurrency int
+}
+
+// BulkRetag adds new tags to a set of artifacts, e.g. a -stable suffix to the tags of a
+// release, handling the tags already on other artifacts according to policy. It returns
+// the outcome of every mapping in their order, along with the conflict error with the
+// RetagFail policy or the context error, in which case the items not started are failed.
+func (p *ProjectsV2Client) BulkRetag(ctx context.Context, mappings []RetagMapping, policy RetagConflictPolicy, opts *RetagOptions) ([]*RetagResult, error) {
+	if opts == nil {
+		opts = &RetagOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRetagConcurrency
+	}
+	switch policy {
+	case RetagSkip, RetagOverwrite, RetagFail:
+	default:
+		return nil, fmt.Errorf("invalid retag conflict policy %q", policy)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	results := make([]*RetagResult, len(mappings))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := results[i]
+				if err := ctx.Err(); err != nil {
+					result.Status, result.Error = RetagStatusFailed, err.Error()
+					continue
+				}
+				if err := p.retag(ctx, result, policy); err != nil && policy == RetagFail {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range mappings {
+		results[i] = &RetagResult{RetagMapping: mappings[i]}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// retag applies the mapping of result and returns the conflict error, if any.
+func (p *ProjectsV2Client) retag(ctx context.Context, result *RetagResult, policy RetagConflictPolicy) error {
+	artifacts := newArtifacts(p.restClient, result.Project, url.PathEscape(result.Repository))
+	fail := func(err error) error {
+		result.Status, result.Error = RetagStatusFailed, err.Error()
+		return nil
+	}
+	source, err := artifacts.Get(ctx, result.Reference)
+	if err != nil {
+		return fail(fmt.Errorf("get artifact error: %v", err))
+	}
+
+	// look the new tag up in the repository to detect the conflicts
+	current := &model.Artifact{}
+	var code int
+	err = p.restClient.Get().
+		Project(result.Project).
+		Resource("repositories").
+		Name(url.PathEscape(result.Repository)).
+		Suffix(fmt.Sprintf("/artifacts/%s", result.Tag)).
+		Params(model.ArtifactQuery{WithTag: true, WithImmutableStatus: true}).
+		Do(ctx).
+		StatusCode(&code).
+		Into(current)
+	switch {
+	case code == http.StatusNotFound:
+		if err := artifacts.CreateTag(ctx, source.Digest, result.Tag); err != nil {
+			return fail(err)
+		}
+		result.Status = RetagStatusCreated
+		return nil
+	case err != nil:
+		return fail(fmt.Errorf("get tag %s error: %v", result.Tag, err))
+	case current.Digest == source.Digest:
+		result.Status = RetagStatusUnchanged
+		return nil
+	}
+
+	conflict := fmt.Errorf("tag %s is on artifact %s", result.Tag, current.Digest)
+	switch policy {
+	case RetagSkip:
+		result.Status, result.Error = RetagStatusSkipped, conflict.Error()
+		return nil
+	case RetagFail:
+		result.Status, result.Error = RetagStatusFailed, conflict.Error()
+		return conflict
+	}
+	for _, t := range current.Tags {
+		if t.Name == result.Tag && t.Immutable {
+			result.Status, result.Error = RetagStatusSkipped, fmt.Sprintf("%v and immutable", conflict)
+			return nil
+		}
+	}
+	if err := artifacts.DeleteTag(ctx, current.Digest, result.Tag); err != nil {
+		return fail(fmt.Errorf("remove tag %s from artifact %s error: %v", result.Tag, current.Digest, err))
+	}
+	if err := artifacts.CreateTag(ctx, source.Digest, result.Tag); err != nil {
+		// put the tag back on the artifact it was removed from, even if ctx is done
+		restoreCtx := ctx
+		if ctx.Err() != nil {
+			restoreCtx = context.Background()
+		}
+		if restoreErr := artifacts.CreateTag(restoreCtx, current.Digest, result.Tag); restoreErr != nil {
+			return fail(fmt.Errorf("%v, restore tag %s on artifact %s error: %v", err, result.Tag, current.Digest, restoreErr))
+		}
+		return fail(fmt.Errorf("%v, tag %s restored on artifact %s", err, result.Tag, current.Digest))
+	}
+	result.Status = RetagStatusOverwritten
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRepository serves the artifacts and the tags of library/app.
+type fakeRepository struct {
+	mu        sync.Mutex
+	digests   []string
+	tags      map[string]string
+	immutable map[string]bool
+	// failCreate rejects adding the tags, keyed by digest/tag
+	failCreate map[string]bool
+}
+
+func (f *fakeRepository) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	const prefix = "/api/v2.0/projects/library/repositories/app/artifacts/"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		http.NotFound(w, req)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, prefix), "/")
+	switch {
+	case req.Method == http.MethodGet && len(parts) == 1:
+		digest := parts[0]
+		if tagged, ok := f.tags[digest]; ok {
+			digest = tagged
+		} else if !f.hasDigest(digest) {
+			http.NotFound(w, req)
+			return
+		}
+		var tags []map[string]interface{}
+		for name, tagged := range f.tags {
+			if tagged == digest {
+				tags = append(tags, map[string]interface{}{"name": name, "immutable": f.immutable[name]})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"digest": digest, "tags": tags})
+	case req.Method == http.MethodPost && len(parts) == 2:
+		var body struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		if f.failCreate[parts[0]+"/"+body.Name] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		f.tags[body.Name] = parts[0]
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodDelete && len(parts) == 3:
+		delete(f.tags, parts[2])
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeRepository) hasDigest(digest string) bool {
+	for _, d := range f.digests {
+		if d == digest {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRepository) tagged(tag string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tags[tag]
+}
+
+// retagFixture serves a repository whose artifact sha256:a is tagged v1 and sha256:b v2,
+// stable and the immutable locked.
+func retagFixture(t *testing.T) (*fakeRepository, *ProjectsV2Client, func()) {
+	repository := &fakeRepository{
+		digests:    []string{"sha256:a", "sha256:b"},
+		tags:       map[string]string{"v1": "sha256:a", "v2": "sha256:b", "stable": "sha256:b", "locked": "sha256:b"},
+		immutable:  map[string]bool{"locked": true},
+		failCreate: map[string]bool{},
+	}
+	server := httptest.NewServer(repository)
+	client, err := NewProjectsV1Client(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return repository, client, server.Close
+}
+
+func retagMappings(pairs ...string) []RetagMapping {
+	var mappings []RetagMapping
+	for i := 0; i < len(pairs); i += 2 {
+		mappings = append(mappings, RetagMapping{Project: "library", Repository: "app", Reference: pairs[i], Tag: pairs[i+1]})
+	}
+	return mappings
+}
+
+func checkRetagStatuses(t *testing.T, results []*RetagResult, statuses ...string) {
+	t.Helper()
+	if len(results) != len(statuses) {
+		t.Fatalf("expected %d results, got %d", len(statuses), len(results))
+	}
+	for i, result := range results {
+		if result.Status != statuses[i] {
+			t.Errorf("%s -> %s: expected %s, got %s (%s)", result.Reference, result.Tag, statuses[i], result.Status, result.Error)
+		}
+	}
+}
+
+func TestBulkRetagSkip(t *testing.T) {
+	repository, client, stop := retagFixture(t)
+	defer stop()
+
+	results, err := client.BulkRetag(context.Background(), retagMappings("v1", "stable", "v1", "new", "v2", "stable"), RetagSkip, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRetagStatuses(t, results, RetagStatusSkipped, RetagStatusCreated, RetagStatusUnchanged)
+	if digest := repository.tagged("stable"); digest != "sha256:b" {
+		t.Errorf("expected the skipped tag to stay on sha256:b, got %s", digest)
+	}
+	if digest := repository.tagged("new"); digest != "sha256:a" {
+		t.Errorf("expected the new tag on sha256:a, got %s", digest)
+	}
+}
+
+func TestBulkRetagOverwrite(t *testing.T) {
+	repository, client, stop := retagFixture(t)
+	defer stop()
+
+	results, err := client.BulkRetag(context.Background(), retagMappings("v1", "stable", "v1", "locked"), RetagOverwrite, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRetagStatuses(t, results, RetagStatusOverwritten, RetagStatusSkipped)
+	if !strings.Contains(results[1].Error, "immutable") {
+		t.Errorf("expected the immutable tag to be reported, got %q", results[1].Error)
+	}
+	if digest := repository.tagged("stable"); digest != "sha256:a" {
+		t.Errorf("expected the tag to be moved to sha256:a, got %s", digest)
+	}
+	if digest := repository.tagged("locked"); digest != "sha256:b" {
+		t.Errorf("expected the immutable tag to stay on sha256:b, got %s", digest)
+	}
+}
+
+func TestBulkRetagOverwriteRestoresTag(t *testing.T) {
+	repository, client, stop := retagFixture(t)
+	defer stop()
+	repository.failCreate["sha256:a/stable"] = true
+
+	results, err := client.BulkRetag(context.Background(), retagMappings("v1", "stable"), RetagOverwrite, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRetagStatuses(t, results, RetagStatusFailed)
+	if !strings.Contains(results[0].Error, "403") || !strings.Contains(results[0].Error, "restored on artifact sha256:b") {
+		t.Errorf("expected both the failure and the restoration to be reported, got %q", results[0].Error)
+	}
+	if digest := repository.tagged("stable"); digest != "sha256:b" {
+		t.Errorf("expected the tag to be put back on sha256:b, got %q", digest)
+	}
+}
+
+func TestBulkRetagFailCancelsPending(t *testing.T) {
+	repository, client, stop := retagFixture(t)
+	defer stop()
+
+	results, err := client.BulkRetag(context.Background(), retagMappings("v1", "stable", "v1", "new"), RetagFail, &RetagOptions{Concurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "tag stable is on artifact sha256:b") {
+		t.Fatalf("expected the conflict error, got %v", err)
+	}
+	checkRetagStatuses(t, results, RetagStatusFailed, RetagStatusFailed)
+	if results[1].Error != context.Canceled.Error() {
+		t.Errorf("expected the pending item to be canceled, got %q", results[1].Error)
+	}
+	if digest := repository.tagged("new"); digest != "" {
+		t.Errorf("expected the pending item not to be applied, got the tag on %s", digest)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultScanConcurrency is the default number of scans running at the same time.
+	DefaultScanConcurrency = 10
+	// defaultScanPollInterval and defaultScanRetryInterval are the default intervals of
+	// ScanArtifacts to poll the running scans and to re-dispatch rejected scans.
+	defaultScanPollInterval  = 5 * time.Second
+	defaultScanRetryInterval = 10 * time.Second
+)
+
+// ScanTarget is an artifact to scan.
+type ScanTarget struct {
+	Project    string `json:"project"`
+	Repository string `json:"repository"`
+	// Reference is the tag or the digest of the artifact.
+	Reference string `json:"reference"`
+}
+
+// ScanResult is the final status of the scan of an artifact.
+type ScanResult struct {
+	ScanTarget
+	// Status is the scan status, e.g. Success or Error, or empty if the scan could not
+	// be triggered.
+	Status   string `json:"status"`
+	Severity string `json:"severity,omitempty"`
+	// Attempts is the number of times the scan was triggered, scans rejected by Harbor
+	// because the scanner is busy are re-dispatched.
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ScanOptions holds the optional settings of ScanArtifacts.
+type ScanOptions struct {
+	// Concurrency is the maximum number of scans running at the same time,
+	// DefaultScanConcurrency by default.
+	Concurrency int
+	// PollInterval is the interval the status of the running scans is polled at.
+	PollInterval time.Duration
+	// RetryInterval is the delay before re-dispatching a rejected scan when Harbor does
+	// not send a Retry-After header.
+	RetryInterval time.Duration
+	// MaxAttempts is the maximum number of times a scan is triggered, unlimited if 0.
+	MaxAttempts int
+}
+
+// scanJob tracks the scan of a target.
+type scanJob struct {
+	result    *ScanResult
+	notBefore time.Time
+}
+
+// ScanArtifacts scans a large set of artifacts, e.g. to rescan everything after a new CVE
+// drop, without overwhelming the scanner: at most opts.Concurrency scans run at the same
+// time and the scans Harbor rejects with 409 Conflict or 429 Too Many Requests are queued
+// and re-dispatched after the Retry-After delay. It returns once every scan is over with
+// the final status of each artifact, in the order of targets. If ctx is done first, the
+// unfinished scans are reported with the context error, which is also returned.
+func (p *ProjectsV2Client) ScanArtifacts(ctx context.Context, targets []ScanTarget, opts *ScanOptions) ([]*ScanResult, error) {
+	if opts == nil {
+		opts = &ScanOptions{}
+	}
+	concurrency, pollInterval, retryInterval := opts.Concurrency, opts.PollInterval, opts.RetryInterval
+	if concurrency <= 0 {
+		concurrency = DefaultScanConcurrency
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultScanPollInterval
+	}
+	if retryInterval <= 0 {
+		retryInterval = defaultScanRetryInterval
+	}
+
+	results := make([]*ScanResult, len(targets))
+	var pending, running []*scanJob
+	for i := range targets {
+		results[i] = &ScanResult{ScanTarget: targets[i]}
+		pending = append(pending, &scanJob{result: results[i]})
+	}
+	// paused holds back every dispatch after a rejection, the scanner is busy for all
+	var paused time.Time
+
+	for len(pending) > 0 || len(running) > 0 {
+		now := time.Now()
+		for len(running) < concurrency && len(pending) > 0 && !now.Before(paused) && !now.Before(pending[0].notBefore) {
+			job := pending[0]
+			pending = pending[1:]
+			job.result.Attempts++
+			result := p.artifacts(job.result.ScanTarget).scan(ctx, job.result.Reference)
+			var code int
+			result.StatusCode(&code)
+			switch {
+			case code == http.StatusConflict || code == http.StatusTooManyRequests:
+				if opts.MaxAttempts > 0 && job.result.Attempts >= opts.MaxAttempts {
+					job.result.Error = result.Error().Error()
+					continue
+				}
+				delay, ok := result.RetryAfter()
+				if !ok {
+					delay = retryInterval
+				}
+				job.notBefore = now.Add(delay)
+				paused = job.notBefore
+				pending = append(pending, job)
+			case result.Error() != nil:
+				job.result.Error = result.Error().Error()
+			default:
+				running = append(running, job)
+			}
+		}
+
+		wait := pollInterval
+		if len(running) == 0 {
+			if len(pending) == 0 {
+				break
+			}
+			// nothing to poll, wait until the first pending job can be dispatched
+			next := pending[0].notBefore
+			if paused.After(next) {
+				next = paused
+			}
+			wait = time.Until(next)
+		}
+		select {
+		case <-ctx.Done():
+			for _, job := range append(pending, running...) {
+				job.result.Error = ctx.Err().Error()
+			}
+			return results, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		remaining := running[:0]
+		for _, job := range running {
+			if !p.pollScan(ctx, job.result) {
+				remaining = append(remaining, job)
+			}
+		}
+		running = remaining
+	}
+	return results, nil
+}
+
+func (p *ProjectsV2Client) artifacts(target ScanTarget) *artifact {
+	return newArtifacts(p.restClient, target.Project, url.PathEscape(target.Repository))
+}
+
+// pollScan updates the status of a running scan and returns true if it is over. Errors
+// getting the artifact are recorded. The scan is over when the artifact is gone or can't
+// be read with the credentials of the client, other errors, e.g. a timeout, are polled
+// again.
+func (p *ProjectsV2Client) pollScan(ctx context.Context, result *ScanResult) bool {
+	artifact, err := p.artifacts(result.ScanTarget).GetWithQuery(ctx, result.Reference, &model.ArtifactQuery{WithScanOverview: true})
+	if err != nil {
+		result.Error = fmt.Sprintf("get scan status error: %v", err)
+		return rest2.IsNotFound(err) || rest2.IsForbidden(err) || rest2.IsUnauthorized(err)
+	}
+	result.Error = ""
+	for _, overview := range artifact.ScanOverview {
+		if overview == nil {
+			continue
+		}
+		result.Status, result.Severity = overview.ScanStatus, overview.Severity
+		return overview.Done()
+	}
+	return false
+}
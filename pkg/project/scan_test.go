@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScanner serves the scans of the artifacts of library/app. The scan of a reference
+// reports Running on the first poll and statuses[reference] afterwards, the references
+// in codes answer the polls with that status code instead and the ones in scanCodes answer
+// the scans with that status code.
+type fakeScanner struct {
+	statuses  map[string]string
+	codes     map[string]int
+	scanCodes map[string]int
+
+	mu    sync.Mutex
+	polls map[string]int
+}
+
+func (f *fakeScanner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/api/v2.0/projects/library/repositories/app/artifacts/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.NotFound(w, r)
+		return
+	}
+	reference := strings.TrimPrefix(r.URL.Path, prefix)
+	if r.Method == http.MethodPost && strings.HasSuffix(reference, "/scan") {
+		if code, ok := f.scanCodes[strings.TrimSuffix(reference, "/scan")]; ok {
+			w.WriteHeader(code)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if code, ok := f.codes[reference]; ok {
+		w.WriteHeader(code)
+		return
+	}
+	f.mu.Lock()
+	f.polls[reference]++
+	status := model.ScanStatusRunning
+	if f.polls[reference] > 1 {
+		status = f.statuses[reference]
+	}
+	f.mu.Unlock()
+	json.NewEncoder(w).Encode(&model.Artifact{ScanOverview: map[string]*model.ScanOverview{
+		"application/vnd.security.vulnerability.report; version=1.1": {ScanStatus: status, Severity: "High"},
+	}})
+}
+
+func TestScanArtifacts(t *testing.T) {
+	scanner := &fakeScanner{
+		statuses: map[string]string{"ok": model.ScanStatusSuccess, "broken": model.ScanStatusError},
+		codes:    map[string]int{"gone": http.StatusNotFound, "denied": http.StatusForbidden},
+		polls:    map[string]int{},
+	}
+	server := httptest.NewServer(scanner)
+	defer server.Close()
+	client, err := NewProjectsV1Client(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var targets []ScanTarget
+	for _, reference := range []string{"ok", "broken", "gone", "denied"} {
+		targets = append(targets, ScanTarget{Project: "library", Repository: "app", Reference: reference})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results, err := client.ScanArtifacts(ctx, targets, &ScanOptions{PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+
+	expected := []struct {
+		status string
+		failed bool
+	}{
+		{model.ScanStatusSuccess, false},
+		{model.ScanStatusError, false},
+		{"", true},
+		{"", true},
+	}
+	for i, result := range results {
+		if result.Status != expected[i].status || result.Attempts != 1 {
+			t.Errorf("%s: expected status %q after 1 attempt, got %q after %d", result.Reference, expected[i].status, result.Status, result.Attempts)
+		}
+		if !expected[i].failed && result.Error != "" {
+			t.Errorf("%s: unexpected error %s", result.Reference, result.Error)
+		}
+		if expected[i].failed && !strings.Contains(result.Error, "get scan status error") {
+			t.Errorf("%s: expected the error getting the status to be recorded, got %q", result.Reference, result.Error)
+		}
+	}
+	if results[0].Severity != "High" {
+		t.Errorf("expected the severity to be recorded, got %q", results[0].Severity)
+	}
+}
+
+func TestScanArtifactsWithoutRunningScans(t *testing.T) {
+	scanner := &fakeScanner{
+		scanCodes: map[string]int{"broken": http.StatusInternalServerError, "busy": http.StatusTooManyRequests},
+		polls:     map[string]int{},
+	}
+	server := httptest.NewServer(scanner)
+	defer server.Close()
+	client, err := NewProjectsV1Client(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no scan is ever running, nothing is polled and the poll interval is never waited
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, err := client.ScanArtifacts(ctx, []ScanTarget{
+		{Project: "library", Repository: "app", Reference: "broken"},
+		{Project: "library", Repository: "app", Reference: "busy"},
+	}, &ScanOptions{PollInterval: time.Hour, RetryInterval: 10 * time.Millisecond, MaxAttempts: 2})
+	if err != nil {
+		t.Fatalf("scan error: %v", err)
+	}
+	for i, attempts := range []int{1, 2} {
+		if results[i].Error == "" || results[i].Attempts != attempts {
+			t.Errorf("%s: expected an error after %d attempts, got %q after %d", results[i].Reference, attempts, results[i].Error, results[i].Attempts)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// GetScanner gets the scanner registration used to scan the artifacts of a project, the
+// default one unless another one was assigned to the project.
+func (p *ProjectsV2Client) GetScanner(ctx context.Context, name string) (result *model.ScannerRegistration, err error) {
+	result = &model.ScannerRegistration{}
+	err = p.restClient.Get().
+		Resource("projects").
+		Name(name).
+		Suffix("scanner").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// SetScanner assigns the scanner registration uuid to a project.
+func (p *ProjectsV2Client) SetScanner(ctx context.Context, name, uuid string) (err error) {
+	return p.restClient.Put().
+		Resource("projects").
+		Name(name).
+		Suffix("scanner").
+		Body(&model.ProjectScanner{UUID: uuid}).
+		Do(ctx).
+		Error()
+}
+
+// ScannerCandidates lists the scanner registrations that can be assigned to a project.
+func (p *ProjectsV2Client) ScannerCandidates(ctx context.Context, name string, query *model.Query) (result *[]model.ScannerRegistration, err error) {
+	if query == nil {
+		query = &model.Query{}
+	}
+	result = &[]model.ScannerRegistration{}
+	err = p.restClient.Get().
+		Resource("projects").
+		Name(name).
+		Suffix("scanner", "candidates").
+		Params(query).
+		Do(ctx).
+		IntoList(result)
+	return
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"github.com/goharbor/harbor/src/controller/tag"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// tagRequest is the body creating a tag.
+type tagRequest struct {
+	Name string `json:"name"`
+}
+
+// ListTags lists the tags of the artifact by reference (tag or digest).
+func (r *artifact) ListTags(ctx context.Context, reference string, query *model.Query) (result *[]tag.Tag, err error) {
+	if query == nil {
+		query = &model.Query{}
+	}
+	result = &[]tag.Tag{}
+	err = r.client.Get().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/tags", reference)).
+		Params(query).
+		Do(ctx).
+		IntoList(result)
+	return
+}
+
+// CreateTag tags the artifact by reference (tag or digest) with name, it fails with a 409
+// if another artifact of the repository has the tag.
+func (r *artifact) CreateTag(ctx context.Context, reference, name string) (err error) {
+	return r.client.Post().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/tags", reference)).
+		Body(&tagRequest{Name: name}).
+		Do(ctx).
+		Error()
+}
+
+// DeleteTag removes the tag name from the artifact by reference (tag or digest), the
+// artifact itself is kept.
+func (r *artifact) DeleteTag(ctx context.Context, reference, name string) (err error) {
+	return r.client.Delete().
+		Project(r.project).
+		Resource("repositories").
+		Name(r.repository).
+		Suffix(fmt.Sprintf("/artifacts/%s/tags/%s", reference, name)).
+		Do(ctx).
+		Error()
+}
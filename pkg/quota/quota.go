@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package quota
+
+import (
+	"context"
+	"fmt"
+	"github.com/goharbor/harbor/src/common/models"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strconv"
+)
+
+// QuotasInterface holds the methods of the quota APIs.
+type QuotasInterface interface {
+	Get(ctx context.Context, id int64) (result *model.Quota, err error)
+	GetByProject(ctx context.Context, projectIDOrName string) (result *model.Quota, err error)
+	List(ctx context.Context, query *model.QuotaQuery) (results *[]model.Quota, err error)
+	Update(ctx context.Context, id int64, hard map[string]int64) (err error)
+}
+
+// QuotasClient is used to read and update the quotas of projects.
+type QuotasClient struct {
+	restClient rest2.Interface
+}
+
+func NewQuotasClient(restClient *rest2.Config) (*QuotasClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotasClient{restClient: client}, nil
+}
+
+func (q *QuotasClient) Get(ctx context.Context, id int64) (result *model.Quota, err error) {
+	result = &model.Quota{}
+	err = q.restClient.Get().
+		Resource("quotas").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetByProject gets the quota of a project given its ID or its name, a name being
+// resolved to the project ID first since quotas reference projects by ID.
+func (q *QuotasClient) GetByProject(ctx context.Context, projectIDOrName string) (result *model.Quota, err error) {
+	projectID := projectIDOrName
+	if _, err := strconv.ParseInt(projectIDOrName, 10, 64); err != nil {
+		project := &models.Project{}
+		err = q.restClient.Get().
+			Resource("projects").
+			Name(projectIDOrName).
+			Do(ctx).
+			Into(project)
+		if err != nil {
+			return nil, fmt.Errorf("get project %s error: %v", projectIDOrName, err)
+		}
+		projectID = strconv.FormatInt(project.ProjectID, 10)
+	}
+	results, err := q.List(ctx, &model.QuotaQuery{
+		Reference:   model.QuotaReferenceProject,
+		ReferenceID: projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range *results {
+		if ref := (*results)[i].Ref; ref != nil && strconv.FormatInt(ref.ID, 10) == projectID {
+			return &(*results)[i], nil
+		}
+	}
+	return nil, fmt.Errorf("quota of project %s %w", projectIDOrName, rest2.ErrNotFound)
+}
+
+func (q *QuotasClient) List(ctx context.Context, query *model.QuotaQuery) (results *[]model.Quota, err error) {
+	results = &[]model.Quota{}
+	err = q.restClient.List().
+		Resource("quotas").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// Update sets the limits of a quota, keyed by resource, e.g. storage in bytes.
+func (q *QuotasClient) Update(ctx context.Context, id int64, hard map[string]int64) (err error) {
+	return q.restClient.Put().
+		Resource("quotas").
+		Name(strconv.FormatInt(id, 10)).
+		Body(&model.QuotaUpdate{Hard: hard}).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package reference
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Reference is an image reference of the form [host/]project/repository[:tag][@digest].
+type Reference struct {
+	Host string
+	// Project is empty if the reference has no project part, e.g. docker hub library images.
+	Project    string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Parse splits an image reference, the tag defaults to latest when there is no digest.
+func Parse(ref string) Reference {
+	var r Reference
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, r.Digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, r.Tag = name[:i], name[i+1:]
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = "latest"
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		r.Host, parts = parts[0], parts[1:]
+	}
+	if len(parts) < 2 {
+		r.Repository = strings.Join(parts, "/")
+		return r
+	}
+	r.Project, r.Repository = parts[0], strings.Join(parts[1:], "/")
+	return r
+}
+
+// Name returns the reference without tag and digest.
+func (r Reference) Name() string {
+	var parts []string
+	for _, part := range []string{r.Host, r.Project, r.Repository} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Reference returns the digest, or the tag if there is no digest.
+func (r Reference) Reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// String returns the reference, with the digest only if it has both a tag and a digest.
+func (r Reference) String() string {
+	if r.Digest != "" {
+		return r.Name() + "@" + r.Digest
+	}
+	if r.Tag != "" {
+		return r.Name() + ":" + r.Tag
+	}
+	return r.Name()
+}
+
+// HostRewriter maps the hostname of the Harbor API to the hostname images are pulled with.
+// Split-horizon deployments often call the API through an internal endpoint while images
+// are pulled through an external one, references returned to users must use the latter
+// and references read back must be resolved against the former.
+type HostRewriter struct {
+	apiHost  string
+	pullHost string
+}
+
+// NewHostRewriter returns a HostRewriter between the two hosts, given as host[:port] or as
+// URLs. If pullHost is empty, images are pulled with the API host.
+func NewHostRewriter(apiHost, pullHost string) *HostRewriter {
+	h := &HostRewriter{apiHost: hostOf(apiHost), pullHost: hostOf(pullHost)}
+	if h.pullHost == "" {
+		h.pullHost = h.apiHost
+	}
+	return h
+}
+
+// hostOf returns the host[:port] of a host or of a URL.
+func hostOf(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return strings.TrimSuffix(host, "/")
+}
+
+// APIHost returns the hostname of the API.
+func (h *HostRewriter) APIHost() string {
+	return h.apiHost
+}
+
+// PullHost returns the hostname images are pulled with.
+func (h *HostRewriter) PullHost() string {
+	return h.pullHost
+}
+
+// IsHarbor returns true if host is either the API host or the pull host.
+func (h *HostRewriter) IsHarbor(host string) bool {
+	host = hostOf(host)
+	return host == h.apiHost || host == h.pullHost
+}
+
+// ToPull rewrites a reference using the API host to use the pull host, other references
+// are returned unchanged.
+func (h *HostRewriter) ToPull(ref string) string {
+	return h.rewrite(ref, h.apiHost, h.pullHost)
+}
+
+// ToAPI rewrites a reference using the pull host to use the API host, other references
+// are returned unchanged.
+func (h *HostRewriter) ToAPI(ref string) string {
+	return h.rewrite(ref, h.pullHost, h.apiHost)
+}
+
+func (h *HostRewriter) rewrite(ref, from, to string) string {
+	if from == to || !strings.HasPrefix(ref, from+"/") {
+		return ref
+	}
+	return to + ref[len(from):]
+}
+
+// Reference returns the reference an artifact of Harbor is pulled with, reference being
+// a tag or a digest.
+func (h *HostRewriter) Reference(project, repository, reference string) string {
+	r := Reference{Host: h.pullHost, Project: project, Repository: repository}
+	if strings.Contains(reference, ":") {
+		r.Digest = reference
+	} else {
+		r.Tag = reference
+	}
+	return r.String()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package reference
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := map[string]Reference{
+		"nginx":                                  {Repository: "nginx", Tag: "latest"},
+		"library/nginx:1.19":                     {Project: "library", Repository: "nginx", Tag: "1.19"},
+		"harbor.example.com/app/api/web:v1":      {Host: "harbor.example.com", Project: "app", Repository: "api/web", Tag: "v1"},
+		"localhost:5000/app/api@sha256:abc":      {Host: "localhost:5000", Project: "app", Repository: "api", Digest: "sha256:abc"},
+		"harbor.example.com/app/api:v1@sha256:a": {Host: "harbor.example.com", Project: "app", Repository: "api", Tag: "v1", Digest: "sha256:a"},
+	}
+	for ref, expected := range testCases {
+		if parsed := Parse(ref); parsed != expected {
+			t.Errorf("%s: expected %#v, got %#v", ref, expected, parsed)
+		}
+	}
+	if s := Parse("harbor.example.com/app/api:v1").String(); s != "harbor.example.com/app/api:v1" {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
+
+func TestHostRewriter(t *testing.T) {
+	h := NewHostRewriter("https://harbor.internal:8443/", "harbor.example.com")
+	if ref := h.ToPull("harbor.internal:8443/app/api:v1"); ref != "harbor.example.com/app/api:v1" {
+		t.Errorf("unexpected pull reference: %s", ref)
+	}
+	if ref := h.ToAPI("harbor.example.com/app/api:v1"); ref != "harbor.internal:8443/app/api:v1" {
+		t.Errorf("unexpected API reference: %s", ref)
+	}
+	if ref := h.ToPull("docker.io/library/nginx"); ref != "docker.io/library/nginx" {
+		t.Errorf("expected other hosts to be unchanged, got %s", ref)
+	}
+	if ref := h.Reference("app", "api", "sha256:abc"); ref != "harbor.example.com/app/api@sha256:abc" {
+		t.Errorf("unexpected reference: %s", ref)
+	}
+	if !h.IsHarbor("harbor.internal:8443") || !h.IsHarbor("harbor.example.com") || h.IsHarbor("docker.io") {
+		t.Errorf("unexpected Harbor hosts")
+	}
+	if same := NewHostRewriter("harbor.example.com", ""); same.PullHost() != "harbor.example.com" {
+		t.Errorf("expected the API host to be pulled with, got %s", same.PullHost())
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/pager"
+	"sync"
+	"time"
+)
+
+const (
+	// listPageSize is the page size used to list all the registry endpoints.
+	listPageSize = 100
+	// healthConcurrency is the maximum number of registry endpoints pinged at once.
+	healthConcurrency = 8
+)
+
+// HealthSummary pings all the registry endpoints concurrently from Harbor, e.g. to check
+// the prerequisites of a replication before it runs, and returns the status and latency
+// of each of them, in the order they are listed.
+func (r *RegistriesClient) HealthSummary(ctx context.Context) (result *model.RegistryHealthSummary, err error) {
+	var registries []model.Registry
+	err = pager.ListAll(ctx, listPageSize, &registries, func(ctx context.Context, page, pageSize int64) (interface{}, error) {
+		return r.List(ctx, &model.Query{Page: page, PageSize: pageSize})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list registries error: %v", err)
+	}
+
+	result = &model.RegistryHealthSummary{Registries: make([]*model.RegistryHealth, len(registries))}
+	slots := make(chan struct{}, healthConcurrency)
+	var wg sync.WaitGroup
+	for i := range registries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			result.Registries[i] = r.health(ctx, &registries[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, health := range result.Registries {
+		if health.Healthy {
+			result.Healthy++
+		} else {
+			result.Unhealthy++
+		}
+	}
+	return result, nil
+}
+
+func (r *RegistriesClient) health(ctx context.Context, registry *model.Registry) *model.RegistryHealth {
+	health := &model.RegistryHealth{ID: registry.ID, Name: registry.Name, URL: registry.URL}
+	id := registry.ID
+	start := time.Now()
+	err := r.Ping(ctx, &model.RegistryPing{ID: &id})
+	health.Latency = time.Since(start)
+	if err != nil {
+		health.Error = err.Error()
+	} else {
+		health.Healthy = true
+	}
+	return health
+}
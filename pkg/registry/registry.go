@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strconv"
+)
+
+// RegistriesInterface holds the methods of the registry endpoint APIs.
+type RegistriesInterface interface {
+	Get(ctx context.Context, id int64) (result *model.Registry, err error)
+	GetByName(ctx context.Context, name string) (result *model.Registry, err error)
+	List(ctx context.Context, query *model.Query) (results *[]model.Registry, err error)
+	Create(ctx context.Context, registry *model.Registry) (err error)
+	Update(ctx context.Context, id int64, registry *model.Registry) (err error)
+	Delete(ctx context.Context, id int64) (err error)
+	Ping(ctx context.Context, ping *model.RegistryPing) (err error)
+	HealthSummary(ctx context.Context) (result *model.RegistryHealthSummary, err error)
+}
+
+// RegistriesClient is used to manage the registry endpoints used by replication.
+type RegistriesClient struct {
+	restClient rest2.Interface
+}
+
+func NewRegistriesClient(restClient *rest2.Config) (*RegistriesClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistriesClient{restClient: client}, nil
+}
+
+func (r *RegistriesClient) Get(ctx context.Context, id int64) (result *model.Registry, err error) {
+	result = &model.Registry{}
+	err = r.restClient.Get().
+		Resource("registries").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetByName gets the registry endpoint with the given name.
+func (r *RegistriesClient) GetByName(ctx context.Context, name string) (result *model.Registry, err error) {
+	q, err := model.NewQ().Exact("name", name).Build()
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.List(ctx, &model.Query{Q: q})
+	if err != nil {
+		return nil, err
+	}
+	for i := range *results {
+		if (*results)[i].Name == name {
+			return &(*results)[i], nil
+		}
+	}
+	return nil, fmt.Errorf("registry %s %w", name, rest2.ErrNotFound)
+}
+
+func (r *RegistriesClient) List(ctx context.Context, query *model.Query) (results *[]model.Registry, err error) {
+	results = &[]model.Registry{}
+	err = r.restClient.List().
+		Resource("registries").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (r *RegistriesClient) Create(ctx context.Context, registry *model.Registry) (err error) {
+	return r.restClient.Post().
+		Resource("registries").
+		Body(registry).
+		Do(ctx).
+		Error()
+}
+
+func (r *RegistriesClient) Update(ctx context.Context, id int64, registry *model.Registry) (err error) {
+	return r.restClient.Put().
+		Resource("registries").
+		Name(strconv.FormatInt(id, 10)).
+		Body(registry).
+		Do(ctx).
+		Error()
+}
+
+func (r *RegistriesClient) Delete(ctx context.Context, id int64) (err error) {
+	return r.restClient.Delete().
+		Resource("registries").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Error()
+}
+
+// Ping checks that Harbor can reach the registry endpoint with its credential.
+func (r *RegistriesClient) Ping(ctx context.Context, ping *model.RegistryPing) (err error) {
+	return r.restClient.Post().
+		Resource("registries").
+		Suffix("ping").
+		Body(ping).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/registry"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strings"
+	"time"
+)
+
+// MirrorOptions describes the upstream images to mirror into a Harbor project.
+type MirrorOptions struct {
+	// Registry is the upstream registry endpoint, it is created unless a registry
+	// endpoint with the same name already exists.
+	Registry *model.Registry
+	// PolicyName is the name of the pull based replication policy, it is created unless
+	// a policy with the same name already exists.
+	PolicyName string
+	// Repositories are the upstream repositories to pull, e.g. library/nginx. Patterns
+	// such as library/** are supported.
+	Repositories []string
+	// Tag optionally filters the tags to pull, e.g. 1.**.
+	Tag string
+	// Project is the Harbor project the images are pulled into, the upstream namespace
+	// is kept if empty.
+	Project string
+	// Override replaces the images which already exist in Harbor.
+	Override bool
+	// PollInterval is the interval the execution status is polled at.
+	PollInterval time.Duration
+}
+
+// Mirror mirrors upstream images into Harbor: it creates the registry endpoint and a
+// manual pull based replication policy for the repositories, triggers the first execution
+// and waits for it to complete. The completed execution is returned, an error is returned
+// if it did not succeed.
+func Mirror(ctx context.Context, registries registry.RegistriesInterface, replications ReplicationInterface, opts *MirrorOptions) (*model.ReplicationExecution, error) {
+	if opts.Registry == nil || opts.PolicyName == "" || len(opts.Repositories) == 0 {
+		return nil, fmt.Errorf("registry, policy name and repositories are required")
+	}
+
+	// only a missing resource is created, e.g. an authorization or a transient error
+	// must not create a duplicate
+	upstream, err := registries.GetByName(ctx, opts.Registry.Name)
+	if err != nil {
+		if !rest2.IsNotFound(err) {
+			return nil, fmt.Errorf("get registry %s error: %v", opts.Registry.Name, err)
+		}
+		if err := registries.Create(ctx, opts.Registry); err != nil {
+			return nil, fmt.Errorf("create registry %s error: %v", opts.Registry.Name, err)
+		}
+		if upstream, err = registries.GetByName(ctx, opts.Registry.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	policy, err := replications.GetPolicyByName(ctx, opts.PolicyName)
+	if err != nil {
+		if !rest2.IsNotFound(err) {
+			return nil, fmt.Errorf("get replication policy %s error: %v", opts.PolicyName, err)
+		}
+		filters := []*model.ReplicationFilter{{
+			Type:  model.FilterTypeName,
+			Value: repositoryPattern(opts.Repositories),
+		}}
+		if opts.Tag != "" {
+			filters = append(filters, &model.ReplicationFilter{Type: model.FilterTypeTag, Value: opts.Tag})
+		}
+		err := replications.CreatePolicy(ctx, &model.ReplicationPolicy{
+			Name:          opts.PolicyName,
+			Description:   fmt.Sprintf("mirror of %s from %s", strings.Join(opts.Repositories, ", "), upstream.Name),
+			SrcRegistry:   &model.Registry{ID: upstream.ID},
+			DestNamespace: opts.Project,
+			Filters:       filters,
+			Trigger:       &model.ReplicationTrigger{Type: model.TriggerTypeManual},
+			Override:      opts.Override,
+			Enabled:       true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create replication policy %s error: %v", opts.PolicyName, err)
+		}
+		if policy, err = replications.GetPolicyByName(ctx, opts.PolicyName); err != nil {
+			return nil, err
+		}
+	}
+
+	execution, err := replications.StartExecution(ctx, policy.ID)
+	if err != nil {
+		return nil, fmt.Errorf("start replication policy %s error: %v", opts.PolicyName, err)
+	}
+	execution, err = replications.WaitForExecution(ctx, execution.ID, opts.PollInterval)
+	if err != nil {
+		return execution, err
+	}
+	if execution.Status != model.ExecutionStatusSucceed {
+		return execution, fmt.Errorf("replication execution %d %s: %d of %d tasks failed %s",
+			execution.ID, execution.Status, execution.Failed, execution.Total, execution.StatusText)
+	}
+	return execution, nil
+}
+
+// repositoryPattern returns the name filter matching all the repositories.
+func repositoryPattern(repositories []string) string {
+	if len(repositories) == 1 {
+		return repositories[0]
+	}
+	return "{" + strings.Join(repositories, ",") + "}"
+}
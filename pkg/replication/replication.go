@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strconv"
+	"time"
+)
+
+// DefaultPollInterval is the interval WaitForExecution polls the execution status at.
+const DefaultPollInterval = 5 * time.Second
+
+// ReplicationInterface holds the methods of the replication APIs.
+type ReplicationInterface interface {
+	GetPolicy(ctx context.Context, id int64) (result *model.ReplicationPolicy, err error)
+	GetPolicyByName(ctx context.Context, name string) (result *model.ReplicationPolicy, err error)
+	ListPolicies(ctx context.Context, query *model.Query) (results *[]model.ReplicationPolicy, err error)
+	CreatePolicy(ctx context.Context, policy *model.ReplicationPolicy) (err error)
+	UpdatePolicy(ctx context.Context, id int64, policy *model.ReplicationPolicy) (err error)
+	DeletePolicy(ctx context.Context, id int64) (err error)
+	StartExecution(ctx context.Context, policyID int64) (result *model.ReplicationExecution, err error)
+	GetExecution(ctx context.Context, id int64) (result *model.ReplicationExecution, err error)
+	ListExecutions(ctx context.Context, query *model.ReplicationExecutionQuery) (results *[]model.ReplicationExecution, err error)
+	StopExecution(ctx context.Context, id int64) (err error)
+	WaitForExecution(ctx context.Context, id int64, interval time.Duration) (result *model.ReplicationExecution, err error)
+}
+
+// ReplicationClient is used to manage replication policies and executions.
+type ReplicationClient struct {
+	restClient rest2.Interface
+}
+
+func NewReplicationClient(restClient *rest2.Config) (*ReplicationClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplicationClient{restClient: client}, nil
+}
+
+func (r *ReplicationClient) GetPolicy(ctx context.Context, id int64) (result *model.ReplicationPolicy, err error) {
+	result = &model.ReplicationPolicy{}
+	err = r.restClient.Get().
+		Resource("replication").
+		Suffix("policies", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetPolicyByName gets the replication policy with the given name.
+func (r *ReplicationClient) GetPolicyByName(ctx context.Context, name string) (result *model.ReplicationPolicy, err error) {
+	q, err := model.NewQ().Exact("name", name).Build()
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.ListPolicies(ctx, &model.Query{Q: q})
+	if err != nil {
+		return nil, err
+	}
+	for i := range *results {
+		if (*results)[i].Name == name {
+			return &(*results)[i], nil
+		}
+	}
+	return nil, fmt.Errorf("replication policy %s %w", name, rest2.ErrNotFound)
+}
+
+func (r *ReplicationClient) ListPolicies(ctx context.Context, query *model.Query) (results *[]model.ReplicationPolicy, err error) {
+	results = &[]model.ReplicationPolicy{}
+	err = r.restClient.List().
+		Resource("replication").
+		Suffix("policies").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (r *ReplicationClient) CreatePolicy(ctx context.Context, policy *model.ReplicationPolicy) (err error) {
+	return r.restClient.Post().
+		Resource("replication").
+		Suffix("policies").
+		Body(policy).
+		Do(ctx).
+		Error()
+}
+
+func (r *ReplicationClient) UpdatePolicy(ctx context.Context, id int64, policy *model.ReplicationPolicy) (err error) {
+	return r.restClient.Put().
+		Resource("replication").
+		Suffix("policies", strconv.FormatInt(id, 10)).
+		Body(policy).
+		Do(ctx).
+		Error()
+}
+
+func (r *ReplicationClient) DeletePolicy(ctx context.Context, id int64) (err error) {
+	return r.restClient.Delete().
+		Resource("replication").
+		Suffix("policies", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Error()
+}
+
+// StartExecution manually triggers a replication policy and returns the started execution.
+// Harbor returns the ID of the execution in the Location header. Older versions don't, the
+// latest execution of the policy is returned then, which is racy: if the policy is
+// triggered concurrently, e.g. by another client or its schedule, it may be the other
+// execution.
+func (r *ReplicationClient) StartExecution(ctx context.Context, policyID int64) (result *model.ReplicationExecution, err error) {
+	response := r.restClient.Post().
+		Resource("replication").
+		Suffix("executions").
+		Body(map[string]int64{"policy_id": policyID}).
+		Do(ctx)
+	if err = response.Error(); err != nil {
+		return nil, err
+	}
+	// the ID of the execution is only returned in the Location header, look up the
+	// latest execution of the policy without it
+	if id, ok := response.LocationID(); ok {
+		return r.GetExecution(ctx, id)
+	}
+	executions, err := r.ListExecutions(ctx, &model.ReplicationExecutionQuery{
+		Query:    model.Query{Page: 1, PageSize: 10},
+		PolicyID: policyID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range *executions {
+		if result == nil || (*executions)[i].ID > result.ID {
+			result = &(*executions)[i]
+		}
+	}
+	if result == nil {
+		return nil, fmt.Errorf("execution of replication policy %d not found", policyID)
+	}
+	return result, nil
+}
+
+func (r *ReplicationClient) GetExecution(ctx context.Context, id int64) (result *model.ReplicationExecution, err error) {
+	result = &model.ReplicationExecution{}
+	err = r.restClient.Get().
+		Resource("replication").
+		Suffix("executions", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (r *ReplicationClient) ListExecutions(ctx context.Context, query *model.ReplicationExecutionQuery) (results *[]model.ReplicationExecution, err error) {
+	results = &[]model.ReplicationExecution{}
+	err = r.restClient.List().
+		Resource("replication").
+		Suffix("executions").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (r *ReplicationClient) StopExecution(ctx context.Context, id int64) (err error) {
+	return r.restClient.Put().
+		Resource("replication").
+		Suffix("executions", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Error()
+}
+
+// WaitForExecution polls the execution until it is done or ctx is done, interval
+// defaults to DefaultPollInterval.
+func (r *ReplicationClient) WaitForExecution(ctx context.Context, id int64, interval time.Duration) (result *model.ReplicationExecution, err error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result, err = r.GetExecution(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if result.Done() {
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
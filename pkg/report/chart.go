@@ -0,0 +1,286 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+	"github.com/hujianxiong/go-harbor/pkg/reference"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// imagesAnnotation is the chart annotation listing the images used by a chart.
+const imagesAnnotation = "artifacthub.io/images"
+
+// ChartSource lists the charts of a project and fetches the details of a chart version, it
+// is implemented by chart.ChartsClient.
+type ChartSource interface {
+	List(ctx context.Context, project string) (result *[]model.ChartInfo, err error)
+	Versions(ctx context.Context, project, name string) (result *[]model.ChartVersion, err error)
+	GetVersion(ctx context.Context, project, name, version string) (result *model.ChartVersionDetails, err error)
+}
+
+// RepositoriesGetter returns the repositories of a project, it is implemented by
+// project.ProjectsV2Client.
+type RepositoriesGetter interface {
+	Repositories(project string) project2.RepositoryInterface
+}
+
+// ChartImageOptions holds the optional settings of ChartImages.
+type ChartImageOptions struct {
+	// RegistryHost is the host images are pulled from Harbor with, e.g. harbor.example.com.
+	// Images hosted elsewhere are reported as external and not checked. If empty, the
+	// host part of every reference is ignored and all images are looked up in Harbor.
+	RegistryHost string
+	// Hosts, if set, maps the API host of Harbor to its pull host, images pulled with
+	// either of them are looked up in Harbor and RegistryHost is ignored.
+	// Clientset.ChartImages defaults it to the References of the client set when neither
+	// Hosts nor RegistryHost is set.
+	Hosts *reference.HostRewriter
+	// AllVersions checks every version of each chart instead of the latest one only.
+	AllVersions bool
+}
+
+// ChartImage is an image referenced by a chart version.
+type ChartImage struct {
+	// Reference is the image reference as found in the chart.
+	Reference  string `json:"reference"`
+	Project    string `json:"project,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	// Tag is the tag or the digest of the image.
+	Tag string `json:"tag,omitempty"`
+	// External is true if the image is not hosted by the Harbor instance.
+	External bool `json:"external"`
+	// Exists is false if the artifact is not found, or if looking it up failed, in which
+	// case Error is set.
+	Exists bool `json:"exists"`
+	// ScanStatus and Severity are taken from the scan overview of the artifact, if any.
+	ScanStatus string `json:"scan_status,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ChartImageReport pairs a chart version with the images it references.
+type ChartImageReport struct {
+	Project    string       `json:"project"`
+	Chart      string       `json:"chart"`
+	Version    string       `json:"version"`
+	AppVersion string       `json:"app_version,omitempty"`
+	Images     []ChartImage `json:"images"`
+}
+
+// Healthy returns true if every Harbor hosted image of the chart version exists
+// and has been scanned successfully.
+func (r *ChartImageReport) Healthy() bool {
+	for _, image := range r.Images {
+		if image.External {
+			continue
+		}
+		if !image.Exists || image.ScanStatus != model.ScanStatusSuccess {
+			return false
+		}
+	}
+	return true
+}
+
+// ChartImages builds a co-versioning report for the charts of a project: the images each
+// chart version references in its values and annotations are resolved against Harbor to
+// check that they exist and have been scanned.
+func ChartImages(ctx context.Context, charts ChartSource, repositories RepositoriesGetter, project string, opts *ChartImageOptions) ([]ChartImageReport, error) {
+	if opts == nil {
+		opts = &ChartImageOptions{}
+	}
+	infos, err := charts.List(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("list charts of project %s error: %v", project, err)
+	}
+	var reports []ChartImageReport
+	for _, info := range *infos {
+		versions := []string{info.LatestVersion}
+		if opts.AllVersions {
+			list, err := charts.Versions(ctx, project, info.Name)
+			if err != nil {
+				return nil, fmt.Errorf("list versions of chart %s error: %v", info.Name, err)
+			}
+			versions = versions[:0]
+			for _, v := range *list {
+				versions = append(versions, v.Version)
+			}
+		}
+		for _, version := range versions {
+			details, err := charts.GetVersion(ctx, project, info.Name, version)
+			if err != nil {
+				return nil, fmt.Errorf("get chart %s:%s error: %v", info.Name, version, err)
+			}
+			report := ChartImageReport{
+				Project: project,
+				Chart:   info.Name,
+				Version: version,
+			}
+			if details.Metadata != nil {
+				report.AppVersion = details.Metadata.AppVersion
+			}
+			for _, ref := range chartImageReferences(details) {
+				report.Images = append(report.Images, checkImage(ctx, repositories, ref, opts))
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// chartImageReferences collects the distinct image references of a chart version.
+func chartImageReferences(details *model.ChartVersionDetails) []string {
+	refs := map[string]struct{}{}
+	appVersion := ""
+	if details.Metadata != nil {
+		appVersion = details.Metadata.AppVersion
+		scanner := bufio.NewScanner(strings.NewReader(details.Metadata.Annotations[imagesAnnotation]))
+		for scanner.Scan() {
+			line := strings.TrimLeft(strings.TrimSpace(scanner.Text()), "- ")
+			if strings.HasPrefix(line, "image:") {
+				ref := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "image:")), `"'`)
+				if ref != "" {
+					refs[ref] = struct{}{}
+				}
+			}
+		}
+	}
+	collectValueImages(details.Values, appVersion, refs)
+
+	result := make([]string, 0, len(refs))
+	for ref := range refs {
+		result = append(result, ref)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// collectValueImages walks the chart values looking for the common "image" conventions:
+// either a plain reference string or a map with registry, repository, tag and digest keys.
+func collectValueImages(values map[string]interface{}, appVersion string, refs map[string]struct{}) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case string:
+			if key == "image" && v != "" {
+				refs[v] = struct{}{}
+			}
+		case map[string]interface{}:
+			if key == "image" {
+				if ref := imageFromMap(v, appVersion); ref != "" {
+					refs[ref] = struct{}{}
+					continue
+				}
+			}
+			collectValueImages(v, appVersion, refs)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					collectValueImages(m, appVersion, refs)
+				}
+			}
+		}
+	}
+}
+
+func imageFromMap(image map[string]interface{}, appVersion string) string {
+	repository, _ := image["repository"].(string)
+	if repository == "" {
+		return ""
+	}
+	if registry, _ := image["registry"].(string); registry != "" {
+		repository = strings.TrimSuffix(registry, "/") + "/" + repository
+	}
+	if digest, _ := image["digest"].(string); digest != "" {
+		return repository + "@" + digest
+	}
+	tag := imageTag(image["tag"])
+	if tag == "" {
+		tag = appVersion
+	}
+	if tag == "" {
+		return repository
+	}
+	return repository + ":" + tag
+}
+
+// imageTag formats the tag of an image map. A tag left unquoted in the values is a number
+// once decoded: integers are formatted as such, e.g. 1000000 rather than 1e+06, and the
+// other numbers with their shortest representation, 1.10 being read as 1.1 since the
+// original text is lost.
+func imageTag(tag interface{}) string {
+	switch v := tag.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return ""
+}
+
+// checkImage resolves an image reference against Harbor.
+func checkImage(ctx context.Context, repositories RepositoriesGetter, ref string, opts *ChartImageOptions) ChartImage {
+	image := ChartImage{Reference: ref}
+	parsed := reference.Parse(ref)
+	external := opts.RegistryHost != "" && parsed.Host != opts.RegistryHost
+	if opts.Hosts != nil {
+		external = !opts.Hosts.IsHarbor(parsed.Host)
+	}
+	if parsed.Project == "" || external {
+		image.External = true
+		return image
+	}
+	image.Project, image.Repository, image.Tag = parsed.Project, parsed.Repository, parsed.Reference()
+
+	artifact, err := repositories.Repositories(image.Project).
+		Artifacts(url.PathEscape(image.Repository)).
+		GetWithQuery(ctx, image.Tag, &model.ArtifactQuery{WithScanOverview: true})
+	if rest2.IsNotFound(err) {
+		return image
+	}
+	if err != nil {
+		image.Error = fmt.Sprintf("get artifact error: %v", err)
+		return image
+	}
+	image.Exists = true
+	for _, overview := range artifact.ScanOverview {
+		if overview == nil {
+			continue
+		}
+		image.ScanStatus = overview.ScanStatus
+		image.Severity = overview.Severity
+	}
+	return image
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+func TestImageFromMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      map[string]interface{}
+		appVersion string
+		want       string
+	}{
+		{"tag", map[string]interface{}{"repository": "library/app", "tag": "1.10"}, "", "library/app:1.10"},
+		{"registry", map[string]interface{}{"registry": "harbor.example.com/", "repository": "library/app", "tag": "v1"}, "", "harbor.example.com/library/app:v1"},
+		{"digest", map[string]interface{}{"repository": "library/app", "tag": "v1", "digest": "sha256:abc"}, "", "library/app@sha256:abc"},
+		{"app version", map[string]interface{}{"repository": "library/app"}, "2.0", "library/app:2.0"},
+		{"empty tag", map[string]interface{}{"repository": "library/app", "tag": ""}, "2.0", "library/app:2.0"},
+		{"no tag", map[string]interface{}{"repository": "library/app"}, "", "library/app"},
+		{"integer tag", map[string]interface{}{"repository": "library/app", "tag": float64(1000000)}, "", "library/app:1000000"},
+		{"number tag", map[string]interface{}{"repository": "library/app", "tag": json.Number("1.10")}, "", "library/app:1.10"},
+		{"decimal tag", map[string]interface{}{"repository": "library/app", "tag": 1.5}, "", "library/app:1.5"},
+		{"no repository", map[string]interface{}{"tag": "v1"}, "", ""},
+	}
+	for _, test := range tests {
+		if got := imageFromMap(test.image, test.appVersion); got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
+
+func TestChartImageReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		details *model.ChartVersionDetails
+		want    []string
+	}{
+		{
+			name: "values",
+			details: &model.ChartVersionDetails{Values: map[string]interface{}{
+				"image": "library/app:v1",
+				"sidecar": map[string]interface{}{
+					"image": map[string]interface{}{"repository": "library/sidecar", "tag": "v2"},
+				},
+				"workers": []interface{}{
+					map[string]interface{}{"image": "library/worker:v3"},
+					map[string]interface{}{"image": "library/app:v1"},
+				},
+			}},
+			want: []string{"library/app:v1", "library/sidecar:v2", "library/worker:v3"},
+		},
+		{
+			name: "annotations and app version",
+			details: &model.ChartVersionDetails{
+				Metadata: &model.ChartVersion{ChartMetadata: model.ChartMetadata{
+					AppVersion:  "4.0",
+					Annotations: map[string]string{imagesAnnotation: "- name: app\n  image: \"library/app:v1\"\n- name: db\n  image: library/db:13\n"},
+				}},
+				Values: map[string]interface{}{"image": map[string]interface{}{"repository": "library/app"}},
+			},
+			want: []string{"library/app:4.0", "library/app:v1", "library/db:13"},
+		},
+		{
+			name:    "no images",
+			details: &model.ChartVersionDetails{Values: map[string]interface{}{"replicas": float64(2)}},
+			want:    []string{},
+		},
+	}
+	for _, test := range tests {
+		if got := chartImageReferences(test.details); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestCheckImageErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case strings.Contains(req.URL.Path, "/repositories/missing/"):
+			http.NotFound(w, req)
+		case strings.Contains(req.URL.Path, "/repositories/broken/"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Write([]byte(`{"digest":"sha256:abc"}`))
+		}
+	}))
+	defer server.Close()
+	repositories, err := project2.NewProjectsV1Client(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref    string
+		exists bool
+		failed bool
+	}{
+		{"library/app:v1", true, false},
+		{"library/missing:v1", false, false},
+		{"library/broken:v1", false, true},
+	}
+	for _, test := range tests {
+		image := checkImage(context.Background(), repositories, test.ref, &ChartImageOptions{})
+		if image.Exists != test.exists || (image.Error != "") != test.failed {
+			t.Errorf("%s: expected exists %v and failed %v, got %+v", test.ref, test.exists, test.failed, image)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hujianxiong/go-harbor/pkg/store"
+)
+
+// defaultFlushEvery is the default number of rows written between two checkpoints.
+const defaultFlushEvery = 1000
+
+// ExportOptions holds the optional settings of the NDJSON exports: ExportAuditLogs,
+// ExportManifests, ExportVulnerabilities and ExportTagSigning. ChartImages and
+// StorageBreakdown aggregate their results in memory, per chart and per project, and are
+// not exported.
+type ExportOptions struct {
+	// Gzip compresses the output.
+	Gzip bool
+	// FlushEvery is the number of rows after which the output is flushed and a checkpoint
+	// saved, at the end of the page being exported. Defaults to 1000.
+	FlushEvery int
+	// Checkpoints, if set, saves the progress of the export under CheckpointName so that
+	// it resumes where it stopped when run again, see OpenExportFile.
+	Checkpoints store.Store
+	// CheckpointName identifies the export in Checkpoints, it is required with Checkpoints,
+	// the checkpoint being stored under export-checkpoints/<name>.
+	CheckpointName string
+}
+
+// Checkpoint is the progress of an export. It is saved once the rows before it are
+// flushed, so the output truncated to Offset bytes holds exactly Rows complete rows, and
+// the export resumes from the position after them. Rows exported after the last
+// checkpoint are exported again on resume.
+type Checkpoint struct {
+	// Rows is the number of rows written.
+	Rows int64 `json:"rows"`
+	// Offset is the number of bytes written to the output.
+	Offset int64 `json:"offset"`
+	// Page is the next page of the top level listing to export, 0 before the first one.
+	Page int64 `json:"page"`
+	// Repository is the repository of the page to resume at, for exports walking
+	// repositories, and RepositoryPage the next page of its artifacts to export.
+	Repository     string `json:"repository,omitempty"`
+	RepositoryPage int64  `json:"repository_page,omitempty"`
+	// Done is set once the export completed.
+	Done bool `json:"done"`
+}
+
+// LoadCheckpoint returns the last checkpoint saved by the export of opts, nil if there is
+// none or opts has no Checkpoints.
+func LoadCheckpoint(opts *ExportOptions) (*Checkpoint, error) {
+	if opts == nil || opts.Checkpoints == nil {
+		return nil, nil
+	}
+	if opts.CheckpointName == "" {
+		return nil, fmt.Errorf("a checkpoint name is required to checkpoint an export")
+	}
+	data, ok, err := opts.Checkpoints.Get(checkpointKey(opts.CheckpointName))
+	if err != nil || !ok {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("read checkpoint %s error: %v", opts.CheckpointName, err)
+	}
+	return checkpoint, nil
+}
+
+func saveCheckpoint(opts *ExportOptions, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return opts.Checkpoints.Put(checkpointKey(opts.CheckpointName), data)
+}
+
+func checkpointKey(name string) string {
+	return "export-checkpoints/" + name
+}
+
+// OpenExportFile opens the output file of the export of opts, dropping what was written
+// after its last checkpoint so that the export can resume by appending to it. The file is
+// created, or truncated, if there is no checkpoint.
+func OpenExportFile(path string, opts *ExportOptions) (*os.File, error) {
+	var offset int64
+	checkpoint, err := LoadCheckpoint(opts)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil {
+		offset = checkpoint.Offset
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NDJSONExporter writes rows as newline delimited JSON, optionally gzip compressed, and
+// checkpoints its progress. With gzip, every checkpoint ends a gzip member, so the output
+// truncated at any checkpoint is a valid gzip stream, and so is the output completed on
+// resume since readers decompress concatenated members as one stream.
+type NDJSONExporter struct {
+	opts       ExportOptions
+	out        *countingWriter
+	buffer     *bufio.Writer
+	gzip       *gzip.Writer
+	encoder    *json.Encoder
+	checkpoint Checkpoint
+	pending    int
+}
+
+// NewNDJSONExporter returns an exporter writing to w, resuming from the last checkpoint
+// of opts.Checkpoints if any, w being positioned at its offset, e.g. by OpenExportFile.
+func NewNDJSONExporter(w io.Writer, opts *ExportOptions) (*NDJSONExporter, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	e := &NDJSONExporter{opts: *opts}
+	if e.opts.FlushEvery <= 0 {
+		e.opts.FlushEvery = defaultFlushEvery
+	}
+	checkpoint, err := LoadCheckpoint(&e.opts)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint error: %v", err)
+	}
+	if checkpoint != nil {
+		e.checkpoint = *checkpoint
+	}
+	e.out = &countingWriter{w: w, n: e.checkpoint.Offset}
+	e.buffer = bufio.NewWriter(e.out)
+	if e.opts.Gzip {
+		e.gzip = gzip.NewWriter(e.buffer)
+		e.encoder = json.NewEncoder(e.gzip)
+	} else {
+		e.encoder = json.NewEncoder(e.buffer)
+	}
+	return e, nil
+}
+
+// Checkpoint returns the progress of the export, the position to resume from being the one
+// of the last checkpoint.
+func (e *NDJSONExporter) Checkpoint() Checkpoint {
+	return e.checkpoint
+}
+
+// Write writes a row.
+func (e *NDJSONExporter) Write(row interface{}) error {
+	if err := e.encoder.Encode(row); err != nil {
+		return err
+	}
+	e.pending++
+	return nil
+}
+
+// EndPage is called once the rows of a page are written with the position to resume
+// from, it flushes the output and saves a checkpoint every opts.FlushEvery rows.
+func (e *NDJSONExporter) EndPage(next Checkpoint) error {
+	if e.pending < e.opts.FlushEvery {
+		e.setPosition(next)
+		return nil
+	}
+	return e.save(next)
+}
+
+// Close flushes the output and marks the export as done, it doesn't close the underlying
+// writer.
+func (e *NDJSONExporter) Close() error {
+	e.checkpoint.Done = true
+	return e.save(e.checkpoint)
+}
+
+func (e *NDJSONExporter) setPosition(next Checkpoint) {
+	e.checkpoint.Page = next.Page
+	e.checkpoint.Repository = next.Repository
+	e.checkpoint.RepositoryPage = next.RepositoryPage
+}
+
+// save flushes the output, ending the current gzip member, and saves a checkpoint at next.
+func (e *NDJSONExporter) save(next Checkpoint) error {
+	if e.gzip != nil {
+		if err := e.gzip.Close(); err != nil {
+			return err
+		}
+	}
+	if err := e.buffer.Flush(); err != nil {
+		return err
+	}
+	if e.gzip != nil {
+		e.gzip.Reset(e.buffer)
+	}
+	e.setPosition(next)
+	e.checkpoint.Rows += int64(e.pending)
+	e.checkpoint.Offset = e.out.n
+	e.checkpoint.Done = next.Done
+	e.pending = 0
+	if e.opts.Checkpoints == nil {
+		return nil
+	}
+	checkpoint := e.checkpoint
+	if err := saveCheckpoint(&e.opts, &checkpoint); err != nil {
+		return fmt.Errorf("save checkpoint error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+)
+
+// pagedLogs serves total audit logs, failing the pages listed in fail once.
+type pagedLogs struct {
+	total int64
+	fail  map[int64]bool
+}
+
+func (p *pagedLogs) List(ctx context.Context, query *model.AuditLogQuery) (*model.AuditLogList, error) {
+	if p.fail[query.Page] {
+		delete(p.fail, query.Page)
+		return nil, fmt.Errorf("page %d unavailable", query.Page)
+	}
+	list := &model.AuditLogList{Total: p.total}
+	for id := (query.Page-1)*query.PageSize + 1; id <= query.Page*query.PageSize && id <= p.total; id++ {
+		list.Items = append(list.Items, &model.AuditLog{ID: id})
+	}
+	return list, nil
+}
+
+func TestExportAuditLogsResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs.ndjson.gz")
+	checkpoints, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &ExportOptions{
+		Gzip:           true,
+		FlushEvery:     20,
+		Checkpoints:    checkpoints,
+		CheckpointName: "logs",
+	}
+	logs := &pagedLogs{total: 95, fail: map[int64]bool{5: true}}
+
+	export := func() (*Checkpoint, error) {
+		file, err := OpenExportFile(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		return ExportAuditLogs(context.Background(), logs, &model.AuditLogQuery{PageSize: 10}, file, opts)
+	}
+	if _, err := export(); err == nil {
+		t.Fatalf("expected the first export to fail")
+	}
+	checkpoint, err := LoadCheckpoint(opts)
+	if err != nil || checkpoint == nil || checkpoint.Rows != 40 || checkpoint.Page != 5 || checkpoint.Done {
+		t.Fatalf("unexpected checkpoint after the failure: %+v %v", checkpoint, err)
+	}
+	checkpoint, err = export()
+	if err != nil {
+		t.Fatalf("unexpected error on resume: %v", err)
+	}
+	if checkpoint.Rows != 95 || !checkpoint.Done {
+		t.Errorf("unexpected final checkpoint: %+v", checkpoint)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(reader)
+	var id int64
+	for scanner.Scan() {
+		log := &model.AuditLog{}
+		if err := json.Unmarshal(scanner.Bytes(), log); err != nil {
+			t.Fatalf("invalid row %q: %v", scanner.Text(), err)
+		}
+		if id++; log.ID != id {
+			t.Fatalf("expected row %d, got %d", id, log.ID)
+		}
+	}
+	if err := scanner.Err(); err != nil || id != 95 {
+		t.Errorf("expected 95 rows, got %d %v", id, err)
+	}
+
+	if checkpoint, err := export(); err != nil || checkpoint.Rows != 95 {
+		t.Errorf("expected a completed export to be left as is, got %+v %v", checkpoint, err)
+	}
+}
+
+func TestExportTagSigningResume(t *testing.T) {
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v2.0/projects/library/repositories":
+			w.Write([]byte(`[{"name":"library/a"},{"name":"library/b"}]`))
+		case "/api/v2.0/projects/library/repositories/a/artifacts":
+			w.Write([]byte(`[{"digest":"sha256:1","tags":[{"name":"v1","signed":true},{"name":"v2"}]}]`))
+		case "/api/v2.0/projects/library/repositories/b/artifacts":
+			if !failed {
+				failed = true
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`[{"digest":"sha256:2","tags":[{"name":"latest"}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	projects, err := project2.NewProjectsV1Client(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signing.ndjson")
+	opts := &ExportOptions{FlushEvery: 1, Checkpoints: store.NewMemoryStore(), CheckpointName: "signing"}
+
+	export := func() (*Checkpoint, error) {
+		file, err := OpenExportFile(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		return ExportTagSigning(context.Background(), projects, "library", file, opts)
+	}
+	if _, err := export(); err == nil {
+		t.Fatalf("expected the first export to fail")
+	}
+	checkpoint, err := LoadCheckpoint(opts)
+	if err != nil || checkpoint == nil || checkpoint.Rows != 2 || checkpoint.Repository != "b" {
+		t.Fatalf("unexpected checkpoint after the failure: %+v %v", checkpoint, err)
+	}
+	if checkpoint, err = export(); err != nil || checkpoint.Rows != 3 || !checkpoint.Done {
+		t.Fatalf("unexpected final checkpoint: %+v %v", checkpoint, err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tags []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		row := &TagSignature{}
+		if err := json.Unmarshal([]byte(line), row); err != nil {
+			t.Fatalf("invalid row %q: %v", line, err)
+		}
+		tags = append(tags, fmt.Sprintf("%s:%s:%v", row.Repository, row.Tag, row.Signed()))
+	}
+	if strings.Join(tags, " ") != "a:v1:true a:v2:false b:latest:false" {
+		t.Errorf("unexpected rows %v", tags)
+	}
+}
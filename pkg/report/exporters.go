@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	project2 "github.com/hujianxiong/go-harbor/pkg/project"
+)
+
+// AuditLogLister lists audit logs, it is implemented by auditlog.AuditLogsClient.
+type AuditLogLister interface {
+	List(ctx context.Context, query *model.AuditLogQuery) (result *model.AuditLogList, err error)
+}
+
+// ManifestRow is a row of ExportManifests.
+type ManifestRow struct {
+	Project    string          `json:"project"`
+	Repository string          `json:"repository"`
+	Digest     string          `json:"digest"`
+	Tags       []string        `json:"tags,omitempty"`
+	Manifest   *model.Manifest `json:"manifest"`
+}
+
+// VulnerabilityRow is a row of ExportVulnerabilities, a vulnerability of an artifact.
+type VulnerabilityRow struct {
+	Project    string   `json:"project"`
+	Repository string   `json:"repository"`
+	Digest     string   `json:"digest"`
+	Tags       []string `json:"tags,omitempty"`
+	Scanner    string   `json:"scanner,omitempty"`
+	*model.VulnerabilityItem
+}
+
+// ExportAuditLogs writes the audit logs matching query as NDJSON to w and returns the final
+// checkpoint. The logs are listed newest first, so query should set To when resuming, the
+// logs added meanwhile shifting the pages otherwise.
+func ExportAuditLogs(ctx context.Context, logs AuditLogLister, query *model.AuditLogQuery, w io.Writer, opts *ExportOptions) (*Checkpoint, error) {
+	e, err := NewNDJSONExporter(w, opts)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint := e.Checkpoint(); checkpoint.Done {
+		return &checkpoint, nil
+	}
+	q := model.AuditLogQuery{}
+	if query != nil {
+		q = *query
+	}
+	if q.PageSize <= 0 {
+		q.PageSize = listPageSize
+	}
+	for q.Page = max64(e.Checkpoint().Page, 1); ; q.Page++ {
+		list, err := logs.List(ctx, &q)
+		if err != nil {
+			return nil, fmt.Errorf("list audit logs error: %v", err)
+		}
+		for _, log := range list.Items {
+			if err := e.Write(log); err != nil {
+				return nil, err
+			}
+		}
+		if int64(len(list.Items)) < q.PageSize {
+			break
+		}
+		if err := e.EndPage(Checkpoint{Page: q.Page + 1}); err != nil {
+			return nil, err
+		}
+	}
+	return closeExport(e)
+}
+
+// ExportManifests writes the manifest of every artifact of a project as NDJSON to w and
+// returns the final checkpoint.
+func ExportManifests(ctx context.Context, repositories RepositoriesGetter, project string, w io.Writer, opts *ExportOptions) (*Checkpoint, error) {
+	return exportArtifacts(ctx, repositories, project, w, opts, func(e *NDJSONExporter, artifacts project2.ArtifactInterface, row *ManifestRow) error {
+		manifest, err := artifacts.Manifest(ctx, row.Digest)
+		if err != nil {
+			return fmt.Errorf("get manifest of %s/%s@%s error: %v", row.Project, row.Repository, row.Digest, err)
+		}
+		row.Manifest = manifest
+		return e.Write(row)
+	})
+}
+
+// ExportVulnerabilities writes the vulnerabilities of every artifact of a project as
+// NDJSON to w, one row per vulnerability and report, and returns the final checkpoint.
+// Artifacts that haven't been scanned have no row.
+func ExportVulnerabilities(ctx context.Context, repositories RepositoriesGetter, project string, w io.Writer, opts *ExportOptions) (*Checkpoint, error) {
+	return exportArtifacts(ctx, repositories, project, w, opts, func(e *NDJSONExporter, artifacts project2.ArtifactInterface, artifact *ManifestRow) error {
+		reports, err := artifacts.Vulnerabilities(ctx, artifact.Digest)
+		if err != nil {
+			return fmt.Errorf("get vulnerabilities of %s/%s@%s error: %v", artifact.Project, artifact.Repository, artifact.Digest, err)
+		}
+		for _, report := range reports {
+			var scanner string
+			if report.Scanner != nil {
+				scanner = report.Scanner.Name
+			}
+			for _, item := range report.Vulnerabilities {
+				row := &VulnerabilityRow{
+					Project:           artifact.Project,
+					Repository:        artifact.Repository,
+					Digest:            artifact.Digest,
+					Tags:              artifact.Tags,
+					Scanner:           scanner,
+					VulnerabilityItem: item,
+				}
+				if err := e.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// exportArtifacts calls export with every artifact of a project, the row holding its
+// identity, and checkpoints after every page of artifacts.
+func exportArtifacts(ctx context.Context, repositories RepositoriesGetter, project string, w io.Writer, opts *ExportOptions,
+	export func(e *NDJSONExporter, artifacts project2.ArtifactInterface, row *ManifestRow) error) (*Checkpoint, error) {
+	e, err := NewNDJSONExporter(w, opts)
+	if err != nil {
+		return nil, err
+	}
+	resume := e.Checkpoint()
+	if resume.Done {
+		return &resume, nil
+	}
+	for page := max64(resume.Page, 1); ; page++ {
+		names, err := repositoryNames(ctx, repositories, project, page)
+		if err != nil {
+			return nil, err
+		}
+		// resume at the checkpointed repository, or at the start of the page if it is
+		// gone, its artifacts then being exported from the checkpointed page
+		start, artifactPage := 0, int64(1)
+		if resume.Repository != "" {
+			for i, name := range names {
+				if name == resume.Repository {
+					start, artifactPage = i, max64(resume.RepositoryPage, 1)
+				}
+			}
+			resume.Repository = ""
+		}
+		for i := start; i < len(names); i++ {
+			next := Checkpoint{Page: page + 1}
+			if i+1 < len(names) {
+				next = Checkpoint{Page: page, Repository: names[i+1], RepositoryPage: 1}
+			}
+			if err := exportRepositoryArtifacts(ctx, e, repositories, project, page, names[i], artifactPage, next, export); err != nil {
+				return nil, err
+			}
+			artifactPage = 1
+		}
+		if len(names) < listPageSize {
+			break
+		}
+	}
+	return closeExport(e)
+}
+
+// ExportTagSigning writes the signing status of every tag of a project, see TagSigning, as
+// NDJSON to w, one TagSignature row per tag, and returns the final checkpoint. The progress
+// is checkpointed at the end of the repositories, whose tags are all listed to detect the
+// cosign signatures.
+func ExportTagSigning(ctx context.Context, repositories RepositoriesGetter, project string, w io.Writer, opts *ExportOptions) (*Checkpoint, error) {
+	e, err := NewNDJSONExporter(w, opts)
+	if err != nil {
+		return nil, err
+	}
+	resume := e.Checkpoint()
+	if resume.Done {
+		return &resume, nil
+	}
+	for page := max64(resume.Page, 1); ; page++ {
+		names, err := repositoryNames(ctx, repositories, project, page)
+		if err != nil {
+			return nil, err
+		}
+		// resume at the checkpointed repository, or at the start of the page if it is gone
+		start := 0
+		if resume.Repository != "" {
+			for i, name := range names {
+				if name == resume.Repository {
+					start = i
+				}
+			}
+			resume.Repository = ""
+		}
+		for i := start; i < len(names); i++ {
+			report, err := repositorySigning(ctx, repositories, project, names[i])
+			if err != nil {
+				return nil, err
+			}
+			for j := range report.Tags {
+				if err := e.Write(&report.Tags[j]); err != nil {
+					return nil, err
+				}
+			}
+			next := Checkpoint{Page: page + 1}
+			if i+1 < len(names) {
+				next = Checkpoint{Page: page, Repository: names[i+1]}
+			}
+			if err := e.EndPage(next); err != nil {
+				return nil, err
+			}
+		}
+		if len(names) < listPageSize {
+			break
+		}
+	}
+	return closeExport(e)
+}
+
+// repositoryNames returns the names of the repositories of a page of a project, without
+// the project.
+func repositoryNames(ctx context.Context, repositories RepositoriesGetter, project string, page int64) ([]string, error) {
+	repos, err := repositories.Repositories(project).List(ctx, &model.Query{Page: page, PageSize: listPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("list repositories of project %s error: %v", project, err)
+	}
+	names := make([]string, len(*repos))
+	for i, repo := range *repos {
+		names[i] = strings.TrimPrefix(repo.Name, project+"/")
+	}
+	return names, nil
+}
+
+// exportRepositoryArtifacts exports the artifacts of a repository, listed in the page
+// repositoryPage of the repositories, from page on, next being the position after the
+// repository.
+func exportRepositoryArtifacts(ctx context.Context, e *NDJSONExporter, repositories RepositoriesGetter, project string, repositoryPage int64, repository string, page int64, next Checkpoint,
+	export func(e *NDJSONExporter, artifacts project2.ArtifactInterface, row *ManifestRow) error) error {
+	artifacts := repositories.Repositories(project).Artifacts(url.PathEscape(repository))
+	for ; ; page++ {
+		list, err := artifacts.ListWithQuery(ctx, &model.ArtifactQuery{
+			Query:   model.Query{Page: page, PageSize: listPageSize},
+			WithTag: true,
+		})
+		if err != nil {
+			return fmt.Errorf("list artifacts of %s/%s error: %v", project, repository, err)
+		}
+		for _, artifact := range *list {
+			row := &ManifestRow{Project: project, Repository: repository, Digest: artifact.Digest}
+			for _, tag := range artifact.Tags {
+				row.Tags = append(row.Tags, tag.Name)
+			}
+			if err := export(e, artifacts, row); err != nil {
+				return err
+			}
+		}
+		if len(*list) < listPageSize {
+			return e.EndPage(next)
+		}
+		if err := e.EndPage(Checkpoint{Page: repositoryPage, Repository: repository, RepositoryPage: page + 1}); err != nil {
+			return err
+		}
+	}
+}
+
+// closeExport completes the export of e and returns its final checkpoint.
+func closeExport(e *NDJSONExporter) (*Checkpoint, error) {
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	checkpoint := e.Checkpoint()
+	return &checkpoint, nil
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hujianxiong/go-harbor/pkg/controller"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+	"k8s.io/klog"
+)
+
+const (
+	// defaultGrowthWindow is the default period the growth is estimated over.
+	defaultGrowthWindow = 30 * 24 * time.Hour
+	// defaultWarnWithin is the default horizon of the quota exhaustion warnings.
+	defaultWarnWithin = 14 * 24 * time.Hour
+)
+
+// StorageSample is the storage usage of a project at a point in time.
+type StorageSample struct {
+	Time time.Time `json:"time"`
+	Used int64     `json:"used"`
+	// Hard is the storage quota of the project, -1 if unlimited.
+	Hard int64 `json:"hard"`
+}
+
+// samplesKey is the key the samples of a project are stored under.
+func samplesKey(project string) string {
+	return "storage-samples/" + project
+}
+
+// loadSamples returns the samples of a project kept in s, oldest first.
+func loadSamples(s store.Store, project string) ([]StorageSample, error) {
+	data, ok, err := s.Get(samplesKey(project))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var samples []StorageSample
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// saveSamples replaces the samples of a project kept in s.
+func saveSamples(s store.Store, project string, samples []StorageSample) error {
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return err
+	}
+	return s.Put(samplesKey(project), data)
+}
+
+// GrowthEstimate is the storage growth of a project and the date its quota is projected
+// to be exhausted at.
+type GrowthEstimate struct {
+	Project string `json:"project"`
+	// Samples is the number of samples the estimate is based on.
+	Samples int   `json:"samples"`
+	Used    int64 `json:"used"`
+	Hard    int64 `json:"hard"`
+	// BytesPerDay is the growth rate, negative if the usage decreases.
+	BytesPerDay float64 `json:"bytes_per_day"`
+	// Exhaustion is when the quota is projected to be exhausted, nil if the quota is
+	// unlimited, the usage doesn't grow or the exhaustion is too far away to be
+	// represented, i.e. beyond about 292 years.
+	Exhaustion *time.Time `json:"exhaustion,omitempty"`
+}
+
+// EstimateGrowth fits the usage of the samples, oldest first, with a linear regression and
+// projects when the quota of the latest sample is exhausted. It returns nil with less than
+// two samples.
+func EstimateGrowth(project string, samples []StorageSample) *GrowthEstimate {
+	if len(samples) < 2 {
+		return nil
+	}
+	last := samples[len(samples)-1]
+	estimate := &GrowthEstimate{Project: project, Samples: len(samples), Used: last.Used, Hard: last.Hard}
+
+	origin := samples[0].Time
+	var meanX, meanY float64
+	for _, sample := range samples {
+		meanX += sample.Time.Sub(origin).Seconds()
+		meanY += float64(sample.Used)
+	}
+	meanX /= float64(len(samples))
+	meanY /= float64(len(samples))
+	var covariance, variance float64
+	for _, sample := range samples {
+		dx := sample.Time.Sub(origin).Seconds() - meanX
+		covariance += dx * (float64(sample.Used) - meanY)
+		variance += dx * dx
+	}
+	if variance == 0 {
+		return estimate
+	}
+	perSecond := covariance / variance
+	estimate.BytesPerDay = perSecond * (24 * time.Hour).Seconds()
+
+	if last.Hard < 0 || perSecond <= 0 {
+		return estimate
+	}
+	exhaustion := last.Time
+	if remaining := last.Hard - last.Used; remaining > 0 {
+		seconds := float64(remaining) / perSecond
+		if seconds > float64(math.MaxInt64/int64(time.Second)) {
+			// a time.Duration would overflow, the quota is not exhausted in any foreseeable time
+			return estimate
+		}
+		exhaustion = last.Time.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	estimate.Exhaustion = &exhaustion
+	return estimate
+}
+
+// ProjectSummaryGetter gets the summary of a project, it is implemented by
+// project.ProjectsV2Client.
+type ProjectSummaryGetter interface {
+	Summary(ctx context.Context, name string) (result *model.ProjectSummary, err error)
+}
+
+// GrowthEstimator samples the storage usage of projects, persisting the samples of the
+// estimation window in Store under storage-samples/<project>, and warns when the quota of
+// a project is projected to be exhausted soon. It is a controller.Reconciler keyed by
+// project name, run it with controller.ProjectLister and a resync period to sample all
+// the projects periodically.
+type GrowthEstimator struct {
+	Projects ProjectSummaryGetter
+	Store    store.Store
+	// Window is the period the growth is estimated over, 30 days by default.
+	Window time.Duration
+	// WarnWithin is the horizon of the warnings, 14 days by default: Warn is called for the
+	// projects whose quota is projected to be exhausted within it.
+	WarnWithin time.Duration
+	// Warn receives the warnings, they are logged by default.
+	Warn func(estimate *GrowthEstimate)
+	// Now returns the time of the samples, time.Now by default.
+	Now func() time.Time
+}
+
+// Reconcile implements controller.Reconciler, it samples the usage of the project key and
+// estimates its growth.
+func (g *GrowthEstimator) Reconcile(ctx context.Context, key string) (controller.Result, error) {
+	_, err := g.Sample(ctx, key)
+	return controller.Result{}, err
+}
+
+// Sample records the current storage usage of a project and returns its growth estimate,
+// nil until there are enough samples.
+func (g *GrowthEstimator) Sample(ctx context.Context, project string) (*GrowthEstimate, error) {
+	summary, err := g.Projects.Summary(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("get summary of project %s error: %v", project, err)
+	}
+	if summary.Quota == nil {
+		return nil, nil
+	}
+	now := time.Now
+	if g.Now != nil {
+		now = g.Now
+	}
+	sample := StorageSample{Time: now(), Used: summary.Quota.Used["storage"], Hard: summary.Quota.Hard["storage"]}
+	samples, err := loadSamples(g.Store, project)
+	if err != nil {
+		return nil, fmt.Errorf("read storage samples of project %s error: %v", project, err)
+	}
+
+	window, warnWithin := g.Window, g.WarnWithin
+	if window <= 0 {
+		window = defaultGrowthWindow
+	}
+	if warnWithin <= 0 {
+		warnWithin = defaultWarnWithin
+	}
+	// the samples out of the window are dropped, the stored ones are rewritten every time
+	start := 0
+	for start < len(samples) && samples[start].Time.Before(sample.Time.Add(-window)) {
+		start++
+	}
+	samples = append(samples[start:], sample)
+	if err := saveSamples(g.Store, project, samples); err != nil {
+		return nil, fmt.Errorf("store storage sample of project %s error: %v", project, err)
+	}
+	estimate := EstimateGrowth(project, samples)
+	if estimate != nil && estimate.Exhaustion != nil && estimate.Exhaustion.Before(sample.Time.Add(warnWithin)) {
+		if g.Warn != nil {
+			g.Warn(estimate)
+		} else {
+			klog.Warningf("Storage quota of project %s is projected to be exhausted on %s, growing by %.0f bytes per day",
+				project, estimate.Exhaustion.Format(time.RFC3339), estimate.BytesPerDay)
+		}
+	}
+	return estimate, nil
+}
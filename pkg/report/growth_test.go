@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+)
+
+const gib = 1 << 30
+
+func TestEstimateGrowth(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var samples []StorageSample
+	for day := 0; day < 10; day++ {
+		samples = append(samples, StorageSample{Time: start.AddDate(0, 0, day), Used: int64(10+day) * gib, Hard: 30 * gib})
+	}
+	estimate := EstimateGrowth("library", samples)
+	if estimate.BytesPerDay != gib {
+		t.Errorf("expected a growth of 1GiB per day, got %f", estimate.BytesPerDay)
+	}
+	// 19GiB used on day 9, 11 days left
+	if expected := start.AddDate(0, 0, 20); estimate.Exhaustion == nil || !estimate.Exhaustion.Equal(expected) {
+		t.Errorf("expected the quota to be exhausted on %s, got %v", expected, estimate.Exhaustion)
+	}
+
+	samples[len(samples)-1].Hard = -1
+	if estimate := EstimateGrowth("library", samples); estimate.Exhaustion != nil {
+		t.Errorf("expected no exhaustion without quota, got %v", estimate.Exhaustion)
+	}
+	if estimate := EstimateGrowth("library", samples[:1]); estimate != nil {
+		t.Errorf("expected no estimate with a single sample, got %+v", estimate)
+	}
+}
+
+func TestEstimateGrowthBeyondHorizon(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	const tb, mb = 1 << 40, 1 << 20
+	var samples []StorageSample
+	for day := 0; day < 10; day++ {
+		samples = append(samples, StorageSample{Time: start.AddDate(0, 0, day), Used: int64(day) * 5 * mb, Hard: tb})
+	}
+	// about 600 years at 5MB per day, beyond what a time.Duration holds
+	estimate := EstimateGrowth("library", samples)
+	if estimate.BytesPerDay != 5*mb {
+		t.Errorf("expected a growth of 5MiB per day, got %f", estimate.BytesPerDay)
+	}
+	if estimate.Exhaustion != nil {
+		t.Errorf("expected no exhaustion within the horizon, got %v", estimate.Exhaustion)
+	}
+}
+
+type fakeSummaries struct {
+	used int64
+}
+
+func (f *fakeSummaries) Summary(ctx context.Context, name string) (*model.ProjectSummary, error) {
+	return &model.ProjectSummary{Quota: &model.ProjectSummaryQuota{
+		Hard: map[string]int64{"storage": 10 * gib},
+		Used: map[string]int64{"storage": f.used},
+	}}, nil
+}
+
+func TestGrowthEstimatorWarns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "samples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	samples, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	summaries := &fakeSummaries{}
+	var warnings []*GrowthEstimate
+	estimator := &GrowthEstimator{
+		Projects: summaries,
+		Store:    samples,
+		Now:      func() time.Time { return now },
+		Warn:     func(estimate *GrowthEstimate) { warnings = append(warnings, estimate) },
+	}
+	for day := 0; day < 5; day++ {
+		summaries.used = int64(day) * gib / 10
+		if _, err := estimator.Reconcile(context.Background(), "library"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warning for a slow growth, got %+v", warnings[0])
+	}
+
+	for day := 0; day < 5; day++ {
+		summaries.used += 2 * gib
+		if _, err := estimator.Reconcile(context.Background(), "library"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+	if len(warnings) == 0 || warnings[len(warnings)-1].Samples != 10 {
+		t.Errorf("expected warnings based on the persisted samples, got %+v", warnings)
+	}
+}
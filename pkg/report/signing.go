@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/pager"
+)
+
+// cosignTag matches the tags cosign stores signatures under, e.g. sha256-<hex>.sig for the
+// artifact sha256:<hex>, when the registry does not support accessories.
+var cosignTag = regexp.MustCompile(`^(sha256)-([a-f0-9]{64})\.sig$`)
+
+// TagSignature is the signing status of a tag.
+type TagSignature struct {
+	Project    string `json:"project"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Digest     string `json:"digest"`
+	// Notary is true if the tag is signed with notary (content trust).
+	Notary bool `json:"notary"`
+	// Cosign is true if the artifact of the tag has a cosign signature.
+	Cosign bool `json:"cosign"`
+}
+
+// Signed returns true if the tag is signed with notary or cosign.
+func (t *TagSignature) Signed() bool {
+	return t.Notary || t.Cosign
+}
+
+// RepositorySigning is the signing status of the tags of a repository.
+type RepositorySigning struct {
+	Project    string         `json:"project"`
+	Repository string         `json:"repository"`
+	Tags       []TagSignature `json:"tags"`
+}
+
+// Signed returns the number of signed tags of the repository.
+func (r *RepositorySigning) Signed() int {
+	signed := 0
+	for i := range r.Tags {
+		if r.Tags[i].Signed() {
+			signed++
+		}
+	}
+	return signed
+}
+
+// TagSigning reports, for each repository of a project, whether each tag is signed with
+// notary or cosign, e.g. to track the progress of a signing rollout. Cosign signatures are
+// detected both as accessories and as sha256-<hex>.sig tags, the signature tags themselves
+// are not reported.
+func TagSigning(ctx context.Context, repositories RepositoriesGetter, project string) ([]RepositorySigning, error) {
+	var reports []RepositorySigning
+	err := pager.EachPage(ctx, listPageSize, func(pageCtx context.Context, page, pageSize int64) (int, error) {
+		repos, err := repositories.Repositories(project).List(pageCtx, &model.Query{Page: page, PageSize: pageSize})
+		if err != nil {
+			return 0, fmt.Errorf("list repositories of project %s error: %v", project, err)
+		}
+		for _, repo := range *repos {
+			name := strings.TrimPrefix(repo.Name, project+"/")
+			report, err := repositorySigning(ctx, repositories, project, name)
+			if err != nil {
+				return 0, err
+			}
+			reports = append(reports, *report)
+		}
+		return len(*repos), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func repositorySigning(ctx context.Context, repositories RepositoriesGetter, project, repository string) (*RepositorySigning, error) {
+	artifacts := repositories.Repositories(project).Artifacts(url.PathEscape(repository))
+	report := &RepositorySigning{Project: project, Repository: repository}
+	cosignSigned := map[string]bool{}
+	for page := int64(1); ; page++ {
+		list, err := artifacts.ListWithQuery(ctx, &model.ArtifactQuery{
+			Query:         model.Query{Page: page, PageSize: listPageSize},
+			WithTag:       true,
+			WithSignature: true,
+			WithAccessory: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list artifacts of %s/%s error: %v", project, repository, err)
+		}
+		for _, artifact := range *list {
+			for _, accessory := range artifact.Accessories {
+				if accessory.Type == model.AccessoryTypeCosignSignature {
+					cosignSigned[artifact.Digest] = true
+				}
+			}
+			for _, tag := range artifact.Tags {
+				if m := cosignTag.FindStringSubmatch(tag.Name); m != nil {
+					cosignSigned[m[1]+":"+m[2]] = true
+					continue
+				}
+				report.Tags = append(report.Tags, TagSignature{
+					Project:    project,
+					Repository: repository,
+					Tag:        tag.Name,
+					Digest:     artifact.Digest,
+					Notary:     tag.Signed,
+				})
+			}
+		}
+		if len(*list) < listPageSize {
+			break
+		}
+	}
+	for i := range report.Tags {
+		report.Tags[i].Cosign = cosignSigned[report.Tags[i].Digest]
+	}
+	return report, nil
+}
+
+// WriteTagSigningCSV writes one CSV record per tag of the reports, with a header.
+func WriteTagSigningCSV(w io.Writer, reports []RepositorySigning) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"project", "repository", "tag", "digest", "notary", "cosign", "signed"}); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		for _, tag := range report.Tags {
+			err := writer.Write([]string{
+				tag.Project,
+				tag.Repository,
+				tag.Tag,
+				tag.Digest,
+				strconv.FormatBool(tag.Notary),
+				strconv.FormatBool(tag.Cosign),
+				strconv.FormatBool(tag.Signed()),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"github.com/hujianxiong/go-harbor/pkg/pager"
+)
+
+// listPageSize is the page size used to list the repositories and artifacts of a project.
+const listPageSize = 100
+
+// StorageOptions holds the optional settings of StorageBreakdown.
+type StorageOptions struct {
+	// Top is the number of largest layers reported, 20 by default.
+	Top int
+}
+
+// LayerUsage is a blob of a project and the artifacts referencing it.
+type LayerUsage struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type,omitempty"`
+	Size      int64  `json:"size"`
+	// Artifacts is the number of manifests of the project referencing the blob.
+	Artifacts    int      `json:"artifacts"`
+	Repositories []string `json:"repositories"`
+}
+
+// Shared returns true if more than one repository references the blob.
+func (l *LayerUsage) Shared() bool {
+	return len(l.Repositories) > 1
+}
+
+// ProjectStorage is the storage breakdown of a project by blob reuse.
+type ProjectStorage struct {
+	Project string `json:"project"`
+	// Artifacts is the number of manifests read, including the children of indexes.
+	Artifacts int `json:"artifacts"`
+	Blobs     int `json:"blobs"`
+	// LogicalSize is the sum of the sizes of all the artifacts, as if no blob was shared.
+	LogicalSize int64 `json:"logical_size"`
+	// UniqueSize is the size of the distinct blobs, the space the project actually consumes.
+	UniqueSize int64 `json:"unique_size"`
+	// SharedSize is the size of the distinct blobs referenced by more than one repository.
+	SharedSize int64 `json:"shared_size"`
+	// LargestUniqueLayers are the largest blobs referenced by a single repository, the ones
+	// deleting the repository would free.
+	LargestUniqueLayers []*LayerUsage `json:"largest_unique_layers"`
+	// LargestSharedLayers are the largest blobs referenced by several repositories.
+	LargestSharedLayers []*LayerUsage `json:"largest_shared_layers"`
+	// Errors lists the artifacts whose manifest could not be read, they are not counted.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// StorageBreakdown computes a best effort storage breakdown of a project. Harbor does not
+// expose its blobs through the API, so the breakdown is computed from the manifests of the
+// artifacts read through the registry API: blobs shared across artifacts are only counted
+// once, and blobs no longer referenced by any manifest, which garbage collection would
+// delete, are not visible at all.
+func StorageBreakdown(ctx context.Context, repositories RepositoriesGetter, project string, opts *StorageOptions) (*ProjectStorage, error) {
+	if opts == nil {
+		opts = &StorageOptions{}
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = 20
+	}
+	result := &ProjectStorage{Project: project}
+	blobs := map[string]*LayerUsage{}
+	add := func(repository string, descriptor *model.Descriptor) {
+		usage, ok := blobs[descriptor.Digest]
+		if !ok {
+			usage = &LayerUsage{Digest: descriptor.Digest, MediaType: descriptor.MediaType, Size: descriptor.Size}
+			blobs[descriptor.Digest] = usage
+		}
+		usage.Artifacts++
+		if n := len(usage.Repositories); n == 0 || usage.Repositories[n-1] != repository {
+			usage.Repositories = append(usage.Repositories, repository)
+		}
+		result.LogicalSize += descriptor.Size
+	}
+
+	err := pager.EachPage(ctx, listPageSize, func(pageCtx context.Context, page, pageSize int64) (int, error) {
+		repos, err := repositories.Repositories(project).List(pageCtx, &model.Query{Page: page, PageSize: pageSize})
+		if err != nil {
+			return 0, fmt.Errorf("list repositories of project %s error: %v", project, err)
+		}
+		for _, repo := range *repos {
+			name := strings.TrimPrefix(repo.Name, project+"/")
+			if err := collectRepositoryBlobs(ctx, repositories, project, name, result, add); err != nil {
+				return 0, err
+			}
+		}
+		return len(*repos), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unique, shared []*LayerUsage
+	for _, usage := range blobs {
+		result.UniqueSize += usage.Size
+		if usage.Shared() {
+			result.SharedSize += usage.Size
+			shared = append(shared, usage)
+		} else {
+			unique = append(unique, usage)
+		}
+	}
+	result.Blobs = len(blobs)
+	result.LargestUniqueLayers = largestLayers(unique, top)
+	result.LargestSharedLayers = largestLayers(shared, top)
+	return result, nil
+}
+
+// collectRepositoryBlobs adds the blobs of every artifact of a repository, the children
+// of an index are read as well since they are not listed as artifacts.
+func collectRepositoryBlobs(ctx context.Context, repositories RepositoriesGetter, project, repository string, result *ProjectStorage, add func(string, *model.Descriptor)) error {
+	artifacts := repositories.Repositories(project).Artifacts(url.PathEscape(repository))
+	seen := map[string]bool{}
+	var collect func(digest string)
+	collect = func(digest string) {
+		if seen[digest] {
+			return
+		}
+		seen[digest] = true
+		manifest, err := artifacts.Manifest(ctx, digest)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s/%s@%s: %v", project, repository, digest, err))
+			return
+		}
+		result.Artifacts++
+		if manifest.Config != nil {
+			add(repository, manifest.Config)
+		}
+		for _, layer := range manifest.Layers {
+			add(repository, layer)
+		}
+		for _, child := range manifest.Manifests {
+			collect(child.Digest)
+		}
+	}
+
+	return pager.EachPage(ctx, listPageSize, func(ctx context.Context, page, pageSize int64) (int, error) {
+		list, err := artifacts.List(ctx, &model.Query{Page: page, PageSize: pageSize})
+		if err != nil {
+			return 0, fmt.Errorf("list artifacts of %s/%s error: %v", project, repository, err)
+		}
+		for _, artifact := range *list {
+			collect(artifact.Digest)
+		}
+		return len(*list), nil
+	})
+}
+
+// largestLayers sorts the layers by size, largest first, and keeps the first n.
+func largestLayers(layers []*LayerUsage, n int) []*LayerUsage {
+	sort.Slice(layers, func(i, j int) bool {
+		if layers[i].Size != layers[j].Size {
+			return layers[i].Size > layers[j].Size
+		}
+		return layers[i].Digest < layers[j].Digest
+	})
+	if len(layers) > n {
+		layers = layers[:n]
+	}
+	return layers
+}
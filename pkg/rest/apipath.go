@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"k8s.io/klog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LegacyVersionApiPath is the path of the API of Harbor 1.x.
+	LegacyVersionApiPath = "/api"
+	// AutoAPIVersionPath is the Config.APIVersionPath detecting the path of the API on
+	// first use, DefaultVersionApiPath for Harbor 2.x or LegacyVersionApiPath for Harbor 1.x.
+	AutoAPIVersionPath = "auto"
+)
+
+// apiPathRetryMin and apiPathRetryMax bound the delay before probing the API path again
+// after a failed probe, doubling with every failure.
+const (
+	apiPathRetryMin = time.Second
+	apiPathRetryMax = time.Minute
+)
+
+// apiPathDetector detects the path of the API of a server once for all the clients of a
+// Config.
+type apiPathDetector struct {
+	mu       sync.Mutex
+	path     string
+	probe    *apiPathProbe
+	failures int
+	retryAt  time.Time
+}
+
+// apiPathProbe is a probe of the API path, whose result is ready once done is closed.
+type apiPathProbe struct {
+	done chan struct{}
+	path string
+}
+
+// detect returns the path of the API of the server at base, probed with ctx on the first
+// call. The callers arriving while the probe runs wait for its result. After a failed
+// probe, DefaultVersionApiPath is used until the next probe, delayed exponentially so
+// that an unreachable server isn't probed again by every request.
+func (d *apiPathDetector) detect(ctx context.Context, client HTTPClient, base *url.URL, headers http.Header, timeout time.Duration) string {
+	d.mu.Lock()
+	if d.path != "" || time.Now().Before(d.retryAt) {
+		defer d.mu.Unlock()
+		if d.path != "" {
+			return d.path
+		}
+		return DefaultVersionApiPath
+	}
+	probe := d.probe
+	if probe == nil {
+		probe = &apiPathProbe{done: make(chan struct{})}
+		d.probe = probe
+		d.mu.Unlock()
+		d.run(ctx, probe, client, base, headers, timeout)
+		return probe.path
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-probe.done:
+		return probe.path
+	case <-ctx.Done():
+		// the request fails with the context error anyway
+		return DefaultVersionApiPath
+	}
+}
+
+// run probes the API path into probe and records the result.
+func (d *apiPathDetector) run(ctx context.Context, probe *apiPathProbe, client HTTPClient, base *url.URL, headers http.Header, timeout time.Duration) {
+	defer close(probe.done)
+	apiPath, err := probeAPIPath(ctx, client, base, headers, timeout)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.probe = nil
+	if err == nil {
+		d.path, probe.path = apiPath, apiPath
+		return
+	}
+	probe.path = DefaultVersionApiPath
+	if ctx.Err() != nil {
+		// canceled by the caller, which says nothing about the server
+		return
+	}
+	d.failures++
+	delay := apiPathRetryMin
+	for i := 1; i < d.failures && delay < apiPathRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > apiPathRetryMax {
+		delay = apiPathRetryMax
+	}
+	d.retryAt = time.Now().Add(delay)
+	klog.V(2).Infof("Could not detect the API path of %s, using %s for %v: %v", base.Redacted(), DefaultVersionApiPath, delay, err)
+}
+
+// detectAPIPath replaces the default versioned API path of r with the one detected for the
+// server, unless the path was overwritten with AbsPath or RequestURI.
+func (r *Request) detectAPIPath() {
+	if r.apiPath == nil {
+		return
+	}
+	detector := r.apiPath
+	r.apiPath = nil
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	apiPath := detector.detect(ctx, r.client, r.baseURL, r.headers, r.timeout)
+	if apiPath == DefaultVersionApiPath {
+		return
+	}
+	base := "/"
+	if r.baseURL != nil {
+		base = path.Join(base, r.baseURL.Path)
+	}
+	defaultPrefix := path.Join(base, DefaultVersionApiPath)
+	if r.pathPrefix == defaultPrefix || strings.HasPrefix(r.pathPrefix, defaultPrefix+"/") {
+		r.pathPrefix = path.Join(base, apiPath) + strings.TrimPrefix(r.pathPrefix, defaultPrefix)
+	}
+}
+
+// probeAPIPath pings the API of Harbor 2.x, the server being a Harbor 1.x if it isn't found.
+func probeAPIPath(ctx context.Context, client HTTPClient, base *url.URL, headers http.Header, timeout time.Duration) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if timeout <= 0 {
+		timeout = time.Duration(DefaultTimeOut) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	u := *base
+	u.Path = path.Join("/", base.Path, DefaultVersionApiPath, "ping")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = headers.Clone()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return DefaultVersionApiPath, nil
+	case http.StatusNotFound:
+		return LegacyVersionApiPath, nil
+	}
+	return "", fmt.Errorf("ping %s: unexpected status code %d", u.Redacted(), resp.StatusCode)
+}
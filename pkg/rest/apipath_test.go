@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAutoAPIVersionPath(t *testing.T) {
+	for apiPath, legacy := range map[string]bool{DefaultVersionApiPath: false, LegacyVersionApiPath: true} {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			paths = append(paths, req.URL.Path)
+			if legacy && req.URL.Path == "/api/v2.0/ping" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("{}"))
+		}))
+
+		config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+		config.APIVersionPath = AutoAPIVersionPath
+		projects, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		users, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range []*Request{projects.Get().Resource("projects"), users.Get().Resource("users"), projects.Get().Resource("projects")} {
+			if err := r.Do(context.Background()).Error(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected := []string{"/api/v2.0/ping", apiPath + "/projects", apiPath + "/users", apiPath + "/projects"}
+		if len(paths) != len(expected) {
+			t.Fatalf("expected a single detection for %s, got the requests %v", apiPath, paths)
+		}
+		for i := range expected {
+			if paths[i] != expected[i] {
+				t.Errorf("expected the requests %v, got %v", expected, paths)
+				break
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestAPIVersionPath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.APIVersionPath = LegacyVersionApiPath
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/projects" {
+		t.Errorf("unexpected path %s", path)
+	}
+}
+
+func TestAutoAPIVersionPathSingleProbe(t *testing.T) {
+	var mu sync.Mutex
+	var pings int
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if req.URL.Path == "/api/v2.0/ping" {
+			pings++
+			// a Harbor 1.x, answering slowly so that the requests wait for the probe
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		paths = append(paths, req.URL.Path)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.APIVersionPath = AutoAPIVersionPath
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if pings != 1 {
+		t.Errorf("expected a single probe, got %d", pings)
+	}
+	for _, path := range paths {
+		if path != "/api/projects" {
+			t.Errorf("expected the detected API path, got the requests %v", paths)
+			break
+		}
+	}
+}
+
+func TestAutoAPIVersionPathBackoff(t *testing.T) {
+	var pings int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v2.0/ping" {
+			atomic.AddInt32(&pings, 1)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.APIVersionPath = AutoAPIVersionPath
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&pings); n != 1 {
+		t.Errorf("expected the failed probe not to be repeated by every request, got %d probes", n)
+	}
+}
+
+func TestAutoAPIVersionPathCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v2.0/ping" {
+			select {
+			case <-req.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.APIVersionPath = AutoAPIVersionPath
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	// building the request doesn't probe the server
+	request := client.Get().Resource("projects")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := request.Do(ctx).Error(); err == nil {
+		t.Error("expected the request to fail with its context")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the probe to end with the context of the request, took %v", elapsed)
+	}
+}
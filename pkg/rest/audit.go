@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+)
+
+// AuditRecord is a client side record of a mutating operation (POST, PUT, PATCH or DELETE),
+// complementing the audit log of Harbor with the intent of the client. The parameters
+// are hashed so that records can be correlated without leaking their content.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Actor is the username the request is authenticated as, "bearer" for a bearer token
+	// or empty for anonymous requests.
+	Actor string `json:"actor"`
+	// Operation is the HTTP verb and Resource the path of the request.
+	Operation string `json:"operation"`
+	Resource  string `json:"resource"`
+	// ParametersHash is the hex encoded SHA-256 of the query and the body of the request.
+	ParametersHash string        `json:"parameters_hash"`
+	StatusCode     int           `json:"status_code"`
+	Success        bool          `json:"success"`
+	Error          string        `json:"error,omitempty"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// AuditHook receives a record of every mutating operation once it completed, e.g. to
+// forward it to an audit system. It is called synchronously so it should not block.
+type AuditHook interface {
+	Audit(record *AuditRecord)
+}
+
+// AuditHookFunc adapts a function to an AuditHook.
+type AuditHookFunc func(record *AuditRecord)
+
+// Audit calls f(record).
+func (f AuditHookFunc) Audit(record *AuditRecord) {
+	f(record)
+}
+
+// isMutating returns true if the verb changes resources.
+func isMutating(verb string) bool {
+	switch verb {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// actor returns the identity the credentials authenticate as.
+func (c *credentials) actor() string {
+	switch {
+	case c == nil:
+		return ""
+	case !c.bearerToken.Empty():
+		return "bearer"
+	}
+	return c.username
+}
+
+// newAuditRecord starts the record of the request.
+func (r *Request) newAuditRecord() *AuditRecord {
+	u := r.URL()
+	hash := sha256.New()
+	io.WriteString(hash, u.RawQuery)
+	hash.Write([]byte{'\n'})
+	// the body is read again when the request is sent, it can only be hashed if it can be rewound
+	if seeker, ok := r.body.(io.ReadSeeker); ok {
+		if _, err := io.Copy(hash, seeker); err == nil {
+			seeker.Seek(0, io.SeekStart)
+		}
+	}
+	return &AuditRecord{
+		Time:           time.Now(),
+		Actor:          r.credentials.actor(),
+		Operation:      r.verb,
+		Resource:       u.Path,
+		ParametersHash: hex.EncodeToString(hash.Sum(nil)),
+	}
+}
+
+// finish completes the record with the outcome of the request.
+func (a *AuditRecord) finish(err error) *AuditRecord {
+	a.Duration = time.Since(a.Time)
+	a.Success = err == nil && a.StatusCode >= http.StatusOK && a.StatusCode < http.StatusMultipleChoices
+	if err != nil {
+		a.Error = err.Error()
+	}
+	return a
+}
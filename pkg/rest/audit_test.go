@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuditHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var records []*AuditRecord
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.AuditHook = AuditHookFunc(func(record *AuditRecord) {
+		records = append(records, record)
+	})
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Post().Resource("projects").Body(map[string]string{"project_name": "a"}).Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Get().Resource("projects").Do(context.Background())
+	if err := client.Delete().Resource("projects").Name("a").Do(context.Background()).Error(); err == nil {
+		t.Fatalf("expected an error")
+	}
+	client.Post().Resource("projects").Body(map[string]string{"project_name": "b"}).Do(context.Background())
+
+	if len(records) != 3 {
+		t.Fatalf("expected the 3 mutating requests to be recorded, got %d", len(records))
+	}
+	created, deleted, other := records[0], records[1], records[2]
+	if created.Actor != "admin" || created.Operation != http.MethodPost || created.Resource != "/api/v2.0/projects" ||
+		!created.Success || created.StatusCode != http.StatusCreated {
+		t.Errorf("unexpected record: %#v", created)
+	}
+	if deleted.Success || deleted.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("expected a failed record, got %#v", deleted)
+	}
+	if created.ParametersHash == "" || created.ParametersHash == other.ParametersHash {
+		t.Errorf("expected distinct parameter hashes, got %s and %s", created.ParametersHash, other.ParametersHash)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults of CircuitBreaker.
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitCoolDown         = 30 * time.Second
+)
+
+// ErrCircuitOpen is matched, with errors.Is, by the errors of the requests failed fast by
+// an open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is the error of a request failed fast by an open circuit breaker, see
+// Config.CircuitBreaker.
+type CircuitOpenError struct {
+	URL string
+	// Until is the end of the cool-down, when a request is let through to probe the server.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s refused until %s: %v", e.URL, e.Until.Format(time.RFC3339), ErrCircuitOpen)
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitBreaker fails the requests fast once the server failed FailureThreshold requests
+// in a row, with a 5xx status code or a connection error, for CoolDown. A single request
+// is then let through: the circuit closes if it succeeds, and opens again otherwise. It is
+// safe for concurrent use and meant to be shared by the clients of a same server.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures opening the circuit,
+	// DefaultCircuitFailureThreshold if 0.
+	FailureThreshold int
+	// CoolDown is the time the circuit stays open, DefaultCircuitCoolDown if 0.
+	CoolDown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+	// now returns the current time, time.Now if nil.
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a circuit breaker opening after failureThreshold consecutive
+// failures for coolDown.
+func NewCircuitBreaker(failureThreshold int, coolDown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: failureThreshold, CoolDown: coolDown}
+}
+
+// Open returns true if the circuit is open, whether or not its cool-down has elapsed.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero()
+}
+
+// allow returns a CircuitOpenError if the request must fail fast.
+func (b *CircuitBreaker) allow(url string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	until := b.openedAt.Add(b.coolDown())
+	if b.probing || b.clock().Before(until) {
+		return &CircuitOpenError{URL: url, Until: until}
+	}
+	b.probing = true
+	return nil
+}
+
+// record records the outcome of a request let through by allow, a nil response meaning
+// the request failed before getting one.
+func (b *CircuitBreaker) record(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil && resp != nil && resp.StatusCode < http.StatusInternalServerError {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+	b.failures++
+	threshold := b.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultCircuitFailureThreshold
+	}
+	if b.failures >= threshold || !b.openedAt.IsZero() {
+		b.openedAt = b.clock()
+	}
+}
+
+// release ends the probe of a request let through by allow whose outcome is unknown.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *CircuitBreaker) coolDown() time.Duration {
+	if b.CoolDown <= 0 {
+		return DefaultCircuitCoolDown
+	}
+	return b.CoolDown
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now == nil {
+		return time.Now()
+	}
+	return b.now()
+}
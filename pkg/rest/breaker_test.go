@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var attempts int
+	status := http.StatusBadGateway
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.CircuitBreaker = breaker
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func() error {
+		return client.Get().Resource("projects").Do(context.Background()).Error()
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := do(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the error of the server, got %v", err)
+		}
+	}
+	if !breaker.Open() {
+		t.Fatal("the circuit should be open after 2 failures")
+	}
+	var openErr *CircuitOpenError
+	if err := do(); !errors.As(err, &openErr) || !openErr.Until.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the request to fail fast until the end of the cool-down, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("the server should not be called while the circuit is open, got %d attempts", attempts)
+	}
+
+	// the probe fails, the circuit opens again
+	now = now.Add(time.Minute)
+	if err := do(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the probe to reach the server, got %v", err)
+	}
+	if err := do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the circuit to open again after a failed probe, got %v", err)
+	}
+
+	// the probe succeeds, the circuit closes
+	now, status = now.Add(time.Minute), http.StatusNotFound
+	if err := do(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the probe to reach the server, got %v", err)
+	}
+	if breaker.Open() {
+		t.Error("a 4xx response should close the circuit")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // Interface captures the set of operations for generically interacting with Kubernetes REST apis.
@@ -27,6 +28,7 @@ type Interface interface {
 	Verb(verb string) *Request
 	Post() *Request
 	Put() *Request
+	Patch() *Request
 	List() *Request
 	Get() *Request
 	Delete() *Request
@@ -50,6 +52,30 @@ type RESTClient struct {
 
 	Throttle flowcontrol2.RateLimiter
 	headers  map[string]string
+	// credentials authenticate every request, the Authorization header is computed when
+	// the request is sent.
+	credentials *credentials
+	// auditHook records the mutating requests of the client.
+	auditHook AuditHook
+	// readOnly refuses the mutating requests of the client.
+	readOnly bool
+	// retryPolicy retries the requests of the client failing with a transient status code.
+	retryPolicy *RetryPolicy
+	// circuitBreaker fails the requests of the client fast while the server is failing.
+	circuitBreaker *CircuitBreaker
+	// logger logs the requests of the client.
+	logger Logger
+	// metrics records the measurements of the requests of the client.
+	metrics Metrics
+	// disableCompression does not ask the server for compressed responses.
+	disableCompression bool
+	// apiPath detects the versioned API path on first use instead of versionedAPIPath.
+	apiPath *apiPathDetector
+	// recorder captures the mutating requests of the client instead of sending them.
+	recorder *Recorder
+	// timeout bounds the requests of the client, unless overridden by Request.Timeout. It
+	// takes precedence over the timeout of Client.
+	timeout time.Duration
 	// Set specific behavior of the client.  If not set http.DefaultClient will be used.
 	Client *http.Client
 }
@@ -66,6 +92,10 @@ func (c *RESTClient) Put() *Request {
 	return c.Verb("PUT")
 }
 
+func (c *RESTClient) Patch() *Request {
+	return c.Verb("PATCH")
+}
+
 // NewRESTClient creates a new RESTClient. This client performs generic REST functions
 // such as Get, Put, Post, and Delete on specified paths.  Codec controls encoding and
 // decoding of responses from the server.
@@ -128,8 +158,55 @@ func (c *RESTClient) Delete() *Request {
 // list, ok := resp.(*api.PodList)
 //
 func (c *RESTClient) Verb(verb string) *Request {
+	versionedAPIPath := c.versionedAPIPath
+	var r *Request
 	if c.Client == nil {
-		return NewRequest(nil, verb, c.base, c.headers, c.versionedAPIPath, c.contentConfig, c.Throttle, 0)
+		r = NewRequest(nil, verb, c.base, c.headers, versionedAPIPath, c.contentConfig, c.Throttle, c.timeout)
+	} else {
+		timeout := c.timeout
+		if timeout == 0 {
+			timeout = c.Client.Timeout
+		}
+		r = NewRequest(c.Client, verb, c.base, c.headers, versionedAPIPath, c.contentConfig, c.Throttle, timeout)
+	}
+	r.credentials = c.credentials
+	// the API path is detected when the request is sent, with its context
+	r.apiPath = c.apiPath
+	r.auditHook = c.auditHook
+	r.readOnly = c.readOnly
+	r.retryPolicy = c.retryPolicy
+	r.circuitBreaker = c.circuitBreaker
+	r.logger = c.logger
+	r.metrics = c.metrics
+	r.disableCompression = c.disableCompression
+	r.recorder = c.recorder
+	return r
+}
+
+// Use wraps the transport of the client with the middlewares, each one wrapping the
+// previous ones, e.g. to refresh credentials, log the traffic or inject failures. It must
+// be called before the client is used.
+func (c *RESTClient) Use(middlewares ...WrapperFunc) {
+	wrap := Wrappers(middlewares...)
+	if wrap == nil {
+		return
+	}
+	client := &http.Client{}
+	if c.Client != nil {
+		*client = *c.Client
+	}
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	client.Transport = wrap(rt)
+	c.Client = client
+}
+
+// Close zeroes the credentials of the client, requests made afterwards are not authenticated.
+func (c *RESTClient) Close() {
+	if c.credentials != nil {
+		c.credentials.password.Zero()
+		c.credentials.bearerToken.Zero()
 	}
-	return NewRequest(c.Client, verb, c.base, c.headers, c.versionedAPIPath, c.contentConfig, c.Throttle, c.Client.Timeout)
 }
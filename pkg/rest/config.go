@@ -17,11 +17,13 @@ package rest
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	flowcontrol2 "github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -76,6 +78,9 @@ type Config struct {
 	Host string
 	// APIPath is a sub-path that points to an API root.
 	APIPath string
+	// PullHost is the hostname images are pulled with when it differs from the hostname
+	// of the API, e.g. an external endpoint in split-horizon DNS deployments.
+	PullHost string
 
 	// ContentConfig contains settings that affect how objects are transformed when
 	// sent to the server.
@@ -83,12 +88,13 @@ type Config struct {
 
 	// Server requires Basic authentication
 	Username string
-	Password string
+	Password *Secret
 
 	// Server requires Bearer authentication. This client will not attempt to use
-	// refresh tokens for an OAuth2 flow.
+	// refresh tokens for an OAuth2 flow. BearerToken takes precedence over Username
+	// and Password.
 	// TODO: demonstrate an OAuth2 compatible client.
-	BearerToken string
+	BearerToken *Secret
 
 	// Path to a file containing a BearerToken.
 	// If set, the contents are periodically read.
@@ -110,13 +116,66 @@ type Config struct {
 	// TLSClientConfig contains settings to enable transport layer security
 	TLSClientConfig
 
-	// UserAgent is an optional field that specifies the caller of this request.
+	// UserAgent is an optional field that specifies the caller of this request, e.g.
+	// my-operator/1.2.0, DefaultUserAgent is appended to it.
 	UserAgent string
 
+	// AuditHook, if set, receives a record of every mutating request.
+	AuditHook AuditHook
+
+	// CertificatePins enables trust on first use: the certificate chain of the server is
+	// not verified, instead the certificate received on the first connection is pinned in
+	// the store, under PinKey of the server name, and the connections presenting another
+	// certificate fail with a CertificatePinError. Safer than Insecure for servers with a
+	// self-signed certificate.
+	CertificatePins store.Store
+
+	// RetryPolicy, if set, retries the requests failing with a transient status code, e.g.
+	// a 503 of a load balancer while Harbor restarts.
+	RetryPolicy *RetryPolicy
+
+	// CircuitBreaker, if set, fails the requests fast for a cool-down period once the server
+	// failed a number of requests in a row, e.g. to protect batch jobs from hammering a down
+	// Harbor. Share it between the configs of a same server.
+	CircuitBreaker *CircuitBreaker
+
+	// Logger, if set, receives a structured record of every request, e.g. a *slog.Logger
+	// or a logr.Logger, see Logger.
+	Logger Logger
+
+	// Metrics, if set, receives the measurements of every request, e.g. to export them to
+	// Prometheus, see Metrics.
+	Metrics Metrics
+
+	// Debug, if set, receives a dump of every request, as an equivalent curl command, and
+	// of its response, with the secrets masked, see DumpTransport.
+	Debug io.Writer
+
+	// ETagCache makes the GET requests conditional with the ETag of their cached response,
+	// see ETagTransport. Use NewETagCache for an in-memory cache.
+	ETagCache ETagCache
+
+	// HedgeDelay sends a second attempt of the GET requests still waiting for a response
+	// after the delay, see HedgeTransport. Zero disables the hedging.
+	HedgeDelay time.Duration
+
+	// ReadOnly refuses the mutating requests, POST, PUT, PATCH and DELETE, before they are
+	// sent, they fail with an error matching ErrReadOnly.
+	ReadOnly bool
+
+	// DryRun captures the mutating requests in the recorder instead of sending them, see
+	// Recorder. It takes precedence over ReadOnly.
+	DryRun *Recorder
+
 	// DisableCompression bypasses automatic GZip compression requests to the
-	// server.
+	// server. Otherwise the responses are asked gzip compressed and decompressed
+	// transparently, even with a custom Transport.
 	DisableCompression bool
 
+	// DisableHTTP2 restricts the transport to HTTP/1.1, e.g. behind an ingress misbehaving
+	// with HTTP/2. Otherwise HTTP/2 is negotiated with the servers supporting it.
+	DisableHTTP2 bool
+
 	// Transport may be used for custom HTTP behavior. This attribute may not
 	// be specified with the TLS client certificate options. Use WrapTransport
 	// to provide additional per-server middleware behavior.
@@ -126,9 +185,8 @@ type Config struct {
 	// Transport, or http.DefaultTransport). The config may layer other RoundTrippers
 	// on top of the returned RoundTripper.
 	//
-	// A future release will change this field to an array. Use config.Wrap()
-	// instead of setting this value directly.
-	//	WrapTransport transport.WrapperFunc
+	// Use config.Wrap() instead of setting this value directly.
+	WrapTransport WrapperFunc
 
 	// QPS indicates the maximum QPS to the master from this client.
 	// If it's zero, the created RESTClient will use DefaultQPS: 5
@@ -142,8 +200,49 @@ type Config struct {
 	RateLimiter flowcontrol2.RateLimiter
 
 	// The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.
+	// It can be overridden for a single request with Request.Timeout.
 	Timeout time.Duration
 
+	// MaxIdleConns limits the idle connections kept open for reuse, DefaultMaxIdleConns if
+	// zero.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits the idle connections kept open for reuse to Harbor,
+	// DefaultMaxIdleConnsPerHost if zero. Raise it for highly concurrent consumers so that
+	// the connections aren't closed and reopened between bursts of requests.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout closes the connections idle for longer, DefaultIdleConnTimeout if
+	// zero.
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake of a new connection,
+	// DefaultTLSHandshakeTimeout if zero.
+	TLSHandshakeTimeout time.Duration
+
+	// APIVersionPath is the path of the API on the host, DefaultVersionApiPath if empty,
+	// LegacyVersionApiPath for Harbor 1.x. AutoAPIVersionPath detects it on first use, the
+	// clients created from the config sharing the detection.
+	APIVersionPath string
+	// apiPath is the detection of AutoAPIVersionPath shared by the clients of the config.
+	apiPath *apiPathDetector
+
+	// Session authenticates with a session of the UI of Harbor, logging in with /c/login,
+	// instead of sending the credentials with every request, for the APIs only reachable
+	// with a session. The clients created from the same config share the session, a copy
+	// of the config logs in with its own credentials.
+	Session bool
+	// session is the session shared by the clients of the config.
+	session *session
+
+	// RedirectPolicy decides how the redirects are followed, e.g. to presigned storage URLs.
+	// If nil, they are followed like http.Client does.
+	RedirectPolicy *RedirectPolicy
+
+	// Proxy returns the proxy of a request, see ProxyFor. If nil, the proxy of the
+	// environment is used, e.g. HTTPS_PROXY and NO_PROXY.
+	Proxy func(*http.Request) (*url.URL, error)
+
 	// Dial specifies the dial function for creating unencrypted TCP connections.
 	Dial func(ctx context.Context, network, address string) (net.Conn, error)
 
@@ -174,27 +273,111 @@ func RESTClientFor(config *Config) (*RESTClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	transport := TransportFor()
+	transport, err := TransportFor(config)
+	if err != nil {
+		return nil, err
+	}
 
 	var httpClient *http.Client
-	if transport != http.DefaultTransport {
-		httpClient = &http.Client{Transport: transport}
-		if config.Timeout > 0 {
-			httpClient.Timeout = config.Timeout
+	if transport != http.DefaultTransport || config.WrapTransport != nil || config.Debug != nil || config.ETagCache != nil || config.HedgeDelay > 0 || config.Session {
+		var rt http.RoundTripper = transport
+		if config.Debug != nil {
+			rt = DumpTransport(config.Debug)(rt)
+		}
+		if config.Session {
+			if config.session == nil || config.session.owner != config {
+				config.session = newSession(baseURL, config.Username, config.Password)
+				config.session.owner = config
+			}
+			rt = config.session.wrap(rt)
+		}
+		if config.HedgeDelay > 0 {
+			rt = HedgeTransport(config.HedgeDelay)(rt)
+		}
+		if config.ETagCache != nil {
+			rt = ETagTransport(config.ETagCache)(rt)
 		}
+		if config.WrapTransport != nil {
+			rt = config.WrapTransport(rt)
+		}
+		// the timeout is applied to the requests rather than to the http.Client, so that
+		// Request.Timeout can extend it
+		httpClient = &http.Client{Transport: rt}
+		if config.RedirectPolicy != nil {
+			httpClient.CheckRedirect = config.RedirectPolicy.checkRedirect
+		}
+	}
+	versionedAPIPath := config.APIVersionPath
+	if versionedAPIPath == "" || versionedAPIPath == AutoAPIVersionPath {
+		versionedAPIPath = DefaultVersionApiPath
+	}
+	client, err := NewRESTClient(baseURL, versionedAPIPath, config.ContentConfig, map[string]string{"User-Agent": userAgent(config)}, qps, burst, config.RateLimiter, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	client.credentials = &credentials{
+		username:    config.Username,
+		password:    config.Password,
+		bearerToken: config.BearerToken,
 	}
-	headers := map[string]string{}
-	if config.Username != "" && config.Password != "" {
-		pwd := fmt.Sprintf("%s:%s", config.Username, config.Password)
-		headers["authorization"] = fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(pwd)))
+	client.auditHook = config.AuditHook
+	client.readOnly = config.ReadOnly
+	client.retryPolicy = config.RetryPolicy
+	client.circuitBreaker = config.CircuitBreaker
+	client.logger = config.Logger
+	client.metrics = config.Metrics
+	client.disableCompression = config.DisableCompression
+	client.timeout = config.Timeout
+	client.recorder = config.DryRun
+	if config.APIVersionPath == AutoAPIVersionPath {
+		if config.apiPath == nil {
+			config.apiPath = &apiPathDetector{}
+		}
+		client.apiPath = config.apiPath
 	}
-	return NewRESTClient(baseURL, DefaultVersionApiPath, config.ContentConfig, headers, qps, burst, config.RateLimiter, httpClient)
+	return client, nil
 }
 
 func NewDefaultConfig(host string, username string, password string) *Config {
 	return &Config{
 		APIPath:  host,
 		Username: username,
-		Password: password,
+		Password: NewSecret(password),
+	}
+}
+
+// String implements fmt.Stringer, the credentials and the client key are redacted.
+func (c Config) String() string {
+	return c.GoString()
+}
+
+// GoString implements fmt.GoStringer, the credentials and the client key are redacted.
+func (c Config) GoString() string {
+	cc := c
+	cc.TLSClientConfig = *c.TLSClientConfig.DeepCopy()
+	if len(cc.KeyData) > 0 {
+		cc.KeyData = []byte(redacted)
+	}
+	return fmt.Sprintf("%#v", configWithoutMethods(cc))
+}
+
+// configWithoutMethods avoids the recursion of formatting a Config with %#v in GoString.
+type configWithoutMethods Config
+
+// Wrap adds a transport middleware function that will give the caller the opportunity
+// to wrap the underlying http.RoundTripper prior to the first API call being made, e.g.
+// to refresh credentials, log the traffic or inject failures. The provided function is
+// invoked after any existing transport wrappers are invoked.
+func (c *Config) Wrap(fn WrapperFunc) {
+	c.WrapTransport = Wrappers(c.WrapTransport, fn)
+}
+
+// Zero zeroes the password and the bearer token of the config, and logs the session
+// logged in with the password out of Harbor.
+func (c *Config) Zero() {
+	c.Password.Zero()
+	c.BearerToken.Zero()
+	if c.session != nil && c.session.password == c.Password {
+		c.session.close()
 	}
 }
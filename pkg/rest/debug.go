@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// sensitiveHeaders are masked in the dumps and the curl commands.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Harbor-Csrf-Token": true,
+}
+
+// sensitiveFields matches the JSON string fields holding a password, a secret or a token,
+// e.g. the secret of a robot account or the credentials of a registry.
+var sensitiveFields = regexp.MustCompile(`("(?i:[a-z_]*(?:password|secret|token)[a-z_]*)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// sensitiveFormFields matches the same fields in a form encoded body, e.g. the password
+// sent to /c/login by a UI session.
+var sensitiveFormFields = regexp.MustCompile(`((?:^|&)(?i:[a-z_]*(?:password|secret|token)[a-z_]*)=)[^&]*`)
+
+// DumpTransport returns a transport middleware writing every request, as an equivalent
+// curl command, and its response, headers and body, to out, e.g. to attach them to a
+// support ticket. The credentials, the cookies and the secret fields of the JSON and form
+// encoded bodies are masked.
+func DumpTransport(out io.Writer) WrapperFunc {
+	var mu sync.Mutex
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := readBody(&req.Body)
+			if err != nil {
+				return nil, err
+			}
+			var dump bytes.Buffer
+			dump.WriteString(curlCommand(req, body))
+			dump.WriteString("\n")
+
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				fmt.Fprintf(&dump, "< error: %v\n", err)
+			} else if body, err = readBody(&resp.Body); err != nil {
+				fmt.Fprintf(&dump, "< error reading the body: %v\n", err)
+			} else {
+				fmt.Fprintf(&dump, "< %s %s\n", resp.Proto, resp.Status)
+				for _, key := range sortedKeys(resp.Header) {
+					for _, value := range resp.Header[key] {
+						fmt.Fprintf(&dump, "< %s: %s\n", key, maskHeader(key, value))
+					}
+				}
+				dump.WriteString("<\n")
+				if len(body) > 0 {
+					dump.WriteString(maskBody(body))
+					dump.WriteString("\n")
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			out.Write(dump.Bytes())
+			return resp, err
+		})
+	}
+}
+
+// CurlCommand renders a curl command equivalent to req, with the credentials, the cookies
+// and the secret fields of the JSON body masked. The body of req is read and replaced.
+func CurlCommand(req *http.Request) (string, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return "", err
+	}
+	return curlCommand(req, body), nil
+}
+
+// curlCommand renders a curl command equivalent to req sending body.
+func curlCommand(req *http.Request, body []byte) string {
+	var command strings.Builder
+	command.WriteString("curl -X " + req.Method)
+	for _, key := range sortedKeys(req.Header) {
+		for _, value := range req.Header[key] {
+			command.WriteString(" -H " + shellQuote(key+": "+maskHeader(key, value)))
+		}
+	}
+	switch {
+	case len(body) == 0:
+	case isPrintable(body):
+		command.WriteString(" --data-raw " + shellQuote(maskBody(body)))
+	default:
+		// binary content, e.g. a chart archive, is read from the standard input
+		command.WriteString(" --data-binary @-")
+	}
+	command.WriteString(" " + shellQuote(req.URL.String()))
+	return command.String()
+}
+
+// readBody reads the body and replaces it with a reader of its content.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	content, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(content))
+	return content, nil
+}
+
+// maskHeader masks the value of the sensitive headers.
+func maskHeader(key, value string) string {
+	if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+		return redacted
+	}
+	return value
+}
+
+// maskBody masks the secret fields of a JSON or form encoded body, a binary body is
+// summarized.
+func maskBody(body []byte) string {
+	if !isPrintable(body) {
+		return fmt.Sprintf("[%d bytes of binary data]", len(body))
+	}
+	masked := sensitiveFields.ReplaceAllString(string(body), `${1}"`+redacted+`"`)
+	return sensitiveFormFields.ReplaceAllString(masked, "${1}"+redacted)
+}
+
+// isPrintable uses the same heuristic as glogBody to tell text from binary content.
+func isPrintable(body []byte) bool {
+	return bytes.IndexFunc(body, func(r rune) bool {
+		return r < 0x0a
+	}) == -1
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedKeys(header http.Header) []string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
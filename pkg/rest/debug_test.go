@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDumpTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body := new(bytes.Buffer)
+		body.ReadFrom(req.Body)
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "session"})
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name":"robot$ci","secret":"s3cr3t"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Debug = &out
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var robot map[string]string
+	err = client.Post().Resource("robots").Body([]byte(`{"name":"ci","password": "it's"}`)).Do(context.Background()).Into(&robot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if robot["secret"] != "s3cr3t" {
+		t.Errorf("the response should not be altered, got %v", robot)
+	}
+
+	dump := out.String()
+	for _, expected := range []string{
+		"curl -X POST ",
+		"-H 'Authorization: [REDACTED]'",
+		`--data-raw '{"name":"ci","password": "[REDACTED]"}'`,
+		"'" + server.URL + "/api/v2.0/robots'",
+		"< HTTP/1.1 201 Created",
+		"< Set-Cookie: [REDACTED]",
+		`{"name":"robot$ci","secret":"[REDACTED]"}`,
+	} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("expected the dump to contain %s, got\n%s", expected, dump)
+		}
+	}
+	for _, secret := range []string{"Harbor12345", "YWRtaW46SGFyYm9yMTIzNDU=", "s3cr3t", "session"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("expected %s to be masked, got\n%s", secret, dump)
+		}
+	}
+}
+
+func TestCurlCommand(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "https://harbor.example.com/api/v2.0/projects/a", strings.NewReader(`{"public":"it's"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	command, err := CurlCommand(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `curl -X PUT -H 'Content-Type: application/json' --data-raw '{"public":"it'\''s"}' 'https://harbor.example.com/api/v2.0/projects/a'`
+	if command != expected {
+		t.Errorf("expected %s, got %s", expected, command)
+	}
+	body := new(bytes.Buffer)
+	body.ReadFrom(req.Body)
+	if body.String() != `{"public":"it's"}` {
+		t.Errorf("the body should be replaced, got %q", body.String())
+	}
+}
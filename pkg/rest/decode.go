@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// listKeys are the fields an object wrapping a list is looked up in, in order, and
+// totalKeys the fields holding the total number of items.
+var (
+	listKeys  = []string{"items", "data", "results", "list"}
+	totalKeys = []string{"total", "total_count", "count"}
+)
+
+// IntoList stores a list response into obj, a pointer to a slice. Several Harbor endpoints
+// changed between versions from returning a bare array to an object wrapping it, e.g.
+// {"total": 2, "items": [...]}, both shapes are accepted so that one client works against
+// all of them. The array of an object is taken from its items, data, results or list field,
+// or from its only array field.
+func (r Result) IntoList(obj interface{}) error {
+	if r.err != nil {
+		return r.Error()
+	}
+	data, err := unwrapList(r.body)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+	if r.decoder == nil {
+		return json.Unmarshal(data, obj)
+	}
+	return r.decoder.Unmarshal(data, obj)
+}
+
+// unwrapList returns the array of a list response, nil if the response is empty or null.
+func unwrapList(body []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	switch trimmed[0] {
+	case '[':
+		return trimmed, nil
+	case '{':
+	default:
+		return nil, fmt.Errorf("expected a list response, got %.20q", trimmed)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, err
+	}
+	for _, key := range listKeys {
+		if value, ok := fields[key]; ok {
+			return value, nil
+		}
+	}
+	var array []byte
+	for key, value := range fields {
+		value = bytes.TrimSpace(value)
+		if len(value) == 0 || value[0] != '[' {
+			continue
+		}
+		if array != nil {
+			return nil, fmt.Errorf("ambiguous list response, several array fields including %q", key)
+		}
+		array = value
+	}
+	if array == nil {
+		return nil, fmt.Errorf("expected a list response, got an object without array field")
+	}
+	return array, nil
+}
+
+// wrappedTotal returns the total number of items of a list wrapped in an object, and false
+// if the body is not such an object.
+func wrappedTotal(body []byte) (int64, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return 0, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return 0, false
+	}
+	for _, key := range totalKeys {
+		if value, ok := fields[key]; ok {
+			if total, err := strconv.ParseInt(string(bytes.TrimSpace(value)), 10, 64); err == nil {
+				return total, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type listItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestResultIntoList(t *testing.T) {
+	expected := []listItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	testCases := map[string]string{
+		"bare array":       `[{"id":1,"name":"a"},{"id":2,"name":"b"}]`,
+		"items":            `{"total":2,"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`,
+		"data":             `{"data":[{"id":1,"name":"a"},{"id":2,"name":"b"}],"count":2}`,
+		"only array field": ` {"page":1,"logs":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`,
+	}
+	for name, body := range testCases {
+		var items []listItem
+		if err := (Result{body: []byte(body)}).IntoList(&items); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(items, expected) {
+			t.Errorf("%s: unexpected items: %#v", name, items)
+		}
+	}
+
+	for _, body := range []string{"", "null", `{"items":null}`} {
+		var items []listItem
+		if err := (Result{body: []byte(body)}).IntoList(&items); err != nil || len(items) != 0 {
+			t.Errorf("%q: expected an empty list, got %#v %v", body, items, err)
+		}
+	}
+	for _, body := range []string{`{"id":1}`, `{"a":[],"b":[]}`, `"text"`} {
+		var items []listItem
+		if err := (Result{body: []byte(body)}).IntoList(&items); err == nil {
+			t.Errorf("%q: expected an error", body)
+		}
+	}
+}
+
+func TestResultTotalCountWrapped(t *testing.T) {
+	r := Result{body: []byte(`{"total":42,"items":[]}`)}
+	if total, ok := r.TotalCount(); !ok || total != 42 {
+		t.Errorf("unexpected total count: %d %v", total, ok)
+	}
+	r.header = http.Header{"X-Total-Count": []string{"7"}}
+	if total, ok := r.TotalCount(); !ok || total != 7 {
+		t.Errorf("expected the header to take precedence, got %d %v", total, ok)
+	}
+	if _, ok := (Result{body: []byte(`[]`)}).TotalCount(); ok {
+		t.Errorf("expected no total count for a bare array")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// RecordedRequest is a mutating request captured by a Recorder instead of being sent.
+type RecordedRequest struct {
+	Method string
+	// URL is the URL of the request, with its password redacted.
+	URL string
+	// Header holds the headers of the request, without its credentials.
+	Header http.Header
+	Body   []byte
+}
+
+// String formats the request for a plan, e.g. POST https://harbor/api/v2.0/projects
+// {"project_name":"library"}, the secret fields of the body being masked.
+func (r RecordedRequest) String() string {
+	if len(r.Body) == 0 {
+		return fmt.Sprintf("%s %s", r.Method, r.URL)
+	}
+	return fmt.Sprintf("%s %s %s", r.Method, r.URL, maskBody(r.Body))
+}
+
+// Recorder captures the mutating requests, POST, PUT, PATCH and DELETE, of a client in dry
+// run mode instead of sending them, e.g. for a declarative tool to show its plan before
+// applying it. The other requests are sent, so that the current state can be read. A
+// captured request succeeds with an empty JSON object.
+type Recorder struct {
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Requests returns the requests captured so far, in order.
+func (r *Recorder) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// Reset forgets the captured requests.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+func (r *Recorder) record(request RecordedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, request)
+}
+
+// record captures the request with the recorder instead of sending it, fn is called with
+// an empty successful response.
+func (r *Request) record(fn func(*http.Request, *http.Response)) error {
+	if r.err != nil {
+		return r.err
+	}
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.body); err != nil {
+			return err
+		}
+		if seeker, ok := r.body.(io.Seeker); ok {
+			seeker.Seek(0, io.SeekStart)
+		}
+	}
+	u := r.URL()
+	r.recorder.record(RecordedRequest{
+		Method: r.verb,
+		URL:    u.Redacted(),
+		Header: r.headers.Clone(),
+		Body:   body,
+	})
+	req, err := http.NewRequest(r.verb, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	fn(req, &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte("{}"))),
+		Request:    req,
+	})
+	return nil
+}
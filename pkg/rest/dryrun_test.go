@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sent = append(sent, req.Method)
+		w.Write([]byte(`[{"name":"library"}]`))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder()
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.DryRun = recorder
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var projects []map[string]string
+	if err := client.Get().Resource("projects").Do(ctx).Into(&projects); err != nil || len(projects) != 1 {
+		t.Fatalf("expected the read to be sent, got %v %v", projects, err)
+	}
+	var robot map[string]interface{}
+	err = client.Post().Resource("robots").Body(map[string]string{"name": "ci", "secret": "s3cr3t"}).Do(ctx).Into(&robot)
+	if err != nil {
+		t.Errorf("unexpected error of a recorded request: %v", err)
+	}
+	if err := client.Delete().Resource("projects").Name("library").Do(ctx).Error(); err != nil {
+		t.Errorf("unexpected error of a recorded request: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != http.MethodGet {
+		t.Errorf("expected only the read to be sent, got %v", sent)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 recorded requests, got %v", requests)
+	}
+	if plan := requests[0].String(); !strings.HasPrefix(plan, "POST "+server.URL+"/api/v2.0/robots {") ||
+		strings.Contains(plan, "s3cr3t") || !strings.Contains(plan, `"name":"ci"`) {
+		t.Errorf("unexpected plan %s", plan)
+	}
+	if requests[0].Header.Get("Authorization") != "" {
+		t.Error("the credentials must not be recorded")
+	}
+	if plan := requests[1].String(); plan != "DELETE "+server.URL+"/api/v2.0/projects/library" {
+		t.Errorf("unexpected plan %s", plan)
+	}
+	recorder.Reset()
+	if len(recorder.Requests()) != 0 {
+		t.Error("expected no request after a reset")
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxErrorMessage is the length of a response body, which isn't a Harbor error payload,
+// kept as the message of an APIError.
+const maxErrorMessage = 512
+
+// ErrorDetail is an error of the errors payload of Harbor, {"errors":[{"code","message"}]}.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is the error of a request the server answered with an unsuccessful status code,
+// e.g. to tell a missing resource from a permission problem without matching the error
+// message:
+//
+//	var apiErr *rest.APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+//		...
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Code and Message are the ones of the first error of the payload, e.g. NOT_FOUND.
+	// Message is the beginning of the body if it isn't a Harbor error payload.
+	Code    string
+	Message string
+	// Errors are all the errors of the payload.
+	Errors []ErrorDetail
+	// Method and URL are the ones of the request, the URL has its password redacted.
+	Method string
+	URL    string
+	// RequestID is the X-Request-Id of the response, to correlate the error with the logs
+	// of Harbor.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// newAPIError parses the error payload of an unsuccessful response.
+func newAPIError(body []byte, statusCode int, req *http.Request) *APIError {
+	err := &APIError{
+		StatusCode: statusCode,
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+	}
+	var payload struct {
+		Errors []ErrorDetail `json:"errors"`
+	}
+	if json.Unmarshal(body, &payload) == nil && len(payload.Errors) > 0 {
+		err.Errors = payload.Errors
+		err.Code = payload.Errors[0].Code
+		err.Message = payload.Errors[0].Message
+		return err
+	}
+	if message := strings.TrimSpace(string(body)); message != "" && isPrintable(body) {
+		if len(message) > maxErrorMessage {
+			message = message[:maxErrorMessage] + "..."
+		}
+		err.Message = message
+	}
+	return err
+}
+
+// ErrNotFound is wrapped by the errors of the lookups which find no matching resource in
+// a list, e.g. of a registry by name, IsNotFound is true for them as for a 404 response.
+var ErrNotFound = errors.New("not found")
+
+// StatusCode returns the HTTP status code of err if it is, or wraps, an APIError, and 0
+// otherwise.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound returns true if err is an APIError of a 404 response, e.g. of a missing
+// project or repository, or wraps ErrNotFound.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound || errors.Is(err, ErrNotFound)
+}
+
+// IsConflict returns true if err is an APIError of a 409 response, e.g. of a resource
+// which already exists.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsUnauthorized returns true if err is an APIError of a 401 response, the credentials
+// are missing or invalid.
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden returns true if err is an APIError of a 403 response, the user lacks the
+// permission.
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsPreconditionFailed returns true if err is an APIError of a 412 response, e.g. of an
+// artifact which can't be deleted because it is signed.
+func IsPreconditionFailed(err error) bool {
+	return StatusCode(err) == http.StatusPreconditionFailed
+}
+
+// IsTooManyRequests returns true if err is an APIError of a 429 response, the server is
+// rate limiting the client.
+func IsTooManyRequests(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v2.0/projects/missing":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"code":"NOT_FOUND","message":"project missing not found"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>bad gateway</html>\n"))
+		}
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		err      error
+		expected *APIError
+	}{
+		{
+			name: "Do",
+			err:  client.Get().Resource("projects").Name("missing").Do(context.Background()).Into(&struct{}{}),
+			expected: &APIError{
+				StatusCode: http.StatusNotFound,
+				Code:       "NOT_FOUND",
+				Message:    "project missing not found",
+				Errors:     []ErrorDetail{{Code: "NOT_FOUND", Message: "project missing not found"}},
+				Method:     http.MethodGet,
+				URL:        server.URL + "/api/v2.0/projects/missing",
+			},
+		},
+		{
+			name: "DoRaw",
+			err: func() error {
+				_, _, err := client.Delete().Resource("projects").Name("down").DoRaw(context.Background())
+				return err
+			}(),
+			expected: &APIError{
+				StatusCode: http.StatusBadGateway,
+				Message:    "<html>bad gateway</html>",
+				Method:     http.MethodDelete,
+				URL:        server.URL + "/api/v2.0/projects/down",
+			},
+		},
+	} {
+		var apiErr *APIError
+		if !errors.As(test.err, &apiErr) {
+			t.Errorf("%s: expected an APIError, got %T %v", test.name, test.err, test.err)
+			continue
+		}
+		if !reflect.DeepEqual(apiErr, test.expected) {
+			t.Errorf("%s: expected %#v, got %#v", test.name, test.expected, apiErr)
+		}
+	}
+
+	err = client.Get().Resource("projects").Name("missing").Do(context.Background()).Error()
+	if msg := err.Error(); !strings.Contains(msg, "404 Not Found: NOT_FOUND: project missing not found") {
+		t.Errorf("unexpected message %s", msg)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	predicates := map[int]func(error) bool{
+		http.StatusNotFound:           IsNotFound,
+		http.StatusConflict:           IsConflict,
+		http.StatusUnauthorized:       IsUnauthorized,
+		http.StatusForbidden:          IsForbidden,
+		http.StatusPreconditionFailed: IsPreconditionFailed,
+		http.StatusTooManyRequests:    IsTooManyRequests,
+	}
+	for statusCode := range predicates {
+		err := fmt.Errorf("get project error: %w", &APIError{StatusCode: statusCode})
+		for other, predicate := range predicates {
+			if predicate(err) != (other == statusCode) {
+				t.Errorf("unexpected predicate of %d for a %d error", other, statusCode)
+			}
+		}
+	}
+	if IsNotFound(errors.New("not found")) || IsNotFound(nil) || StatusCode(nil) != 0 {
+		t.Error("only an APIError has a status code")
+	}
+	if err := fmt.Errorf("registry %s %w", "hub", ErrNotFound); !IsNotFound(err) || err.Error() != "registry hub not found" {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// CachedResponse is a response kept by an ETagCache, to be replayed when the server
+// answers 304 Not Modified to the conditional request.
+type CachedResponse struct {
+	// Path is the URL path of the request, used to invalidate the response after writes.
+	Path       string
+	ETag       string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// ETagCache stores the last response carrying an ETag of the GET requests, keyed by an
+// opaque string identifying the URL and the credentials of the request. It must be safe
+// for concurrent use.
+type ETagCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+	// DeleteFunc removes the responses for which match returns true.
+	DeleteFunc(match func(response *CachedResponse) bool)
+}
+
+// NewETagCache returns an in-memory ETagCache keeping the maxEntries most recently used
+// responses.
+func NewETagCache(maxEntries int) ETagCache {
+	return &lruETagCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+type lruETagCache struct {
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+func (c *lruETagCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).response, true
+}
+
+func (c *lruETagCache) Set(key string, response *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).response = response
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, response: response})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruETagCache) DeleteFunc(match func(response *CachedResponse) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, element := range c.entries {
+		if match(element.Value.(*lruEntry).response) {
+			c.order.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// ETagTransport returns a middleware making the GET requests conditional with the ETag of
+// the response cached for their URL, e.g. for polling loops on the configurations or the
+// projects. A 304 Not Modified response is replaced with the cached response, so that the
+// payload is only transferred when it changed. The requests setting If-None-Match or Range
+// themselves are left untouched.
+//
+// Every other request, whether it succeeds or not, invalidates the cached responses of its
+// URL, of the URLs below it and of its parent collection, e.g. a PUT on /projects/1 drops
+// the responses of /projects/1, /projects/1/members and /projects?page=2, so that a
+// client reads back its own writes. A resource addressed by another URL, e.g. by name
+// instead of ID, is only refreshed once the server changes its ETag.
+func ETagTransport(cache ETagCache) WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.Method {
+			case http.MethodGet:
+			case http.MethodHead, http.MethodOptions:
+				return rt.RoundTrip(req)
+			default:
+				resp, err := rt.RoundTrip(req)
+				cache.DeleteFunc(func(cached *CachedResponse) bool {
+					return invalidatedBy(cached.Path, req.URL.Path)
+				})
+				return resp, err
+			}
+			if req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+				return rt.RoundTrip(req)
+			}
+			key := etagCacheKey(req)
+			cached, ok := cache.Get(key)
+			if ok {
+				req = req.Clone(req.Context())
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				return resp, err
+			}
+			switch {
+			case resp.StatusCode == http.StatusNotModified && ok:
+				resp.Body.Close()
+				return cached.response(req), nil
+			case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+				body, err := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				cache.Set(key, &CachedResponse{
+					Path:       req.URL.Path,
+					ETag:       resp.Header.Get("ETag"),
+					StatusCode: resp.StatusCode,
+					Header:     resp.Header.Clone(),
+					Body:       body,
+				})
+				resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// response rebuilds the cached response as the one of req.
+func (c *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(c.StatusCode),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// invalidatedBy returns true if the response cached for cached is invalidated by a write
+// on written, i.e. if cached is written, is below it or is its parent collection.
+func invalidatedBy(cached, written string) bool {
+	written = strings.TrimSuffix(written, "/")
+	cached = strings.TrimSuffix(cached, "/")
+	return cached == written || strings.HasPrefix(cached, written+"/") || cached == path.Dir(written)
+}
+
+// etagCacheKey identifies the response of req by its URL and the headers changing it, the
+// credentials being hashed so that the cache doesn't hold them.
+func etagCacheKey(req *http.Request) string {
+	h := sha256.New()
+	for _, header := range []string{"Authorization", "Accept", "Accept-Encoding"} {
+		h.Write([]byte(req.Header.Get(header)))
+		h.Write([]byte{0})
+	}
+	return req.URL.String() + " " + hex.EncodeToString(h.Sum(nil))
+}
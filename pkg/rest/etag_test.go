@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestETagTransport(t *testing.T) {
+	var requests, notModified int
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("ETag", etag)
+		if req.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"etag":` + etag + `}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.ETagCache = NewETagCache(10)
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func() string {
+		var result map[string]string
+		if err := client.Get().Resource("configurations").Do(context.Background()).Into(&result); err != nil {
+			t.Fatal(err)
+		}
+		return result["etag"]
+	}
+	if got := get(); got != "v1" || notModified != 0 {
+		t.Errorf("unexpected first response %q, %d not modified", got, notModified)
+	}
+	if got := get(); got != "v1" || notModified != 1 {
+		t.Errorf("expected the cached response, got %q, %d not modified", got, notModified)
+	}
+	etag = `"v2"`
+	if got := get(); got != "v2" || notModified != 1 || requests != 3 {
+		t.Errorf("expected the new response, got %q, %d not modified in %d requests", got, notModified, requests)
+	}
+}
+
+func TestETagCacheEviction(t *testing.T) {
+	cache := NewETagCache(2)
+	cache.Set("a", &CachedResponse{ETag: "a"})
+	cache.Set("b", &CachedResponse{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", &CachedResponse{ETag: "c"})
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if response, ok := cache.Get(key); !ok || response.ETag != key {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+}
+
+// versionedProjects serves projects whose ETag is their version, bumped by every PUT.
+type versionedProjects struct {
+	mu          sync.Mutex
+	versions    map[string]int
+	gets        int
+	notModified int
+}
+
+func (v *versionedProjects) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	name := strings.TrimPrefix(req.URL.Path, "/api/v2.0/projects")
+	if req.Method == http.MethodPut {
+		v.versions[name]++
+		return
+	}
+	v.gets++
+	etag := fmt.Sprintf(`"%d"`, v.versions[name])
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		v.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"version":%d}`, v.versions[name])
+}
+
+func (v *versionedProjects) counts() (int, int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.gets, v.notModified
+}
+
+func TestETagTransportInvalidatesAfterWrites(t *testing.T) {
+	server := &versionedProjects{versions: map[string]int{}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	config := NewDefaultConfig(httpServer.URL, "admin", "Harbor12345")
+	cache := NewETagCache(10)
+	config.ETagCache = cache
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(resource ...string) int {
+		var result map[string]int
+		request := client.Get().Resource("projects")
+		if len(resource) > 0 {
+			request = request.Name(resource[0])
+		}
+		if err := request.Do(context.Background()).Into(&result); err != nil {
+			t.Fatal(err)
+		}
+		return result["version"]
+	}
+	get()
+	get("library")
+	get("other")
+	if get("library") != 0 {
+		t.Fatal("expected the cached project")
+	}
+	if gets, notModified := server.counts(); gets != 4 || notModified != 1 {
+		t.Fatalf("expected 1 of 4 GET requests to be answered with 304, got %d of %d", notModified, gets)
+	}
+
+	if err := client.Put().Resource("projects").Name("library").Body([]byte(`{}`)).Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/api/v2.0/projects", "/api/v2.0/projects/library"} {
+		cache.DeleteFunc(func(cached *CachedResponse) bool {
+			if cached.Path == path {
+				t.Errorf("expected the response of %s to be invalidated by the write", path)
+			}
+			return false
+		})
+	}
+	// the sibling project keeps its cached response
+	if get("other") != 0 {
+		t.Error("expected the cached response of the sibling project")
+	}
+	// the written project is read back without If-None-Match
+	if version := get("library"); version != 1 {
+		t.Errorf("expected the updated project, got version %d", version)
+	}
+	if gets, notModified := server.counts(); gets != 6 || notModified != 2 {
+		t.Errorf("expected only the sibling project to be answered with 304, got %d of %d", notModified, gets)
+	}
+}
+
+func TestETagTransportLeavesConditionalRequests(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"v1"`)
+		if header == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewETagCache(10)
+	rt := ETagTransport(cache)(http.DefaultTransport)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v2.0/systeminfo", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified || header != `"v1"` {
+		t.Errorf("expected the 304 response to the caller's own condition, got %d for %q", resp.StatusCode, header)
+	}
+	if _, ok := cache.Get(etagCacheKey(req)); ok {
+		t.Error("expected the response of a conditional request not to be cached")
+	}
+}
+
+func TestInvalidatedBy(t *testing.T) {
+	tests := []struct {
+		cached, written string
+		want            bool
+	}{
+		{"/api/v2.0/projects/1", "/api/v2.0/projects/1", true},
+		{"/api/v2.0/projects/1/members", "/api/v2.0/projects/1", true},
+		{"/api/v2.0/projects", "/api/v2.0/projects/1", true},
+		{"/api/v2.0/projects/2", "/api/v2.0/projects/1", false},
+		{"/api/v2.0/projects/10", "/api/v2.0/projects/1", false},
+		{"/api/v2.0/projects", "/api/v2.0/projects/1/members/3", false},
+	}
+	for _, test := range tests {
+		if got := invalidatedBy(test.cached, test.written); got != test.want {
+			t.Errorf("expected a write on %s to invalidate %s: %v, got %v", test.written, test.cached, test.want, got)
+		}
+	}
+}
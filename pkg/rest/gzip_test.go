@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGzip(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		acceptEncoding = req.Header.Get("Accept-Encoding")
+		if req.Method == http.MethodHead {
+			w.Header().Set("Content-Encoding", "gzip")
+			return
+		}
+		if acceptEncoding != "gzip" {
+			w.Write([]byte(`{"name":"library"}`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		writer.Write([]byte(`{"name":"library"}`))
+		writer.Close()
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		name               string
+		disableCompression bool
+		transport          http.RoundTripper
+		acceptEncoding     string
+	}{
+		{"default", false, nil, "gzip"},
+		{"custom transport", false, &http.Transport{DisableCompression: true}, "gzip"},
+		{"compression disabled", true, nil, ""},
+	} {
+		config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+		config.DisableCompression = test.disableCompression
+		client, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.transport != nil {
+			client.Client = &http.Client{Transport: test.transport}
+		}
+		var project map[string]string
+		if err := client.Get().Resource("projects").Name("library").Do(context.Background()).Into(&project); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if project["name"] != "library" {
+			t.Errorf("%s: unexpected response %v", test.name, project)
+		}
+		if acceptEncoding != test.acceptEncoding {
+			t.Errorf("%s: expected the Accept-Encoding %q, got %q", test.name, test.acceptEncoding, acceptEncoding)
+		}
+		if err := client.Verb(http.MethodHead).Resource("projects").Name("library").Do(context.Background()).Error(); err != nil {
+			t.Errorf("%s: an empty compressed response should not fail, got %v", test.name, err)
+		}
+	}
+}
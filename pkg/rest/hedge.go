@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HedgeTransport returns a middleware sending a second attempt of the GET and HEAD
+// requests still waiting for a response after delay, e.g. to cut the tail latency when a
+// replica of Harbor behind a load balancer is slow. The first response wins and the other
+// attempt is canceled, the error of an attempt is only returned if no other attempt is in
+// flight.
+func HedgeTransport(delay time.Duration) WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet && req.Method != http.MethodHead || req.Body != nil && req.Body != http.NoBody {
+				return rt.RoundTrip(req)
+			}
+			return hedge(rt, req, delay)
+		})
+	}
+}
+
+type hedgeAttempt struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// hedge sends req, and a second attempt of it if no response is received after delay.
+func hedge(rt http.RoundTripper, req *http.Request, delay time.Duration) (*http.Response, error) {
+	attempts := make(chan hedgeAttempt, 2)
+	var cancels []context.CancelFunc
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := rt.RoundTrip(req.Clone(ctx))
+			attempts <- hedgeAttempt{index: index, resp: resp, err: err}
+		}()
+	}
+	send()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending := 1
+	for {
+		select {
+		case <-timer.C:
+			pending++
+			send()
+		case attempt := <-attempts:
+			pending--
+			if attempt.err != nil {
+				cancels[attempt.index]()
+				if pending > 0 {
+					continue
+				}
+				return nil, attempt.err
+			}
+			for i, cancel := range cancels {
+				if i != attempt.index {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go discardAttempts(attempts, pending)
+			}
+			// the context of the winning attempt lives as long as its body
+			attempt.resp.Body = &hedgeBody{ReadCloser: attempt.resp.Body, cancel: cancels[attempt.index]}
+			return attempt.resp, nil
+		}
+	}
+}
+
+// discardAttempts closes the responses of the canceled attempts.
+func discardAttempts(attempts <-chan hedgeAttempt, pending int) {
+	for ; pending > 0; pending-- {
+		if attempt := <-attempts; attempt.err == nil {
+			attempt.resp.Body.Close()
+		}
+	}
+}
+
+// hedgeBody cancels the context of the attempt it is the response body of when closed.
+type hedgeBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *hedgeBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
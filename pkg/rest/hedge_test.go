@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgeTransport(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the first attempt hangs until it is canceled
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-req.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"name":"library"}`))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.HedgeDelay = 20 * time.Millisecond
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var project map[string]string
+	if err := client.Get().Resource("projects").Name("library").Do(context.Background()).Into(&project); err != nil {
+		t.Fatal(err)
+	}
+	if project["name"] != "library" || atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected the hedged attempt to win, got %v after %d attempts", project, attempts)
+	}
+
+	// the mutating requests aren't hedged
+	atomic.StoreInt32(&attempts, 1)
+	if err := client.Delete().Resource("projects").Name("library").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("expected a single attempt of the DELETE request, got %d", n-1)
+	}
+}
+
+func TestHedgeTransportFastResponse(t *testing.T) {
+	var attempts int32
+	rt := HedgeTransport(time.Hour)(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&attempts, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+	req, _ := http.NewRequest(http.MethodGet, "http://harbor.example.com/api/v2.0/ping", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
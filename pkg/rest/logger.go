@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import "time"
+
+// Logger receives a structured record of every request sent to the server, it is satisfied
+// by *slog.Logger and logr.Logger. The record holds the method, the path, the status code,
+// the duration and the number of retries of the request, and the error if it failed; the
+// credentials, the query and the body are never logged.
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+}
+
+// LoggerFunc adapts a function to a Logger.
+type LoggerFunc func(msg string, keysAndValues ...interface{})
+
+// Info calls f(msg, keysAndValues...).
+func (f LoggerFunc) Info(msg string, keysAndValues ...interface{}) {
+	f(msg, keysAndValues...)
+}
+
+// logRequest logs the outcome of the request with the logger of the request.
+func (r *Request) logRequest(status int, duration time.Duration, err error) {
+	keysAndValues := []interface{}{
+		"method", r.verb,
+		"path", r.URL().Path,
+		"status", status,
+		"duration", duration,
+		"retries", r.retries,
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err.Error())
+	}
+	r.logger.Info("harbor request", keysAndValues...)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		switch {
+		case req.URL.Path == "/api/v2.0/slow":
+			time.Sleep(100 * time.Millisecond)
+			return
+		case req.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case attempts == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	var records []map[string]interface{}
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	config.Logger = LoggerFunc(func(msg string, keysAndValues ...interface{}) {
+		if len(keysAndValues)%2 != 0 {
+			t.Fatalf("odd number of keys and values: %v", keysAndValues)
+		}
+		record := map[string]interface{}{}
+		for i := 0; i < len(keysAndValues); i += 2 {
+			record[keysAndValues[i].(string)] = keysAndValues[i+1]
+		}
+		records = append(records, record)
+	})
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Get().Resource("projects").Param("q", "name=a").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected a record per request, got %v", records)
+	}
+	record := records[0]
+	if record["method"] != http.MethodGet || record["path"] != "/api/v2.0/projects" || record["status"] != http.StatusOK || record["retries"] != 1 {
+		t.Errorf("unexpected record %v", record)
+	}
+	if _, ok := record["duration"].(time.Duration); !ok {
+		t.Errorf("expected the duration of the request, got %v", record["duration"])
+	}
+	if s := fmt.Sprint(record); strings.Contains(s, "Harbor12345") || strings.Contains(s, "name=a") {
+		t.Errorf("the credentials and the query should not be logged, got %s", s)
+	}
+
+	if _, _, err := client.Delete().Resource("projects").Name("a").DoRaw(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(records) != 2 || records[1]["method"] != http.MethodDelete || records[1]["status"] != http.StatusNotFound {
+		t.Errorf("expected the failed request to be logged, got %v", records)
+	}
+
+	if err := client.Get().Resource("slow").Timeout(10 * time.Millisecond).Do(context.Background()).Error(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(records) != 3 || records[2]["status"] != 0 || records[2]["error"] == nil {
+		t.Errorf("expected the error to be logged, got %v", records)
+	}
+}
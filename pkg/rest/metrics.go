@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"net/http"
+	"time"
+)
+
+// Metrics receives the measurements of the requests of a client, e.g. to export them to a
+// monitoring system. The routes are the route templates of the requests, see Request.Route.
+// The methods are called synchronously so they should not block.
+type Metrics interface {
+	// ObserveRequest is called once per request with the status code of its final response,
+	// 0 if it failed without a response, and its duration, retries included.
+	ObserveRequest(verb, route string, statusCode int, duration time.Duration)
+	// ObserveThrottle is called with the time a request waited for the rate limiter.
+	ObserveThrottle(verb, route string, wait time.Duration)
+	// ObserveRetry is called before every retry of a request.
+	ObserveRetry(verb, route string)
+}
+
+// observeRequest sends the request, then logs it and records its measurements.
+func (r *Request) observeRequest(fn func(*http.Request, *http.Response)) error {
+	if (r.logger == nil && r.metrics == nil) || r.err != nil {
+		return r.auditRequest(fn)
+	}
+	start := time.Now()
+	status := 0
+	err := r.auditRequest(func(req *http.Request, resp *http.Response) {
+		status = resp.StatusCode
+		fn(req, resp)
+	})
+	duration := time.Since(start)
+	if r.logger != nil {
+		r.logRequest(status, duration, err)
+	}
+	if r.metrics != nil {
+		r.metrics.ObserveRequest(r.verb, r.Route(), status, duration)
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeMetrics records the calls as strings.
+type fakeMetrics struct {
+	calls []string
+}
+
+func (m *fakeMetrics) ObserveRequest(verb, route string, statusCode int, duration time.Duration) {
+	m.calls = append(m.calls, fmt.Sprintf("request %s %s %d", verb, route, statusCode))
+}
+
+func (m *fakeMetrics) ObserveThrottle(verb, route string, wait time.Duration) {
+	m.calls = append(m.calls, fmt.Sprintf("throttle %s %s", verb, route))
+}
+
+func (m *fakeMetrics) ObserveRetry(verb, route string) {
+	m.calls = append(m.calls, fmt.Sprintf("retry %s %s", verb, route))
+}
+
+func TestMetrics(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	metrics := &fakeMetrics{}
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	config.Metrics = metrics
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Name("library").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"throttle GET /api/v2.0/projects/{name}",
+		"retry GET /api/v2.0/projects/{name}",
+		"throttle GET /api/v2.0/projects/{name}",
+		"request GET /api/v2.0/projects/{name} 200",
+	}
+	if !reflect.DeepEqual(metrics.calls, expected) {
+		t.Errorf("expected %v, got %v", expected, metrics.calls)
+	}
+}
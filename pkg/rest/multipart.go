@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// MultipartBody sets the body of the request to a multipart/form-data form of the fields
+// and the files, both keyed by form field, the files given by path, e.g. to upload a chart
+// archive and its provenance file. The form is buffered so that the request can be retried,
+// see Idempotent.
+func (r *Request) MultipartBody(fields map[string]string, files map[string]string) *Request {
+	if r.err != nil {
+		return r
+	}
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for _, field := range sortedFields(fields) {
+		if err := form.WriteField(field, fields[field]); err != nil {
+			r.err = err
+			return r
+		}
+	}
+	for _, field := range sortedFields(files) {
+		if err := addFormFile(form, field, files[field]); err != nil {
+			r.err = err
+			return r
+		}
+	}
+	if err := form.Close(); err != nil {
+		r.err = err
+		return r
+	}
+	r.body = bytes.NewReader(body.Bytes())
+	return r.SetHeader("Content-Type", form.FormDataContentType())
+}
+
+func addFormFile(form *multipart.Writer, field, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	part, err := form.CreateFormFile(field, filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("read %s error: %v", file, err)
+	}
+	return nil
+}
+
+// sortedFields returns the fields of a form in a stable order.
+func sortedFields(values map[string]string) []string {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMultipartBody(t *testing.T) {
+	dir, err := ioutil.TempDir("", "multipart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chart := filepath.Join(dir, "nginx-1.0.0.tgz")
+	if err := ioutil.WriteFile(chart, []byte("chart archive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("expected a multipart form, got %v", err)
+			return
+		}
+		if value := req.FormValue("force"); value != "true" {
+			t.Errorf("unexpected field %q", value)
+		}
+		file, header, err := req.FormFile("chart")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		content, _ := ioutil.ReadAll(file)
+		if header.Filename != "nginx-1.0.0.tgz" || string(content) != "chart archive" {
+			t.Errorf("unexpected file %s: %q", header.Filename, content)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{MaxAttempts: 2, BaseDelay: 1}
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Post().
+		AbsPath("/api/chartrepo", "library", "charts").
+		MultipartBody(map[string]string{"force": "true"}, map[string]string{"chart": chart}).
+		Idempotent().
+		Do(context.Background()).
+		Error()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected the form to be sent again on retry, got %d attempts", attempts)
+	}
+
+	err = client.Post().Resource("charts").MultipartBody(nil, map[string]string{"chart": filepath.Join(dir, "missing")}).Do(context.Background()).Error()
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the error of the missing file, got %v", err)
+	}
+}
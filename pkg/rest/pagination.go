@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pagination is the paging metadata of a list response, from its X-Total-Count and Link
+// headers.
+type Pagination struct {
+	// Total is the total number of items matching the query, -1 if the server did not
+	// report it.
+	Total int64
+	// Next and Prev are the URLs of the next and the previous pages, as given by the server,
+	// empty if there is no such page.
+	Next string
+	Prev string
+}
+
+// HasNext returns true if there is a page after this one.
+func (p *Pagination) HasNext() bool {
+	return p.Next != ""
+}
+
+// NextPage returns the number of the next page, from the page parameter of its URL, and
+// false if there is no next page.
+func (p *Pagination) NextPage() (int64, bool) {
+	if p.Next == "" {
+		return 0, false
+	}
+	u, err := url.Parse(p.Next)
+	if err != nil {
+		return 0, false
+	}
+	page, err := strconv.ParseInt(u.Query().Get("page"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return page, true
+}
+
+// Pagination returns the paging metadata of a list response.
+func (r Result) Pagination() Pagination {
+	p := Pagination{Total: -1}
+	if total, ok := r.TotalCount(); ok {
+		p.Total = total
+	}
+	if r.header != nil {
+		p.Next, p.Prev = parseLinks(r.header.Values("Link"))
+	}
+	return p
+}
+
+// paginationKey is the context key of the pagination filled by the requests.
+type paginationKey struct{}
+
+// WithPagination returns a copy of ctx so that the list requests made with it fill
+// pagination with their paging metadata, e.g. to get the total count of the projects
+// along with the page listed by a service:
+//
+//	var page rest.Pagination
+//	projects, err := clientSet.V2.List(rest.WithPagination(ctx, &page), query)
+//	if err == nil && page.HasNext() {
+//		...
+//	}
+//
+// The pagination is the one of the last request made with the context.
+func WithPagination(ctx context.Context, pagination *Pagination) context.Context {
+	return context.WithValue(ctx, paginationKey{}, pagination)
+}
+
+// fillPagination fills the pagination of ctx, if any, with the paging metadata of result.
+func fillPagination(ctx context.Context, result Result) {
+	if ctx == nil {
+		return
+	}
+	if pagination, ok := ctx.Value(paginationKey{}).(*Pagination); ok && pagination != nil {
+		*pagination = result.Pagination()
+	}
+}
+
+// parseLinks returns the next and prev URLs of Link headers, e.g.
+// </api/v2.0/projects?page=3&page_size=10>; rel="next" , </api/v2.0/projects?page=1&page_size=10>; rel="prev"
+func parseLinks(values []string) (next, prev string) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if len(target) < 2 || target[0] != '<' || target[len(target)-1] != '>' {
+				continue
+			}
+			target = target[1 : len(target)-1]
+			for _, param := range parts[1:] {
+				key, value := splitParam(param)
+				if key != "rel" {
+					continue
+				}
+				for _, rel := range strings.Fields(value) {
+					switch rel {
+					case "next":
+						next = target
+					case "prev", "previous":
+						prev = target
+					}
+				}
+			}
+		}
+	}
+	return next, prev
+}
+
+// splitParam splits a link parameter, e.g. rel="next", into its key and unquoted value.
+func splitParam(param string) (string, string) {
+	i := strings.Index(param, "=")
+	if i < 0 {
+		return strings.ToLower(strings.TrimSpace(param)), ""
+	}
+	key := strings.ToLower(strings.TrimSpace(param[:i]))
+	return key, strings.Trim(strings.TrimSpace(param[i+1:]), `"`)
+}
+
+// resultOf returns the result holding the headers and the body of a response, for DoRaw
+// which does not transform the response.
+func resultOf(resp *http.Response, body []byte) Result {
+	return Result{header: resp.Header, body: body}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	for _, test := range []struct {
+		values     []string
+		next, prev string
+	}{
+		{nil, "", ""},
+		{[]string{`</api/v2.0/projects?page=2&page_size=10>; rel="next"`}, "/api/v2.0/projects?page=2&page_size=10", ""},
+		{[]string{`</api/v2.0/projects?page=3&page_size=10>; rel="next" , </api/v2.0/projects?page=1&page_size=10>; rel="prev"`}, "/api/v2.0/projects?page=3&page_size=10", "/api/v2.0/projects?page=1&page_size=10"},
+		{[]string{`<https://harbor.example.com/a?page=1>; rel=prev`, `<https://harbor.example.com/a?page=3>;rel="next last"`}, "https://harbor.example.com/a?page=3", "https://harbor.example.com/a?page=1"},
+		{[]string{`invalid; rel="next"`}, "", ""},
+	} {
+		next, prev := parseLinks(test.values)
+		if next != test.next || prev != test.prev {
+			t.Errorf("%v: expected %q and %q, got %q and %q", test.values, test.next, test.prev, next, prev)
+		}
+	}
+}
+
+func TestWithPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("page") == "2" {
+			w.Header().Set("X-Total-Count", "25")
+			w.Header().Set("Link", `</api/v2.0/projects?page=3&page_size=10>; rel="next" , </api/v2.0/projects?page=1&page_size=10>; rel="prev"`)
+		}
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var page Pagination
+	ctx := WithPagination(context.Background(), &page)
+	if err := client.Get().Resource("projects").Param("page", "2").Do(ctx).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 25 || page.Prev != "/api/v2.0/projects?page=1&page_size=10" || !page.HasNext() {
+		t.Errorf("unexpected pagination %+v", page)
+	}
+	if next, ok := page.NextPage(); !ok || next != 3 {
+		t.Errorf("expected the next page to be 3, got %d", next)
+	}
+
+	if _, _, err := client.Get().Resource("projects").DoRaw(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != -1 || page.HasNext() {
+		t.Errorf("expected the pagination of the last page, got %+v", page)
+	}
+	if _, ok := page.NextPage(); ok {
+		t.Error("the last page has no next page")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// EncodeParams encodes v, a struct or a map, or a pointer to one, into URL query
+// parameters. The parameter of a struct field is named by its url tag, or else its json
+// tag, or else its lowercased name, e.g.
+//
+//	type ArtifactQuery struct {
+//		Query                  // embedded structs are flattened
+//		WithTag bool `url:"with_tag,omitempty"`
+//	}
+//
+// The fields tagged "-" and, with omitempty, the zero fields are skipped, as are the nil
+// pointers. The elements of a slice are repeated parameters, times are formatted in
+// RFC 3339 and the other structs and maps are JSON encoded.
+func EncodeParams(v interface{}) (url.Values, error) {
+	params := url.Values{}
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return params, nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		return params, encodeStruct(params, value)
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := encodeParam(params, fmt.Sprint(iter.Key().Interface()), iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return params, nil
+	case reflect.Invalid:
+		return params, nil
+	}
+	return nil, fmt.Errorf("expected a struct or a map to encode as query parameters, got %T", v)
+}
+
+func encodeStruct(params url.Values, value reflect.Value) error {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, tagged := paramName(field)
+		if name == "-" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if field.Anonymous && !tagged {
+			for fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					break
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != timeType {
+				if err := encodeStruct(params, fieldValue); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		if err := encodeParam(params, name, fieldValue); err != nil {
+			return fmt.Errorf("encode %s error: %v", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// paramName returns the name of the parameter of a field, whether it is omitted when
+// empty and whether it is named by a tag.
+func paramName(field reflect.StructField) (name string, omitEmpty bool, tagged bool) {
+	tag, ok := field.Tag.Lookup("url")
+	if !ok {
+		tag, ok = field.Tag.Lookup("json")
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	if parts[0] == "" {
+		return strings.ToLower(field.Name), omitEmpty, false
+	}
+	return parts[0], omitEmpty, ok
+}
+
+func encodeParam(params url.Values, name string, value reflect.Value) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.String:
+		params.Add(name, value.String())
+	case reflect.Bool:
+		params.Add(name, strconv.FormatBool(value.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		params.Add(name, strconv.FormatInt(value.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		params.Add(name, strconv.FormatUint(value.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		params.Add(name, strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()))
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			params.Add(name, string(value.Bytes()))
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := encodeParam(params, name, value.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		if value.Type() == timeType {
+			params.Add(name, value.Interface().(time.Time).Format(time.RFC3339))
+			return nil
+		}
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return err
+		}
+		params.Add(name, string(data))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type pageQuery struct {
+	PageSize int64  `json:"page_size,omitempty"`
+	Page     int64  `json:"page,omitempty"`
+	Q        string `json:"q,omitempty"`
+}
+
+type artifactQuery struct {
+	pageQuery
+	WithTag   bool      `url:"with_tag,omitempty" json:"withTag"`
+	WithLabel bool      `json:"with_label"`
+	IDs       []int64   `url:"id"`
+	Since     time.Time `url:"since,omitempty"`
+	Owner     *string   `url:"owner"`
+	Ignored   string    `url:"-"`
+	Labels    map[string]string
+	internal  string
+}
+
+func TestEncodeParams(t *testing.T) {
+	since := time.Date(2021, 4, 7, 10, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name     string
+		value    interface{}
+		expected url.Values
+	}{
+		{"nil", nil, url.Values{}},
+		{"nil pointer", (*artifactQuery)(nil), url.Values{}},
+		{"empty", artifactQuery{}, url.Values{"with_label": {"false"}, "labels": {"null"}}},
+		{
+			name: "struct",
+			value: &artifactQuery{
+				pageQuery: pageQuery{PageSize: 100, Page: 9007199254740993, Q: "name=~nginx"},
+				WithTag:   true,
+				IDs:       []int64{1, 2},
+				Since:     since,
+				Ignored:   "ignored",
+				Labels:    map[string]string{"env": "prod"},
+				internal:  "internal",
+			},
+			expected: url.Values{
+				"page_size":  {"100"},
+				"page":       {"9007199254740993"},
+				"q":          {"name=~nginx"},
+				"with_tag":   {"true"},
+				"with_label": {"false"},
+				"id":         {"1", "2"},
+				"since":      {"2021-04-07T10:00:00Z"},
+				"labels":     {`{"env":"prod"}`},
+			},
+		},
+		{"map", map[string]interface{}{"page": 2, "q": "name=a"}, url.Values{"page": {"2"}, "q": {"name=a"}}},
+	} {
+		params, err := EncodeParams(test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(params, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, params)
+		}
+	}
+	if _, err := EncodeParams(42); err == nil {
+		t.Error("expected an error for a value which isn't a struct or a map")
+	}
+}
+
+func TestRequestParams(t *testing.T) {
+	r := (&Request{}).Params(&pageQuery{Page: 2}).Params((*pageQuery)(nil)).Params("sort=name")
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if expected := (url.Values{"page": {"2"}, "sort": {"name"}}); !reflect.DeepEqual(r.params, expected) {
+		t.Errorf("expected %v, got %v", expected, r.params)
+	}
+	if r := (&Request{}).Params(map[string]interface{}{"bad": make(chan int)}); r.err == nil {
+		t.Error("expected the encoding error to be kept")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+	"k8s.io/klog"
+	"sync"
+)
+
+// PinKey returns the key the fingerprint of the certificate of host is pinned under in the
+// store of Config.CertificatePins.
+func PinKey(host string) string {
+	return "certificate-pins/" + host
+}
+
+// CertificatePinError is returned when the certificate of a server doesn't match the one
+// pinned on the first connection, it is not retried.
+type CertificatePinError struct {
+	Host     string
+	Pinned   string
+	Received string
+}
+
+func (e *CertificatePinError) Error() string {
+	return fmt.Sprintf("certificate of %s changed: pinned %s, received %s, remove the pin if the certificate was renewed",
+		e.Host, e.Pinned, e.Received)
+}
+
+// IsCertificatePinError returns true if err is a *CertificatePinError.
+func IsCertificatePinError(err error) bool {
+	var pinErr *CertificatePinError
+	return errors.As(err, &pinErr)
+}
+
+// CertificateFingerprint returns the fingerprint certificates are pinned by, the hex
+// encoded SHA-256 of the DER encoded certificate prefixed with sha256:.
+func CertificateFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// verifyPinnedCertificate returns the tls.Config.VerifyConnection function pinning the
+// leaf certificate of the server in store on the first connection and rejecting any other
+// certificate afterwards. The pins are keyed by the server name of the connection, so that
+// the other hosts reached through the transport, e.g. a storage backend Harbor redirects
+// to, are pinned separately, defaultHost being used if the connection has none.
+func verifyPinnedCertificate(defaultHost string, s store.Store) func(tls.ConnectionState) error {
+	var mu sync.Mutex
+	return func(state tls.ConnectionState) error {
+		host := state.ServerName
+		if host == "" {
+			host = defaultHost
+		}
+		if len(state.PeerCertificates) == 0 {
+			return fmt.Errorf("no certificate received from %s", host)
+		}
+		received := CertificateFingerprint(state.PeerCertificates[0].Raw)
+		mu.Lock()
+		defer mu.Unlock()
+		pinned, ok, err := s.Get(PinKey(host))
+		if err != nil {
+			return fmt.Errorf("get certificate pin of %s error: %v", host, err)
+		}
+		if !ok {
+			if err := s.Put(PinKey(host), []byte(received)); err != nil {
+				return fmt.Errorf("pin certificate of %s error: %v", host, err)
+			}
+			klog.Warningf("Pinned the certificate %s of %s on first use", received, host)
+			return nil
+		}
+		if string(pinned) != received {
+			err := &CertificatePinError{Host: host, Pinned: string(pinned), Received: received}
+			klog.Errorf("Refusing to connect: %v", err)
+			return err
+		}
+		return nil
+	}
+}
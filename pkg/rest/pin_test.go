@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/hujianxiong/go-harbor/pkg/store"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCertificatePinning(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "pins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pins, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := func() *RESTClient {
+		config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+		config.CertificatePins = pins
+		client, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	if err := newClient().Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("expected the first connection to succeed, got %v", err)
+	}
+	serverURL, _ := url.Parse(server.URL)
+	pinned, ok, err := pins.Get(PinKey(serverURL.Hostname()))
+	if err != nil || !ok || string(pinned) != CertificateFingerprint(server.Certificate().Raw) {
+		t.Fatalf("expected the server certificate to be pinned, got %q %v %v", pinned, ok, err)
+	}
+	if err := newClient().Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Errorf("expected the pinned certificate to be accepted, got %v", err)
+	}
+
+	if err := pins.Put(PinKey(serverURL.Hostname()), []byte(CertificateFingerprint([]byte("other")))); err != nil {
+		t.Fatal(err)
+	}
+	err = newClient().Get().Resource("projects").Do(context.Background()).Error()
+	if !IsCertificatePinError(err) || !IsTLSHandshakeError(err) {
+		t.Errorf("expected a certificate pin error, got %v", err)
+	}
+}
+
+func TestVerifyPinnedCertificate(t *testing.T) {
+	unwritable := &store.FileStore{Dir: filepath.Join(os.TempDir(), "missing", "pins")}
+	verify := verifyPinnedCertificate("harbor.example.com", unwritable)
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("a")}}}
+	if err := verify(state); err == nil {
+		t.Errorf("expected an error when the pin can't be saved")
+	}
+	if err := verify(tls.ConnectionState{}); err == nil {
+		t.Errorf("expected an error without certificate")
+	}
+
+	// the hosts reached through the transport are pinned separately
+	pins := store.NewMemoryStore()
+	verify = verifyPinnedCertificate("harbor.example.com", pins)
+	api := tls.ConnectionState{ServerName: "harbor.example.com", PeerCertificates: []*x509.Certificate{{Raw: []byte("a")}}}
+	storage := tls.ConnectionState{ServerName: "s3.example.com", PeerCertificates: []*x509.Certificate{{Raw: []byte("b")}}}
+	for _, state := range []tls.ConnectionState{api, storage, api, storage} {
+		if err := verify(state); err != nil {
+			t.Errorf("unexpected error connecting to %s: %v", state.ServerName, err)
+		}
+	}
+	if pinned, _, _ := pins.Get(PinKey("s3.example.com")); string(pinned) != CertificateFingerprint([]byte("b")) {
+		t.Errorf("expected the certificate of the storage to be pinned, got %q", pinned)
+	}
+	storage.PeerCertificates[0].Raw = []byte("a")
+	if err := verify(storage); !IsCertificatePinError(err) {
+		t.Errorf("expected the certificate of the API to be rejected for the storage, got %v", err)
+	}
+}
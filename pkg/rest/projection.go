@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// projectionBuffers holds the buffers the selected fields of an item are re-encoded into
+// before being decoded, they are reused across the items and the calls.
+var projectionBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// IntoListFields stores a list response into obj, a pointer to a slice, like IntoList but
+// only decodes the given JSON fields of each item, e.g. "id" and "digest", the other ones
+// are left to their zero value. The items are decoded one at a time so that the nested
+// objects of the dropped fields, e.g. the tags or the scan overview of an artifact, are never
+// allocated, which matters when only a couple of fields of very large lists are needed.
+// Without fields it is equivalent to IntoList.
+func (r Result) IntoListFields(obj interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return r.IntoList(obj)
+	}
+	if r.err != nil {
+		return r.Error()
+	}
+	slice := reflect.ValueOf(obj)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice, got %T", obj)
+	}
+	slice = slice.Elem()
+	data, err := unwrapList(r.body)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	keys := make([][]byte, len(fields))
+	structFields := make([]reflect.StructField, len(fields))
+	for i, field := range fields {
+		if keys[i], err = json.Marshal(field); err != nil {
+			return err
+		}
+		structFields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: reflect.TypeOf(json.RawMessage{}),
+			Tag:  reflect.StructTag(fmt.Sprintf("json:%q", field)),
+		}
+	}
+	// item only has the selected fields, the other ones are skipped by the decoder without
+	// being allocated, and its raw values are reused from one item to the next.
+	item := reflect.New(reflect.StructOf(structFields)).Elem()
+	unmarshal := json.Unmarshal
+	if r.decoder != nil {
+		unmarshal = r.decoder.Unmarshal
+	}
+	buffer := projectionBuffers.Get().(*bytes.Buffer)
+	defer projectionBuffers.Put(buffer)
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	items := slice.Slice(0, 0)
+	for decoder.More() {
+		for i := range fields {
+			value := item.Field(i)
+			value.SetBytes(value.Bytes()[:0])
+		}
+		if err := decoder.Decode(item.Addr().Interface()); err != nil {
+			return err
+		}
+		buffer.Reset()
+		projectFields(buffer, item, keys)
+		element := reflect.New(slice.Type().Elem())
+		if err := unmarshal(buffer.Bytes(), element.Interface()); err != nil {
+			return err
+		}
+		items = reflect.Append(items, element.Elem())
+	}
+	slice.Set(items)
+	return nil
+}
+
+// projectFields writes the object made of the fields of item present in the response into
+// buffer, keys being their JSON encoded names.
+func projectFields(buffer *bytes.Buffer, item reflect.Value, keys [][]byte) {
+	buffer.WriteByte('{')
+	written := false
+	for i, key := range keys {
+		value := item.Field(i).Bytes()
+		if len(value) == 0 {
+			continue
+		}
+		if written {
+			buffer.WriteByte(',')
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+		written = true
+	}
+	buffer.WriteByte('}')
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type projectedItem struct {
+	ID     int               `json:"id"`
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+func TestResultIntoListFields(t *testing.T) {
+	body := `{"total":2,"items":[{"id":1,"name":"a","labels":{"k":"v"}},{"id":2,"labels":{},"extra":[1]}]}`
+	var items []projectedItem
+	if err := (Result{body: []byte(body)}).IntoListFields(&items, "id", "name"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []projectedItem{{ID: 1, Name: "a"}, {ID: 2}}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("unexpected items: %#v", items)
+	}
+
+	items = nil
+	if err := (Result{body: []byte(body)}).IntoListFields(&items); err != nil || len(items[0].Labels) != 1 {
+		t.Errorf("expected all the fields without projection, got %#v %v", items, err)
+	}
+	for _, body := range []string{"", "null", "[]"} {
+		items = nil
+		if err := (Result{body: []byte(body)}).IntoListFields(&items, "id"); err != nil || len(items) != 0 {
+			t.Errorf("%q: expected an empty list, got %#v %v", body, items, err)
+		}
+	}
+	var item projectedItem
+	if err := (Result{body: []byte(body)}).IntoListFields(&item, "id"); err == nil {
+		t.Errorf("expected an error for a non slice destination")
+	}
+	if err := (Result{body: []byte(`[{"id":"x"}]`)}).IntoListFields(&items, "id"); err == nil {
+		t.Errorf("expected an error for a mistyped field")
+	}
+}
+
+func largeList(n int) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		fmt.Fprintf(buffer, `{"id":%d,"name":"item-%d","labels":{"a":"1","b":"2","c":"3"}}`, i, i)
+	}
+	buffer.WriteByte(']')
+	return buffer.Bytes()
+}
+
+func BenchmarkIntoList(b *testing.B) {
+	body := largeList(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var items []projectedItem
+		if err := (Result{body: body}).IntoList(&items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIntoListFields(b *testing.B) {
+	body := largeList(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var items []projectedItem
+		if err := (Result{body: body}).IntoListFields(&items, "id"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"fmt"
+	"golang.org/x/net/http/httpproxy"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFor returns a proxy function, for Config.Proxy, sending the requests through
+// proxyURL except the ones to the hosts matching noProxy, given in the NO_PROXY format,
+// e.g. "harbor.internal", ".example.com" or "10.0.0.0/8". An empty proxyURL disables the
+// proxy, including the one of the environment.
+func ProxyFor(proxyURL string, noProxy ...string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, nil
+		}, nil
+	}
+	if u, err := url.Parse(proxyURL); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url %q", proxyURL)
+	}
+	proxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(noProxy, ","),
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProxyFor(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req.URL.Host)
+		w.Write([]byte("{}"))
+	}))
+	defer proxy.Close()
+
+	proxyFunc, err := ProxyFor(proxy.URL, "harbor.internal", ".example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := NewDefaultConfig("http://harbor.proxied", "admin", "Harbor12345")
+	config.Proxy = proxyFunc
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "harbor.proxied" {
+		t.Errorf("expected the request to go through the proxy, got %v", proxied)
+	}
+
+	for host, expected := range map[string]bool{
+		"harbor.proxied":       true,
+		"harbor.internal":      false,
+		"registry.example.com": false,
+	} {
+		u, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: "https", Host: host}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (u != nil) != expected {
+			t.Errorf("unexpected proxy %v for %s", u, host)
+		}
+	}
+
+	noProxy, err := ProxyFor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := noProxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "harbor.proxied"}}); u != nil {
+		t.Errorf("expected no proxy, got %v", u)
+	}
+	if _, err := ProxyFor("://invalid"); err == nil {
+		t.Error("expected an invalid proxy url to be rejected")
+	}
+}
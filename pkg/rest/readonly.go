@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is matched, with errors.Is, by the errors of the mutating requests refused
+// by a read-only client.
+var ErrReadOnly = errors.New("client is read-only")
+
+// ReadOnlyError is the error of a mutating request refused by a read-only client, see
+// Config.ReadOnly.
+type ReadOnlyError struct {
+	Verb string
+	URL  string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s %s refused: %v", e.Verb, e.URL, ErrReadOnly)
+}
+
+// Unwrap returns ErrReadOnly.
+func (e *ReadOnlyError) Unwrap() error {
+	return ErrReadOnly
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		methods = append(methods, req.Method)
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.ReadOnly = true
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, _, err := client.Get().Resource("projects").DoRaw(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, request := range []*Request{
+		client.Post().Resource("projects"),
+		client.Put().Resource("projects").Name("a"),
+		client.Verb(http.MethodPatch).Resource("projects").Name("a"),
+		client.Delete().Resource("projects").Name("a"),
+	} {
+		err := request.Do(context.Background()).Error()
+		var readOnlyErr *ReadOnlyError
+		if !errors.Is(err, ErrReadOnly) || !errors.As(err, &readOnlyErr) {
+			t.Errorf("expected a read-only error, got %v", err)
+		}
+	}
+	if _, _, err := client.Delete().Resource("projects").Name("a").DoRaw(context.Background()); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected a read-only error, got %v", err)
+	}
+	if len(methods) != 2 || methods[0] != http.MethodGet || methods[1] != http.MethodGet {
+		t.Errorf("expected only the GET requests to be sent, got %v", methods)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxRedirects is the number of redirects followed by http.Client.
+const defaultMaxRedirects = 10
+
+// RedirectPolicy decides how the redirects of Harbor are followed, e.g. when a blob
+// download is redirected to a presigned URL of S3 or GCS, which rejects the requests
+// carrying an Authorization header. Without a policy, the redirects are followed like
+// http.Client does, the credentials being only forwarded to the same domain.
+type RedirectPolicy struct {
+	// MaxRedirects is the maximum number of redirects followed by a request, 10 if zero.
+	// A negative value doesn't follow the redirects, the redirect response is returned
+	// instead, its Location being available with Result.Location.
+	MaxRedirects int
+	// StripAuthorization removes the credentials of the redirected requests, even to the
+	// host of Harbor, e.g. for a presigned URL served behind the same domain.
+	StripAuthorization bool
+	// AuthorizedHosts are the hosts the credentials are forwarded to on redirects, besides
+	// the domain of Harbor. It is ignored with StripAuthorization.
+	AuthorizedHosts []string
+}
+
+// checkRedirect implements http.Client.CheckRedirect.
+func (p *RedirectPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	maxRedirects := p.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	if maxRedirects < 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	switch {
+	case p.StripAuthorization:
+		req.Header.Del("Authorization")
+	case p.authorized(req.URL.Hostname()):
+		// http.Client only forwards the credentials to the same domain
+		if authorization := via[0].Header.Get("Authorization"); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+	}
+	return nil
+}
+
+func (p *RedirectPolicy) authorized(host string) bool {
+	for _, authorized := range p.AuthorizedHosts {
+		if strings.EqualFold(host, authorized) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	var authorization string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+		w.Write([]byte("blob"))
+	}))
+	defer storage.Close()
+	// the storage is reached by name, a different host than the one of Harbor
+	storageURL := strings.Replace(storage.URL, "127.0.0.1", "localhost", 1)
+	harbor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		target := storage.URL
+		if req.URL.Query().Get("host") == "localhost" {
+			target = storageURL
+		}
+		http.Redirect(w, req, target+"/presigned?X-Amz-Signature=abc", http.StatusTemporaryRedirect)
+	}))
+	defer harbor.Close()
+
+	download := func(policy *RedirectPolicy, host string) (string, int, string) {
+		config := NewDefaultConfig(harbor.URL, "admin", "Harbor12345")
+		config.RedirectPolicy = policy
+		client, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		authorization = ""
+		body, code, _ := client.Get().Resource("blobs").Param("host", host).DoRaw(context.Background())
+		return string(body), code, authorization
+	}
+
+	if body, _, auth := download(&RedirectPolicy{StripAuthorization: true}, ""); body != "blob" || auth != "" {
+		t.Errorf("expected the credentials to be stripped, got %q %q", body, auth)
+	}
+	if _, _, auth := download(nil, "localhost"); auth != "" {
+		t.Errorf("expected the credentials not to be sent to another host, got %q", auth)
+	}
+	if _, _, auth := download(&RedirectPolicy{AuthorizedHosts: []string{"LOCALHOST"}}, "localhost"); !strings.HasPrefix(auth, "Basic ") {
+		t.Errorf("expected the credentials to be sent to an authorized host, got %q", auth)
+	}
+	if _, code, auth := download(&RedirectPolicy{MaxRedirects: -1}, ""); code != http.StatusTemporaryRedirect || auth != "" {
+		t.Errorf("expected the redirect response, got %d", code)
+	}
+}
+
+func TestRedirectPolicyMaxRedirects(t *testing.T) {
+	via := func(n int) []*http.Request {
+		requests := make([]*http.Request, n)
+		for i := range requests {
+			requests[i], _ = http.NewRequest(http.MethodGet, "http://harbor/blobs", nil)
+		}
+		return requests
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://harbor/presigned", nil)
+	for _, test := range []struct {
+		max     int
+		via     int
+		stopped bool
+	}{
+		{2, 1, false},
+		{2, 2, true},
+		{0, defaultMaxRedirects - 1, false},
+		{0, defaultMaxRedirects, true},
+	} {
+		err := (&RedirectPolicy{MaxRedirects: test.max}).checkRedirect(req, via(test.via))
+		if stopped := err != nil; stopped != test.stopped {
+			t.Errorf("max %d via %d: expected stopped %v, got %v", test.max, test.via, test.stopped, err)
+		}
+	}
+}
@@ -95,6 +95,30 @@ type Request struct {
 	ctx context.Context
 
 	throttle flowcontrol2.RateLimiter
+	// apiPath detects the versioned API path of pathPrefix when the request is sent
+	apiPath *apiPathDetector
+	// credentials set the Authorization header when the request is sent
+	credentials *credentials
+	// auditHook records the request if it is a mutating operation
+	auditHook AuditHook
+	// readOnly refuses the request if it is a mutating operation
+	readOnly bool
+	// retryPolicy retries the request if it fails with a transient status code
+	retryPolicy *RetryPolicy
+	// circuitBreaker fails the request fast while the server is failing
+	circuitBreaker *CircuitBreaker
+	// logger logs the request once it completed
+	logger Logger
+	// metrics records the measurements of the request
+	metrics Metrics
+	// disableCompression does not ask the server for a compressed response
+	disableCompression bool
+	// retries counts the attempts made after the first one
+	retries int
+	// idempotent allows the retries of a POST, PUT or PATCH request
+	idempotent bool
+	// recorder captures the mutating request instead of sending it
+	recorder *Recorder
 }
 
 // Result contains the result of calling Request.Do().
@@ -103,6 +127,8 @@ type Result struct {
 	contentType string
 	err         error
 	statusCode  int
+	header      http.Header
+	decoder     Serializer
 }
 
 type ContentConfig struct {
@@ -114,6 +140,8 @@ type ContentConfig struct {
 	// as the default content type on any object sent to the server. If not set,
 	// "application/json" is used.
 	ContentType string
+	// Serializer encodes request bodies and decodes responses. If not set, encoding/json is used.
+	Serializer Serializer
 }
 
 // NewRequest creates a new request helper object for accessing runtime.Objects on a server.
@@ -187,7 +215,9 @@ func (r *Request) SetHeader(key string, values ...string) *Request {
 }
 
 // Timeout makes the request use the given duration as an overall timeout for the
-// request. Additionally, if set passes the value as "timeout" parameter in URL.
+// request, retries included, instead of the Timeout of the client, e.g. a few seconds for a
+// health check and several minutes for an export. A zero duration removes the timeout, the
+// request being bound by its context only.
 func (r *Request) Timeout(d time.Duration) *Request {
 	if r.err != nil {
 		return r
@@ -196,6 +226,17 @@ func (r *Request) Timeout(d time.Duration) *Request {
 	return r
 }
 
+// Throttle replaces the rate limiter of the client for the request, e.g. so that an
+// interactive call isn't starved behind a bulk job sharing the client. A nil rate limiter
+// makes the request bypass throttling.
+func (r *Request) Throttle(limiter flowcontrol2.RateLimiter) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.throttle = limiter
+	return r
+}
+
 // Body makes the request use obj as the body. Optional.
 // If obj is a string, try to read a file of that name.
 // If obj is a []byte, send it directly.
@@ -225,11 +266,11 @@ func (r *Request) Body(obj interface{}) *Request {
 		if reflect.ValueOf(t).IsNil() {
 			return r
 		}
-		data, _ := json.Marshal(t)
+		data, _ := r.content.serializer().Marshal(t)
 		r.body = bytes.NewReader(data)
 		r.SetHeader("Content-Type", r.content.ContentType)
 	case interface{}:
-		data, _ := json.Marshal(t)
+		data, _ := r.content.serializer().Marshal(t)
 		r.body = bytes.NewReader(data)
 		r.SetHeader("Content-Type", r.content.ContentType)
 		/*	case runtime.Object:
@@ -251,6 +292,24 @@ func (r *Request) Body(obj interface{}) *Request {
 	return r
 }
 
+// RawBody sets the body of the request to the content of body, sent as is with the
+// contentType, e.g. a pre-encoded payload or a file streamed without being read into
+// memory. The request can only be retried if body is an io.Seeker.
+func (r *Request) RawBody(body io.Reader, contentType string) *Request {
+	if r.err != nil {
+		return r
+	}
+	if body == nil {
+		r.err = fmt.Errorf("body may not be nil")
+		return r
+	}
+	r.body = body
+	if len(contentType) > 0 {
+		r.SetHeader("Content-Type", contentType)
+	}
+	return r
+}
+
 // Param creates a query parameter with the given string value.
 func (r *Request) Param(paramName, s string) *Request {
 	if r.err != nil {
@@ -271,6 +330,7 @@ func (r *Request) RequestURI(uri string) *Request {
 		return r
 	}
 	r.pathPrefix = locator.Path
+	r.apiPath = nil
 	if len(locator.Query()) > 0 {
 		if r.params == nil {
 			r.params = make(url.Values)
@@ -304,6 +364,7 @@ func (r *Request) AbsPath(segments ...string) *Request {
 		return r
 	}
 	r.pathPrefix = path.Join(r.baseURL.Path, path.Join(segments...))
+	r.apiPath = nil
 	if len(segments) == 1 && (len(r.baseURL.Path) > 1 || len(segments[0]) > 1) && strings.HasSuffix(segments[0], "/") {
 		// preserve any trailing slashes for legacy behavior
 		r.pathPrefix += "/"
@@ -338,10 +399,6 @@ func (r *Request) URL() *url.URL {
 		}
 	}
 
-	// timeout is handled specially here.
-	if r.timeout != 0 {
-		query.Set("timeout", r.timeout.String())
-	}
 	finalURL.RawQuery = query.Encode()
 	return finalURL
 }
@@ -390,31 +447,36 @@ func (r *Request) Name(resourceName string) *Request {
 	return r
 }
 
-func (r *Request) tryThrottle() error {
+func (r *Request) tryThrottle(ctx context.Context) error {
 	if r.throttle == nil {
 		return nil
 	}
 
 	now := time.Now()
-	var err error
-	r.throttle.Accept()
-	if latency := time.Since(now); latency > longThrottleLatency {
+	err := r.throttle.Wait(ctx)
+	latency := time.Since(now)
+	if latency > longThrottleLatency {
 		klog.V(4).Infof("Throttling request took %v, request: %s:%s", latency, r.verb, r.URL().String())
 	}
+	if r.metrics != nil {
+		r.metrics.ObserveThrottle(r.verb, r.Route(), latency)
+	}
 
 	return err
 }
 
 // Do formats and executes the request. Returns a Result object for easy response
-// processing.
+// processing. The request, its rate limiter wait and its retries are aborted once ctx
+// is done.
 //
 // Error type:
 //  * If the request can't be constructed, or an error happened earlier while building its
 //    arguments: *RequestConstructionError
 //  * If the server responds with a status: *errors.StatusError or *errors.UnexpectedObjectError
 //  * http.Client.Do errors are returned directly.
-func (r *Request) Do() Result {
-	if err := r.tryThrottle(); err != nil {
+func (r *Request) Do(ctx context.Context) Result {
+	r.ctx = ctx
+	if err := r.tryThrottle(ctx); err != nil {
 		return Result{err: err}
 	}
 
@@ -425,6 +487,7 @@ func (r *Request) Do() Result {
 	if err != nil {
 		return Result{err: err}
 	}
+	fillPagination(ctx, result)
 	return result
 }
 
@@ -433,13 +496,58 @@ func (r *Request) Do() Result {
 // fn at most once. It will return an error if a problem occurred prior to connecting to the
 // server - the provided function is responsible for handling server errors.
 func (r *Request) request(fn func(*http.Request, *http.Response)) error {
+	r.detectAPIPath()
+	if r.recorder != nil && isMutating(r.verb) {
+		return r.record(fn)
+	}
+	if r.readOnly && isMutating(r.verb) {
+		return &ReadOnlyError{Verb: r.verb, URL: r.URL().String()}
+	}
+	if r.circuitBreaker == nil || r.err != nil {
+		return r.observeRequest(fn)
+	}
+	if err := r.circuitBreaker.allow(r.URL().String()); err != nil {
+		return err
+	}
+	var response *http.Response
+	err := r.observeRequest(func(req *http.Request, resp *http.Response) {
+		response = resp
+		fn(req, resp)
+	})
+	if response == nil && r.ctx != nil && r.ctx.Err() != nil {
+		// canceled by the caller, which says nothing about the server
+		r.circuitBreaker.release()
+	} else {
+		r.circuitBreaker.record(response, err)
+	}
+	return err
+}
+
+// auditRequest sends the request and records it with the audit hook if it is a mutating
+// operation.
+func (r *Request) auditRequest(fn func(*http.Request, *http.Response)) error {
+	if r.auditHook == nil || !isMutating(r.verb) || r.err != nil {
+		return r.doRequest(fn)
+	}
+	record := r.newAuditRecord()
+	err := r.doRequest(func(req *http.Request, resp *http.Response) {
+		record.StatusCode = resp.StatusCode
+		fn(req, resp)
+	})
+	r.auditHook.Audit(record.finish(err))
+	return err
+}
+
+// doRequest sends the request, retrying it as the server instructs, and calls fn with the
+// final response.
+func (r *Request) doRequest(fn func(*http.Request, *http.Response)) error {
 	if r.err != nil {
 		klog.V(4).Infof("Error in request: %v", r.err)
 		return r.err
 	}
 
 	// TODO: added to catch programmer errors (invoking operations with an object with an empty namespace)
-	if (r.verb == "GET" || r.verb == "PUT" || r.verb == "DELETE") && r.projectSet && len(r.resourceName) > 0 && len(r.project) == 0 {
+	if (r.verb == "GET" || r.verb == "PUT" || r.verb == "PATCH" || r.verb == "DELETE") && r.projectSet && len(r.resourceName) > 0 && len(r.project) == 0 {
 		return fmt.Errorf("an empty namespace may not be set when a resource name is provided")
 	}
 	if (r.verb == "POST") && r.projectSet && len(r.project) == 0 {
@@ -454,6 +562,7 @@ func (r *Request) request(fn func(*http.Request, *http.Response)) error {
 	// Right now we make about ten retry attempts if we get a Retry-After response.
 	maxRetries := 10
 	retries := 0
+	start := time.Now()
 	for {
 		url := r.URL().String()
 		req, err := http.NewRequest(r.verb, url, r.body)
@@ -471,17 +580,45 @@ func (r *Request) request(fn func(*http.Request, *http.Response)) error {
 		if r.ctx != nil {
 			req = req.WithContext(r.ctx)
 		}
-		req.Header = r.headers
+		req = req.WithContext(r.withRoute(req.Context()))
+		req.Header = r.headers.Clone()
+		if authorization := r.credentials.authorization(); authorization != "" {
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+			req.Header.Set("Authorization", authorization)
+		}
+		if !r.disableCompression {
+			acceptGzip(req)
+		}
 
 		if retries > 0 {
+			r.retries = retries
+			if r.metrics != nil {
+				r.metrics.ObserveRetry(r.verb, r.Route())
+			}
 			// We are retrying the request that we already send to apiserver
 			// at least once before.
 			// This request should also be throttled with the client-internal throttler.
-			if err := r.tryThrottle(); err != nil {
+			if err := r.tryThrottle(r.ctx); err != nil {
 				return err
 			}
 		}
 		resp, err := client.Do(req)
+		if err == nil {
+			decompressResponse(resp)
+		}
+		if err != nil && isTLSHandshakeFailure(err) {
+			return &TLSHandshakeError{Host: req.URL.Host, Err: err}
+		}
+		if err != nil && req.Context().Err() != nil {
+			// the request was canceled or timed out, there is no point retrying it
+			return err
+		}
+		if err != nil && !r.retryable() {
+			// the request may have reached the server, retrying it could apply it twice
+			return err
+		}
 		if err != nil {
 			// For the purpose of retry, we set the artificial "retry-after" response.
 			// TODO: Should we clean the original response if it exists?
@@ -492,6 +629,7 @@ func (r *Request) request(fn func(*http.Request, *http.Response)) error {
 			}
 		}
 
+		var backoff time.Duration
 		done := func() bool {
 			// Ensure the response body is fully read and closed
 			// before we reconnect, so that we reuse the same TCP
@@ -505,7 +643,16 @@ func (r *Request) request(fn func(*http.Request, *http.Response)) error {
 			}()
 
 			retries++
-			if seconds, wait := checkWait(resp); wait && retries < maxRetries {
+			if !r.retryable() {
+				fn(req, resp)
+				return true
+			}
+			if delay, retry := r.retryPolicy.retry(retries, time.Since(start), resp); retry && r.rewindBody() {
+				klog.V(4).Infof("Got a %d response for attempt %d to %v, retrying in %v", resp.StatusCode, retries, url, delay)
+				backoff = delay
+				return false
+			}
+			if seconds, wait := checkWait(resp); wait && retries < maxRetries && !r.retryPolicy.honorsRetryAfter(resp) {
 				if seeker, ok := r.body.(io.Seeker); ok && r.body != nil {
 					_, err := seeker.Seek(0, 0)
 					if err != nil {
@@ -524,6 +671,44 @@ func (r *Request) request(fn func(*http.Request, *http.Response)) error {
 		if done {
 			return nil
 		}
+		if backoff > 0 {
+			if err := r.sleep(backoff); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// rewindBody seeks the body of the request back to its beginning before a retry, it
+// returns false if the body can't be rewound.
+func (r *Request) rewindBody() bool {
+	if r.body == nil {
+		return true
+	}
+	seeker, ok := r.body.(io.Seeker)
+	if !ok {
+		return false
+	}
+	if _, err := seeker.Seek(0, 0); err != nil {
+		klog.V(4).Infof("Could not retry request, can't Seek() back to beginning of body for %T", r.body)
+		return false
+	}
+	return true
+}
+
+// sleep waits for d, it returns the error of the context of the request if it is done first.
+func (r *Request) sleep(d time.Duration) error {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
@@ -551,24 +736,30 @@ func retryAfterSeconds(resp *http.Response) (int, bool) {
 	return 0, false
 }
 
-// DoRaw executes the request but does not process the response body.
-func (r *Request) DoRaw() ([]byte, error) {
-	if err := r.tryThrottle(); err != nil {
-		return nil, err
+// DoRaw executes the request but does not process the response body, e.g. to call an
+// endpoint with a payload which isn't JSON or isn't modeled yet. It returns the body and
+// the status code of the response, 0 if none was received. The body of an unsuccessful
+// response is returned with an APIError. The request is aborted once ctx is done.
+func (r *Request) DoRaw(ctx context.Context) ([]byte, int, error) {
+	r.ctx = ctx
+	if err := r.tryThrottle(ctx); err != nil {
+		return nil, 0, err
 	}
 
 	var result Result
 	err := r.request(func(req *http.Request, resp *http.Response) {
+		result.statusCode = resp.StatusCode
 		result.body, result.err = ioutil.ReadAll(resp.Body)
 		glogBody("Response Body", result.body)
+		fillPagination(ctx, resultOf(resp, result.body))
 		if resp.StatusCode < http.StatusOK || resp.StatusCode > http.StatusPartialContent {
 			result.err = r.transformUnstructuredResponseError(resp, req, result.body)
 		}
 	})
 	if err != nil {
-		return nil, err
+		return nil, result.statusCode, err
 	}
-	return result.body, result.err
+	return result.body, result.statusCode, result.err
 }
 
 // transformUnstructuredResponseError handles an error from the server that is not in a structured form.
@@ -596,12 +787,14 @@ func (r *Request) transformUnstructuredResponseError(resp *http.Response, req *h
 		}
 	}
 	//retryAfter, _ := retryAfterSeconds(resp)
-	return r.newUnstructuredResponseError(body, resp.StatusCode, req)
+	return r.newUnstructuredResponseError(body, resp, req)
 }
 
 // newUnstructuredResponseError instantiates the appropriate generic error for the provided input. It also logs the body.
-func (r *Request) newUnstructuredResponseError(body []byte, statusCode int, req *http.Request) error {
-	return fmt.Errorf("%s url:%s StatusCode: %d", req.Method, req.URL.Path, statusCode)
+func (r *Request) newUnstructuredResponseError(body []byte, resp *http.Response, req *http.Request) error {
+	err := newAPIError(body, resp.StatusCode, req)
+	err.RequestID = resp.Header.Get(requestIDHeader)
+	return err
 }
 
 // transformResponse converts an API response into a structured API object
@@ -646,11 +839,13 @@ func (r *Request) transformResponse(resp *http.Response, req *http.Request) Resu
 		// calculate an unstructured error from the response which the Result object may use if the caller
 		// did not return a structured error.
 		//retryAfter, _ := retryAfterSeconds(resp)
-		err := r.newUnstructuredResponseError(body, resp.StatusCode, req)
+		err := r.newUnstructuredResponseError(body, resp, req)
 		return Result{
 			body:        body,
 			contentType: contentType,
 			statusCode:  resp.StatusCode,
+			header:      resp.Header,
+			decoder:     r.content.serializer(),
 			err:         err,
 		}
 	}
@@ -659,6 +854,8 @@ func (r *Request) transformResponse(resp *http.Response, req *http.Request) Resu
 		body:        body,
 		contentType: contentType,
 		statusCode:  resp.StatusCode,
+		header:      resp.Header,
+		decoder:     r.content.serializer(),
 	}
 }
 
@@ -699,17 +896,20 @@ func truncateBody(body string) string {
 // additional information in Status will be used to enrich the error.
 func (r Result) Into(obj interface{}) error {
 	if r.err != nil {
-		// Check whether the result has a Status object in the body and prefer that.
-		if r.contentType == "application/json; charset=utf-8" {
-			return fmt.Errorf("%v message:%s", r.err, string(r.body))
-		}
-		return r.err
+		return r.Error()
+	}
+	if r.decoder == nil {
+		return json.Unmarshal(r.body, obj)
 	}
-	return json.Unmarshal(r.body, obj)
+	return r.decoder.Unmarshal(r.body, obj)
 }
 
 func (r Result) Error() error {
 	if r.err != nil {
+		if _, ok := r.err.(*APIError); ok {
+			// the message of the server is already parsed in the error
+			return r.err
+		}
 		// Check whether the result has a Status object in the body and prefer that.
 		if r.contentType == "application/json; charset=utf-8" {
 			return fmt.Errorf("%v message:%s", r.err, string(r.body))
@@ -719,8 +919,70 @@ func (r Result) Error() error {
 	return nil
 }
 
+// TotalCount returns the total number of items of a paginated list, as reported by
+// the X-Total-Count response header or, for lists wrapped in an object, by its total
+// field, and false if there is neither.
+func (r Result) TotalCount() (int64, bool) {
+	if r.header != nil {
+		if total, err := strconv.ParseInt(r.header.Get("X-Total-Count"), 10, 64); err == nil {
+			return total, true
+		}
+	}
+	return wrappedTotal(r.body)
+}
+
+// StatusCode sets statusCode to the HTTP status code of the response, 0 if the request
+// did not get a response.
+func (r Result) StatusCode(statusCode *int) Result {
+	*statusCode = r.statusCode
+	return r
+}
+
+// ContentType sets contentType to the Content-Type of the response.
+func (r Result) ContentType(contentType *string) Result {
+	*contentType = r.contentType
+	return r
+}
+
+// Raw returns the raw body of the response, along with the error of the request if any.
+func (r Result) Raw() ([]byte, error) {
+	return r.body, r.Error()
+}
+
+// RetryAfter returns the delay the server asked to wait before retrying, from the
+// Retry-After response header given in seconds or as an HTTP date, and false if the
+// header is missing.
+func (r Result) RetryAfter() (time.Duration, bool) {
+	return parseRetryAfter(r.header, time.Now())
+}
+
+// Params adds the query parameters encoded from o, a struct or a map, or a pointer to one,
+// see EncodeParams, a nil pointer adds none. Other values are handled by Query.
 func (r *Request) Params(o interface{}) *Request {
-	return r.Query(o)
+	if r.err != nil {
+		return r
+	}
+	v := reflect.ValueOf(o)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return r
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		return r.Query(o)
+	}
+	params, err := EncodeParams(v.Interface())
+	if err != nil {
+		r.err = err
+		return r
+	}
+	for name, values := range params {
+		for _, value := range values {
+			r.setParam(name, value)
+		}
+	}
+	return r
 }
 
 func (r *Request) Query(content interface{}) *Request {
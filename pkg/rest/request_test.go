@@ -16,11 +16,19 @@ See the License for the specific language governing permissions and
 package rest
 
 import (
+	"context"
+	"errors"
+	flowcontrol2 "github.com/hujianxiong/go-harbor/pkg/rest/util/flowcontrol"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewRequestSetsAccept(t *testing.T) {
@@ -141,3 +149,211 @@ func TestRequestBody(t *testing.T) {
 		t.Errorf("should have set err and left body nil: %#v", r)
 	}
 }
+
+func TestRequestRawBody(t *testing.T) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		contentType = req.Header.Get("Content-Type")
+		data, _ := ioutil.ReadAll(req.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a reader without a length, sent chunked
+	reader, writer := io.Pipe()
+	go func() {
+		writer.Write([]byte(`{"name":`))
+		writer.Write([]byte(`"library"}`))
+		writer.Close()
+	}()
+	err = client.Post().Resource("projects").RawBody(reader, "application/vnd.custom+json").Do(context.Background()).Error()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "application/vnd.custom+json" || body != `{"name":"library"}` {
+		t.Errorf("unexpected body %q of type %q", body, contentType)
+	}
+
+	if r := (&Request{}).RawBody(nil, "text/plain"); r.err == nil {
+		t.Error("a nil body should be refused")
+	}
+}
+
+func TestResultTotalCount(t *testing.T) {
+	r := Result{header: http.Header{"X-Total-Count": []string{"42"}}}
+	if total, ok := r.TotalCount(); !ok || total != 42 {
+		t.Errorf("unexpected total count: %d %v", total, ok)
+	}
+	if _, ok := (Result{}).TotalCount(); ok {
+		t.Errorf("expected no total count without the header")
+	}
+}
+
+func TestResultRetryAfter(t *testing.T) {
+	r := Result{statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": []string{"3"}}}
+	var code int
+	r.StatusCode(&code)
+	if code != http.StatusTooManyRequests {
+		t.Errorf("unexpected status code: %d", code)
+	}
+	if delay, ok := r.RetryAfter(); !ok || delay != 3*time.Second {
+		t.Errorf("unexpected retry after: %v %v", delay, ok)
+	}
+	if _, ok := (Result{}).RetryAfter(); ok {
+		t.Errorf("expected no retry after without the header")
+	}
+}
+
+func TestResultRaw(t *testing.T) {
+	var contentType string
+	body, err := (Result{body: []byte("{}"), contentType: "application/spdx+json"}).ContentType(&contentType).Raw()
+	if err != nil || string(body) != "{}" || contentType != "application/spdx+json" {
+		t.Errorf("unexpected raw result: %q %q %v", body, contentType, err)
+	}
+	if _, err := (Result{err: errors.New("not found")}).Raw(); err == nil {
+		t.Errorf("expected the error of the request")
+	}
+}
+
+func TestRequestThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	never := flowcontrol2.NewFakeNeverRateLimiter()
+	defer never.Stop()
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RateLimiter = never
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Get().Resource("projects").Do(ctx).Error(); err == nil {
+		t.Error("expected the request to wait for the rate limiter of the client")
+	}
+	if err := client.Get().Resource("projects").Throttle(nil).Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error bypassing the rate limiter: %v", err)
+	}
+	always := flowcontrol2.NewFakeAlwaysRateLimiter()
+	if err := client.Get().Resource("projects").Throttle(always).Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error replacing the rate limiter: %v", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	// the handler of a timed out request keeps running while the test goes on
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		queries = append(queries, req.URL.RawQuery)
+		mu.Unlock()
+		if req.URL.Path == "/api/v2.0/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Timeout = 50 * time.Millisecond
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper { return rt }
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("slow").Do(context.Background()).Error(); err == nil {
+		t.Error("expected the timeout of the client to apply")
+	}
+	if err := client.Get().Resource("slow").Timeout(time.Second).Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error extending the timeout: %v", err)
+	}
+	if err := client.Get().Resource("slow").Timeout(0).Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error removing the timeout: %v", err)
+	}
+	mu.Lock()
+	for _, query := range queries {
+		if query != "" {
+			t.Errorf("the timeout should not be sent to the server: %q", query)
+		}
+	}
+	mu.Unlock()
+	if err := client.Get().Resource("fast").Timeout(time.Nanosecond).Do(context.Background()).Error(); err == nil {
+		t.Error("expected the timeout of the request to apply")
+	}
+}
+
+func TestResultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Request-Id", "b6b5b8a4")
+		if req.Method == http.MethodPost {
+			w.Header().Set("Location", "/api/v2.0/projects/12")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	result := client.Post().Resource("projects").Body(map[string]string{"project_name": "library"}).Do(context.Background()).StatusCode(&code)
+	if err := result.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if code != http.StatusCreated || result.RequestID() != "b6b5b8a4" || result.Location() != "/api/v2.0/projects/12" {
+		t.Errorf("unexpected result %d %q %q", code, result.RequestID(), result.Location())
+	}
+	if id, ok := result.LocationID(); !ok || id != 12 {
+		t.Errorf("unexpected location ID %d %v", id, ok)
+	}
+	if result.Header().Get("X-Request-Id") != "b6b5b8a4" {
+		t.Errorf("unexpected headers %v", result.Header())
+	}
+
+	err = client.Get().Resource("projects").Name("missing").Do(context.Background()).Error()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "b6b5b8a4" {
+		t.Errorf("expected the request ID in the error, got %#v", err)
+	}
+	if _, ok := (Result{}).LocationID(); ok || (Result{}).RequestID() != "" {
+		t.Error("expected no location nor request ID without a response")
+	}
+}
+
+func TestRequestDoRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v2.0/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("Pong"))
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, code, err := client.Get().Resource("ping").DoRaw(context.Background())
+	if err != nil || code != http.StatusAccepted || string(body) != "Pong" {
+		t.Errorf("unexpected raw response %q %d %v", body, code, err)
+	}
+	body, code, err = client.Get().Resource("missing").DoRaw(context.Background())
+	if !IsNotFound(err) || code != http.StatusNotFound || string(body) != "not found" {
+		t.Errorf("unexpected raw response %q %d %v", body, code, err)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// requestIDHeader is the header Harbor identifies a request with in its logs.
+const requestIDHeader = "X-Request-Id"
+
+// Header returns the headers of the response, nil if no response was received.
+func (r Result) Header() http.Header {
+	return r.header
+}
+
+// RequestID returns the X-Request-Id of the response, the ID of the request in the logs of
+// Harbor, or an empty string if the response has none.
+func (r Result) RequestID() string {
+	return r.header.Get(requestIDHeader)
+}
+
+// Location returns the Location header of the response, the path of the resource a 201
+// response created, e.g. /api/v2.0/projects/12, or an empty string if the response has
+// none.
+func (r Result) Location() string {
+	return r.header.Get("Location")
+}
+
+// LocationID returns the ID ending the Location of the response, e.g. 12 for
+// /api/v2.0/projects/12, and false if the Location doesn't end with a numeric ID.
+func (r Result) LocationID() (int64, bool) {
+	location := r.Location()
+	if location == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(path.Base(location), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults of RetryPolicy.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 200 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+	DefaultMaxRetryAfter    = time.Minute
+)
+
+// DefaultRetryableStatusCodes are the status codes retried by default, the ones returned by
+// a load balancer or a proxy while Harbor is restarting or overloaded.
+var DefaultRetryableStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// Jitter is the randomization of the backoff of a RetryPolicy, so that the clients failing
+// at the same time, e.g. when Harbor restarts, don't retry at the same time too.
+type Jitter int
+
+const (
+	// JitterNone waits for the exact backoff.
+	JitterNone Jitter = iota
+	// JitterFull waits for a random duration between zero and the backoff.
+	JitterFull
+	// JitterEqual waits for half of the backoff plus a random duration up to the other half.
+	JitterEqual
+)
+
+// RetryPolicy retries the requests failing with a transient status code, waiting with an
+// exponential backoff between the attempts. The requests whose body can't be rewound are
+// not retried, nor the POST, PUT and PATCH requests not marked with Request.Idempotent.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request, including the first one,
+	// DefaultRetryMaxAttempts if 0.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, doubled for each following one,
+	// DefaultRetryBaseDelay if 0.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts, DefaultRetryMaxDelay if 0.
+	MaxDelay time.Duration
+	// RetryableStatusCodes are the status codes retried, DefaultRetryableStatusCodes if empty.
+	RetryableStatusCodes []int
+	// HonorRetryAfter retries the 429 and 503 responses with a Retry-After header after the
+	// delay it asks for rather than the backoff.
+	HonorRetryAfter bool
+	// MaxRetryAfter caps the delay of a Retry-After header, the responses asking for a
+	// longer wait are returned. DefaultMaxRetryAfter if 0.
+	MaxRetryAfter time.Duration
+	// Jitter randomizes the backoff, JitterNone by default.
+	Jitter Jitter
+	// MaxElapsedTime stops the retries of a request once the next attempt would start
+	// later than the duration after the first one, whatever MaxAttempts. Unlimited if 0.
+	MaxElapsedTime time.Duration
+}
+
+// Idempotent marks the request safe to retry, e.g. a PUT replacing a resource with the same
+// body. The GET, HEAD, OPTIONS and DELETE requests are retried by default, the POST, PUT
+// and PATCH ones only when marked, so that a request whose response was lost, e.g.
+// creating a project or a robot account, isn't applied twice.
+func (r *Request) Idempotent() *Request {
+	r.idempotent = true
+	return r
+}
+
+// retryable returns true if the request can be sent again after a failed attempt.
+func (r *Request) retryable() bool {
+	switch r.verb {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	}
+	return r.idempotent
+}
+
+// retry returns the wait before the next attempt and true if the response of the attempt,
+// counted from 1 and sent elapsed after the first one, must be retried.
+func (p *RetryPolicy) retry(attempt int, elapsed time.Duration, resp *http.Response) (time.Duration, bool) {
+	delay, retry := p.nextDelay(attempt, resp)
+	if retry && p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return 0, false
+	}
+	return delay, retry
+}
+
+// nextDelay returns the wait before the attempt following attempt and true if its response
+// must be retried.
+func (p *RetryPolicy) nextDelay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultRetryMaxAttempts
+	}
+	if attempt >= maxAttempts {
+		return 0, false
+	}
+	if p.honorsRetryAfter(resp) {
+		if delay, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+			maxRetryAfter := p.MaxRetryAfter
+			if maxRetryAfter <= 0 {
+				maxRetryAfter = DefaultMaxRetryAfter
+			}
+			return delay, delay <= maxRetryAfter
+		}
+	}
+	if !p.retryable(resp.StatusCode) {
+		return 0, false
+	}
+	return p.jitter(p.backoff(attempt)), true
+}
+
+// honorsRetryAfter returns true if the Retry-After header of the response, if any, decides
+// whether and when it is retried.
+func (p *RetryPolicy) honorsRetryAfter(resp *http.Response) bool {
+	return p != nil && p.HonorRetryAfter && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable)
+}
+
+func (p *RetryPolicy) retryable(statusCode int) bool {
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait after the attempt, counted from 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay, maxDelay := p.BaseDelay, p.MaxDelay
+	if delay <= 0 {
+		delay = DefaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// jitter randomizes the backoff delay with the Jitter of the policy.
+func (p *RetryPolicy) jitter(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return delay
+	}
+	switch p.Jitter {
+	case JitterFull:
+		return time.Duration(rand.Int63n(int64(delay) + 1))
+	case JitterEqual:
+		return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, given in seconds or as an
+// HTTP date, and false if it is missing or invalid.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var attempts int
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{BaseDelay: time.Millisecond}
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Post().Resource("projects").Body([]byte("{}")).Idempotent().Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	if err := client.Post().Resource("projects").Body([]byte("{}")).Do(context.Background()).Error(); err == nil {
+		t.Error("expected the error of the first attempt")
+	}
+	if attempts != 1 {
+		t.Errorf("a POST request not marked idempotent must not be retried, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	config.RetryPolicy.MaxAttempts = 2
+	client, _ = RESTClientFor(config)
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err == nil {
+		t.Error("expected the error of the last attempt")
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+
+	attempts, status = 0, http.StatusInternalServerError
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err == nil {
+		t.Error("expected the error of the first attempt")
+	}
+	if attempts != 1 {
+		t.Errorf("a non retryable status code must not be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryPolicyContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Get().Resource("projects").Do(ctx).Error(); err != context.DeadlineExceeded {
+		t.Errorf("expected the backoff to be aborted by the context, got %v", err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 30: 5 * time.Second} {
+		if delay := policy.backoff(attempt); delay != expected {
+			t.Errorf("expected a backoff of %v after attempt %d, got %v", expected, attempt, delay)
+		}
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	for jitter, min := range map[Jitter]time.Duration{JitterFull: 0, JitterEqual: 2 * time.Second} {
+		policy := &RetryPolicy{BaseDelay: 4 * time.Second, Jitter: jitter}
+		distinct := map[time.Duration]bool{}
+		for i := 0; i < 100; i++ {
+			delay := policy.jitter(policy.backoff(1))
+			if delay < min || delay > 4*time.Second {
+				t.Fatalf("jitter %d: delay %v out of [%v, 4s]", jitter, delay, min)
+			}
+			distinct[delay] = true
+		}
+		if len(distinct) < 2 {
+			t.Errorf("jitter %d: expected random delays", jitter)
+		}
+	}
+	if delay := (&RetryPolicy{BaseDelay: time.Second}).jitter(time.Second); delay != time.Second {
+		t.Errorf("expected no jitter by default, got %v", delay)
+	}
+}
+
+func TestRetryPolicyMaxElapsedTime(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 100, BaseDelay: time.Second, MaxElapsedTime: 10 * time.Second}
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	if delay, retry := policy.retry(2, 7*time.Second, resp); !retry || delay != 2*time.Second {
+		t.Errorf("expected a retry in 2s, got %v %v", delay, retry)
+	}
+	if _, retry := policy.retry(3, 7*time.Second, resp); retry {
+		t.Error("expected no retry past the maximum elapsed time")
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	var attempts int
+	retryAfter := "1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &RetryPolicy{HonorRetryAfter: true, MaxRetryAfter: 2 * time.Second}
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts != 2 || time.Since(start) < time.Second {
+		t.Errorf("expected a retry after 1s, got %d attempts in %v", attempts, time.Since(start))
+	}
+
+	attempts, retryAfter = 0, "60"
+	var statusCode int
+	result := client.Get().Resource("projects").Do(context.Background()).StatusCode(&statusCode)
+	if attempts != 1 || statusCode != http.StatusTooManyRequests {
+		t.Errorf("a Retry-After above the cap must not be retried, got %d attempts and status %d", attempts, statusCode)
+	}
+	if delay, ok := result.RetryAfter(); !ok || delay != time.Minute {
+		t.Errorf("expected a Retry-After of 1m, got %v", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for value, expected := range map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Wed, 01 Jan 2020 00:00:30 GMT": 30 * time.Second,
+		"Tue, 31 Dec 2019 23:59:00 GMT": 0,
+	} {
+		delay, ok := parseRetryAfter(http.Header{"Retry-After": []string{value}}, now)
+		if !ok || delay != expected {
+			t.Errorf("expected %v for %q, got %v", expected, value, delay)
+		}
+	}
+	for _, value := range []string{"", "-1", "soon"} {
+		if _, ok := parseRetryAfter(http.Header{"Retry-After": []string{value}}, now); ok {
+			t.Errorf("expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestRetryConnectionError(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		// drop the connection without a response
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Post().Resource("robots").Body([]byte("{}")).Do(context.Background()).Error(); err == nil || StatusCode(err) != 0 {
+		t.Errorf("expected the connection error, got %v", err)
+	}
+	if attempts := atomic.LoadInt32(&attempts); attempts != 1 {
+		t.Errorf("a POST request not marked idempotent must not be retried, got %d attempts", attempts)
+	}
+}
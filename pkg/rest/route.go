@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// routeKey is the context key of the route template of a request.
+type routeKey struct{}
+
+// Route returns the route template of the request, its path with the project and the
+// resource name replaced by placeholders, e.g. /api/v2.0/projects/{project_name}/repositories/{name},
+// to group the requests in traces and metrics without a label per resource. The numeric
+// segments and the digests of the suffix are replaced by {id} and {reference}.
+func (r *Request) Route() string {
+	p := r.pathPrefix
+	if r.projectSet && len(r.project) > 0 {
+		p = path.Join(p, "projects", "{project_name}")
+	}
+	if len(r.resource) != 0 {
+		p = path.Join(p, strings.ToLower(r.resource))
+	}
+	if len(r.resourceName) != 0 {
+		p = path.Join(p, "{name}")
+	}
+	if len(r.subresource) != 0 {
+		p = path.Join(p, r.subresource)
+	}
+	if len(r.subpath) != 0 {
+		segments := strings.Split(r.subpath, "/")
+		for i, segment := range segments {
+			switch {
+			case isNumeric(segment):
+				segments[i] = "{id}"
+			case strings.Contains(segment, ":"):
+				segments[i] = "{reference}"
+			}
+		}
+		p = path.Join(p, path.Join(segments...))
+	}
+	return p
+}
+
+// RouteFrom returns the route template of the request ctx is the context of, see
+// Request.Route. It is set for the transport middlewares, see WrapperFunc.
+func RouteFrom(ctx context.Context) (string, bool) {
+	route, ok := ctx.Value(routeKey{}).(string)
+	return route, ok
+}
+
+// withRoute returns a copy of ctx holding the route template of the request.
+func (r *Request) withRoute(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeKey{}, r.Route())
+}
+
+func isNumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
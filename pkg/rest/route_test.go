@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+	client, err := RESTClientFor(NewDefaultConfig("https://harbor.example.com", "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		request  *Request
+		expected string
+	}{
+		{client.Get().Resource("projects"), "/api/v2.0/projects"},
+		{client.Get().Resource("projects").Name("library"), "/api/v2.0/projects/{name}"},
+		{client.Get().Project("library").Resource("repositories").Name("nginx").Suffix("/artifacts/sha256:abc/tags"), "/api/v2.0/projects/{project_name}/repositories/{name}/artifacts/{reference}/tags"},
+		{client.Delete().Resource("robots").Name("3").Suffix("executions", "12"), "/api/v2.0/robots/{name}/executions/{id}"},
+	} {
+		if route := test.request.Route(); route != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, route)
+		}
+	}
+}
+
+func TestRouteFrom(t *testing.T) {
+	var route string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Use(func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			route, _ = RouteFrom(req.Context())
+			return rt.RoundTrip(req)
+		})
+	})
+	if err := client.Get().Resource("projects").Name("library").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if route != "/api/v2.0/projects/{name}" {
+		t.Errorf("unexpected route %s", route)
+	}
+}
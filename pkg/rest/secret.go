@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// redacted replaces secrets in formatted output.
+const redacted = "[REDACTED]"
+
+// Secret holds a credential such as a password, a token or a robot secret. It is redacted
+// when formatted with any fmt verb, logged or marshaled, so it can't leak through log
+// lines or error messages, and its memory can be zeroed once it is not needed anymore.
+// A nil *Secret is an empty secret.
+type Secret struct {
+	mu    sync.RWMutex
+	value []byte
+}
+
+// NewSecret returns a Secret holding value.
+func NewSecret(value string) *Secret {
+	return &Secret{value: []byte(value)}
+}
+
+// Reveal returns the value of the secret, it should only be used to authenticate a
+// request and never be logged.
+func (s *Secret) Reveal() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return string(s.value)
+}
+
+// Empty returns true if the secret has no value or has been zeroed.
+func (s *Secret) Empty() bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.value) == 0
+}
+
+// Zero overwrites the value of the secret, the secret is empty afterwards.
+func (s *Secret) Zero() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.value {
+		s.value[i] = 0
+	}
+	s.value = nil
+}
+
+// String implements fmt.Stringer.
+func (s *Secret) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (s *Secret) GoString() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter so that every verb is redacted.
+func (s *Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+// credentials authenticate the requests of a RESTClient. The Authorization header is
+// computed when a request is sent instead of being kept with the other headers.
+type credentials struct {
+	username    string
+	password    *Secret
+	bearerToken *Secret
+}
+
+// authorization returns the value of the Authorization header, empty if there are no credentials.
+func (c *credentials) authorization() string {
+	if c == nil {
+		return ""
+	}
+	switch {
+	case !c.bearerToken.Empty():
+		return "Bearer " + c.bearerToken.Reveal()
+	case c.username != "" && !c.password.Empty():
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password.Reveal()))
+	}
+	return ""
+}
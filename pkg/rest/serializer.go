@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Serializer encodes request bodies and decodes response bodies. Its methods have the
+// signatures of encoding/json, so compatible libraries such as jsoniter
+// (jsoniter.ConfigCompatibleWithStandardLibrary) can be plugged in through
+// ContentConfig.Serializer by high volume consumers.
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONSerializer is the default Serializer, backed by encoding/json.
+var JSONSerializer Serializer = jsonSerializer{}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// StrictJSONSerializer is a Serializer backed by encoding/json which fails to decode a
+// response with a field the model doesn't have, e.g. to catch in integration tests the
+// fields a new release of Harbor adds, which JSONSerializer silently drops.
+var StrictJSONSerializer Serializer = strictJSONSerializer{}
+
+type strictJSONSerializer struct{}
+
+func (strictJSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (strictJSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// like json.Unmarshal, the data must hold a single value
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// serializer returns the configured serializer or the default one.
+func (c ContentConfig) serializer() Serializer {
+	if c.Serializer != nil {
+		return c.Serializer
+	}
+	return JSONSerializer
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingSerializer wraps encoding/json and counts the calls made to it.
+type countingSerializer struct {
+	marshal, unmarshal int
+}
+
+func (s *countingSerializer) Marshal(v interface{}) ([]byte, error) {
+	s.marshal++
+	return json.Marshal(v)
+}
+
+func (s *countingSerializer) Unmarshal(data []byte, v interface{}) error {
+	s.unmarshal++
+	return json.Unmarshal(data, v)
+}
+
+type benchmarkArtifact struct {
+	ID        int64             `json:"id"`
+	Digest    string            `json:"digest"`
+	MediaType string            `json:"media_type"`
+	Size      int64             `json:"size"`
+	PushTime  string            `json:"push_time"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func TestContentConfigSerializer(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		received = string(body)
+		w.Write([]byte(`{"id":1,"digest":"sha256:abc"}`))
+	}))
+	defer server.Close()
+
+	serializer := &countingSerializer{}
+	config := NewDefaultConfig(server.URL, "", "")
+	config.Serializer = serializer
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var artifact benchmarkArtifact
+	err = client.Post().Resource("artifacts").Body(&benchmarkArtifact{ID: 1}).Do(context.Background()).Into(&artifact)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if serializer.marshal != 1 || serializer.unmarshal != 1 {
+		t.Fatalf("expected the configured serializer to be used, got %d marshal and %d unmarshal calls",
+			serializer.marshal, serializer.unmarshal)
+	}
+	if !strings.Contains(received, `"id":1`) || artifact.Digest != "sha256:abc" {
+		t.Fatalf("unexpected round trip: sent %s, got %#v", received, artifact)
+	}
+}
+
+func benchmarkArtifactList(n int) []byte {
+	artifacts := make([]benchmarkArtifact, n)
+	for i := range artifacts {
+		artifacts[i] = benchmarkArtifact{
+			ID:        int64(i),
+			Digest:    fmt.Sprintf("sha256:%064d", i),
+			MediaType: "application/vnd.oci.image.manifest.v1+json",
+			Size:      int64(i * 1024),
+			PushTime:  "2020-08-01T10:00:00.000Z",
+			Labels:    map[string]string{"team": "go-harbor"},
+		}
+	}
+	data, _ := json.Marshal(artifacts)
+	return data
+}
+
+// benchmarkInto decodes a page of artifacts through Result.Into with the given serializer.
+func benchmarkInto(b *testing.B, serializer Serializer) {
+	result := Result{body: benchmarkArtifactList(100), decoder: serializer}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var artifacts []benchmarkArtifact
+		if err := result.Into(&artifacts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResultIntoEncodingJSON(b *testing.B) {
+	data := benchmarkArtifactList(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var artifacts []benchmarkArtifact
+		if err := json.Unmarshal(data, &artifacts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResultIntoDefaultSerializer(b *testing.B) {
+	benchmarkInto(b, ContentConfig{}.serializer())
+}
+
+func BenchmarkResultIntoCustomSerializer(b *testing.B) {
+	benchmarkInto(b, &countingSerializer{})
+}
+
+func TestStrictJSONSerializer(t *testing.T) {
+	var artifact benchmarkArtifact
+	if err := StrictJSONSerializer.Unmarshal([]byte(`{"id":1,"digest":"sha256:abc"}`), &artifact); err != nil || artifact.ID != 1 {
+		t.Fatalf("unexpected result %#v, %v", artifact, err)
+	}
+	err := StrictJSONSerializer.Unmarshal([]byte(`{"id":1,"annotations":{}}`), &artifact)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "annotations"`) {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+	if err := JSONSerializer.Unmarshal([]byte(`{"id":1,"annotations":{}}`), &artifact); err != nil {
+		t.Errorf("the default serializer should ignore unknown fields, got %v", err)
+	}
+	if err := StrictJSONSerializer.Unmarshal([]byte(`{"id":1} {}`), &artifact); err == nil {
+		t.Error("expected an error for trailing data")
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"k8s.io/klog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// csrfTokenHeader is the header Harbor returns the CSRF token of a session in, and expects
+// it back in for the mutating requests.
+const csrfTokenHeader = "X-Harbor-Csrf-Token"
+
+// session authenticates the requests with a session of the UI of Harbor, shared by the
+// clients of a Config.
+type session struct {
+	base     *url.URL
+	username string
+	password *Secret
+	jar      http.CookieJar
+	// owner is the config the session was created for, a copy of the config, e.g. with
+	// the credentials of a robot account, doesn't share it
+	owner *Config
+
+	mu       sync.Mutex
+	rt       http.RoundTripper
+	loggedIn bool
+	closed   bool
+	token    string
+}
+
+func newSession(base *url.URL, username string, password *Secret) *session {
+	jar, _ := cookiejar.New(nil)
+	return &session{base: base, username: username, password: password, jar: jar}
+}
+
+// SessionTransport returns a middleware authenticating the requests with a session of
+// the UI of Harbor, for the APIs only reachable with one. It logs in with /c/login on the
+// first request, and again once the session expired, keeps the sid cookie and attaches
+// the CSRF token Harbor returns to the mutating requests.
+func SessionTransport(base *url.URL, username string, password *Secret) WrapperFunc {
+	return newSession(base, username, password).wrap
+}
+
+func (s *session) wrap(rt http.RoundTripper) http.RoundTripper {
+	s.mu.Lock()
+	s.rt = rt
+	s.mu.Unlock()
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if rejected, err := s.login(req.Context(), rt, false); err != nil || rejected != nil {
+			return rejected, err
+		}
+		if isMutating(req.Method) && s.csrfToken() == "" {
+			if err := s.fetchToken(req.Context(), rt); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := s.send(rt, req)
+		if err != nil || !rewindable(req) {
+			return resp, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			// the session expired
+			var rejected *http.Response
+			if rejected, err = s.login(req.Context(), rt, true); rejected != nil {
+				resp.Body.Close()
+				return rejected, nil
+			}
+		case resp.StatusCode == http.StatusForbidden && isMutating(req.Method):
+			// the CSRF token expired
+			err = s.fetchToken(req.Context(), rt)
+		default:
+			return resp, nil
+		}
+		if err != nil {
+			return resp, nil
+		}
+		if req, err = rewind(req); err != nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+		return s.send(rt, req)
+	})
+}
+
+// send sends req with the cookies and the CSRF token of the session.
+func (s *session) send(rt http.RoundTripper, req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	for _, cookie := range s.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	if token := s.csrfToken(); token != "" && isMutating(req.Method) {
+		req.Header.Set(csrfTokenHeader, token)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	s.jar.SetCookies(req.URL, resp.Cookies())
+	if token := resp.Header.Get(csrfTokenHeader); token != "" {
+		s.mu.Lock()
+		s.token = token
+		s.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// login logs in, unless logged in already and not forced to. The response of a rejected
+// login is returned, to be the one of the request.
+func (s *session) login(ctx context.Context, rt http.RoundTripper, force bool) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loggedIn && !force {
+		return nil, nil
+	}
+	if s.closed || s.password.Empty() {
+		return nil, fmt.Errorf("login as %s error: the credentials of the session have been zeroed", s.username)
+	}
+	form := url.Values{"principal": {s.username}, "password": {s.password.Reveal()}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url("/c/login"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.loggedIn = false
+		return resp, nil
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	s.jar.SetCookies(req.URL, resp.Cookies())
+	s.loggedIn = true
+	s.token = resp.Header.Get(csrfTokenHeader)
+	return nil, nil
+}
+
+// fetchToken gets a CSRF token, returned with the responses of the GET requests.
+func (s *session) fetchToken(ctx context.Context, rt http.RoundTripper) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url(DefaultVersionApiPath, "systeminfo"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.send(rt, req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
+
+// close logs the session out of Harbor, so that its cookie is no longer valid, and makes
+// the requests fail to log in again afterwards. A failure to log out is only logged, the
+// cookie then expires with the session on the server.
+func (s *session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loggedIn && s.rt != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(DefaultTimeOut)*time.Second)
+		defer cancel()
+		if err := s.logout(ctx); err != nil {
+			klog.Warningf("Log out the session of %s error: %v", s.username, err)
+		}
+	}
+	s.closed = true
+	s.loggedIn = false
+	s.token = ""
+}
+
+// logout logs the session out with /c/log_out, the caller must hold s.mu.
+func (s *session) logout(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url("/c/log_out"), nil)
+	if err != nil {
+		return err
+	}
+	for _, cookie := range s.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	resp, err := s.rt.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *session) csrfToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+func (s *session) url(elem ...string) string {
+	u := *s.base
+	u.Path = path.Join(append([]string{"/", s.base.Path}, elem...)...)
+	return u.String()
+}
+
+// rewindable returns true if the body of req can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	req = req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	return req, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeUI is a Harbor accepting the requests of a UI session only.
+type fakeUI struct {
+	logins  int
+	logouts int
+	session string
+	token   string
+}
+
+func (f *fakeUI) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/c/log_out" {
+		if cookie, err := req.Cookie("sid"); err == nil && cookie.Value == f.session {
+			f.logouts++
+			f.session = ""
+		}
+		return
+	}
+	if req.URL.Path == "/c/login" {
+		if req.FormValue("principal") != "admin" || req.FormValue("password") != "Harbor12345" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.logins++
+		f.session = fmt.Sprintf("session-%d", f.logins)
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: f.session, Path: "/"})
+		return
+	}
+	if cookie, err := req.Cookie("sid"); err != nil || cookie.Value != f.session || req.Header.Get("Authorization") != "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if req.Method == http.MethodGet {
+		w.Header().Set("X-Harbor-Csrf-Token", f.token)
+		w.Write([]byte("{}"))
+		return
+	}
+	if req.Header.Get("X-Harbor-Csrf-Token") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestSession(t *testing.T) {
+	ui := &fakeUI{token: "token-1"}
+	server := httptest.NewServer(ui)
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Session = true
+	projects, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := projects.Get().Resource("projects").Do(ctx).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Get().Resource("users").Do(ctx).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if ui.logins != 1 {
+		t.Errorf("expected the clients to share a single login, got %d", ui.logins)
+	}
+	if err := projects.Post().Resource("projects").Body([]byte(`{"project_name":"a"}`)).Do(ctx).Error(); err != nil {
+		t.Errorf("expected the CSRF token to be sent, got %v", err)
+	}
+
+	// the session expires
+	ui.session = "expired"
+	if err := projects.Get().Resource("projects").Do(ctx).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if ui.logins != 2 {
+		t.Errorf("expected a new login once the session expired, got %d logins", ui.logins)
+	}
+
+	// the CSRF token rotates
+	ui.token = "token-2"
+	if err := projects.Delete().Resource("projects").Name("a").Do(ctx).Error(); err != nil {
+		t.Errorf("expected the CSRF token to be renewed, got %v", err)
+	}
+}
+
+func TestSessionLoginError(t *testing.T) {
+	server := httptest.NewServer(&fakeUI{})
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "wrong")
+	config.Session = true
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); !IsUnauthorized(err) {
+		t.Errorf("expected the login to be rejected, got %v", err)
+	}
+}
+
+func TestSessionDebugMasksPassword(t *testing.T) {
+	server := httptest.NewServer(&fakeUI{token: "token-1"})
+	defer server.Close()
+
+	var dump bytes.Buffer
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Session = true
+	config.Debug = &dump
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump.String(), "/c/login") {
+		t.Fatalf("expected the login to be dumped, got %s", dump.String())
+	}
+	if strings.Contains(dump.String(), "Harbor12345") || !strings.Contains(dump.String(), "password="+redacted) {
+		t.Errorf("expected the password of the login to be masked, got %s", dump.String())
+	}
+}
+
+func TestSessionZeroLogsOut(t *testing.T) {
+	ui := &fakeUI{token: "token-1"}
+	server := httptest.NewServer(ui)
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Session = true
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+	config.Zero()
+	if ui.logouts != 1 || ui.session != "" {
+		t.Fatalf("expected the session to be logged out of Harbor, got %d log outs", ui.logouts)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err == nil {
+		t.Error("expected the requests to fail once the config is zeroed")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Stream formats and executes the request and returns the body of a successful response
+// without buffering it, e.g. to download a GC log or a scan report export of hundreds of
+// MB. The caller must close the body. The timeout of the request, see Request.Timeout, and
+// ctx bound the whole download, the body can't be read once they are done. The error
+// of an unsuccessful response is the same as the one of Do.
+func (r *Request) Stream(ctx context.Context) (io.ReadCloser, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cancel := func() {}
+	if r.timeout > 0 {
+		// the timeout of doRequest ends when the response is received, the body would be cut
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		r.timeout = 0
+	}
+	r.ctx = ctx
+	if err := r.tryThrottle(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	var result Result
+	err := r.request(func(req *http.Request, resp *http.Response) {
+		if resp.StatusCode < http.StatusOK || resp.StatusCode > http.StatusPartialContent {
+			result = r.transformResponse(resp, req)
+			return
+		}
+		// the body is handed over to the caller instead of being drained and closed
+		body = resp.Body
+		resp.Body = ioutil.NopCloser(bytes.NewReader(nil))
+	})
+	if err == nil {
+		err = result.Error()
+	}
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		cancel()
+		return nil, err
+	}
+	return &streamBody{ReadCloser: body, cancel: cancel}, nil
+}
+
+// StreamTo streams the body of a successful response to w, see Stream, and returns the
+// number of bytes written.
+func (r *Request) StreamTo(ctx context.Context, w io.Writer) (int64, error) {
+	body, err := r.Stream(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.Copy(w, body)
+}
+
+// streamBody releases the timeout of a streamed request when its body is closed.
+type streamBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *streamBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	content := strings.Repeat("gc log line\n", 100000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/missing") {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"code":"NOT_FOUND"}]}`))
+			return
+		}
+		w.Write([]byte(content[:len(content)/2]))
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(content[len(content)/2:]))
+	}))
+	defer server.Close()
+
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := client.Get().Resource("system").Suffix("gc", "1", "log").Timeout(time.Second).Stream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatalf("the body should be readable after Stream returned, got %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("expected %d bytes, got %d", len(content), len(data))
+	}
+
+	var out bytes.Buffer
+	if n, err := client.Get().Resource("system").Suffix("gc", "1", "log").StreamTo(context.Background(), &out); err != nil || n != int64(len(content)) || out.String() != content {
+		t.Errorf("expected %d bytes to be written, got %d, %v", len(content), n, err)
+	}
+
+	if _, err := client.Get().Resource("system").Suffix("missing").Stream(context.Background()); err == nil || !strings.Contains(err.Error(), "NOT_FOUND") {
+		t.Errorf("expected the error of the server, got %v", err)
+	}
+}
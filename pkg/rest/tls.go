@@ -0,0 +1,280 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"k8s.io/klog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TLSHandshakeError is returned when the TLS handshake with the server fails, e.g. because
+// the server rejected the client certificate or the server certificate is not trusted.
+// These errors are not retried since retrying does not help.
+type TLSHandshakeError struct {
+	Host string
+	Err  error
+}
+
+// Error returns a textual description of 'e'.
+func (e *TLSHandshakeError) Error() string {
+	return fmt.Sprintf("tls handshake with %s failed: %v", e.Host, e.Err)
+}
+
+// Unwrap returns the underlying handshake error.
+func (e *TLSHandshakeError) Unwrap() error {
+	return e.Err
+}
+
+// IsTLSHandshakeError returns true if err is a *TLSHandshakeError.
+func IsTLSHandshakeError(err error) bool {
+	var handshakeErr *TLSHandshakeError
+	return errors.As(err, &handshakeErr)
+}
+
+// isTLSHandshakeFailure returns true if err, returned by http.Client.Do, was caused by
+// the TLS handshake.
+func isTLSHandshakeFailure(err error) bool {
+	var (
+		recordErr     tls.RecordHeaderError
+		authorityErr  x509.UnknownAuthorityError
+		invalidErr    x509.CertificateInvalidError
+		hostnameErr   x509.HostnameError
+		constraintErr x509.ConstraintViolationError
+		rootsErr      x509.SystemRootsError
+		opErr         *net.OpError
+	)
+	if IsCertificatePinError(err) || isCertificateVerificationError(err) {
+		return true
+	}
+	switch {
+	case errors.As(err, &recordErr), errors.As(err, &authorityErr),
+		errors.As(err, &invalidErr), errors.As(err, &hostnameErr),
+		errors.As(err, &constraintErr), errors.As(err, &rootsErr):
+		return true
+	}
+	// As a last resort, alerts sent by the server, e.g. "remote error: tls: bad certificate",
+	// are matched by their text since crypto/tls doesn't export their type. Only the error
+	// of the "remote error" operation is inspected, not the text of err as a whole.
+	if errors.As(err, &opErr) && opErr.Op == "remote error" && opErr.Err != nil {
+		return strings.HasPrefix(opErr.Err.Error(), "tls: ")
+	}
+	return false
+}
+
+// TLSConfigFor returns the tls.Config for the TLS settings of config, or nil if config
+// has no TLS settings. A client certificate loaded from CertFile and KeyFile is reloaded
+// when the files change, so that rotated certificates are picked up without restarting.
+func TLSConfigFor(config *Config) (*tls.Config, error) {
+	c := config.TLSClientConfig
+	if config.CertificatePins == nil && !c.Insecure && c.ServerName == "" && len(c.NextProtos) == 0 &&
+		c.CAFile == "" && len(c.CAData) == 0 &&
+		c.CertFile == "" && len(c.CertData) == 0 && c.KeyFile == "" && len(c.KeyData) == 0 {
+		return nil, nil
+	}
+	if c.Insecure && (c.CAFile != "" || len(c.CAData) > 0) {
+		return nil, fmt.Errorf("specifying a root certificates file with the insecure flag is not allowed")
+	}
+	if config.CertificatePins != nil && (c.Insecure || c.CAFile != "" || len(c.CAData) > 0) {
+		return nil, fmt.Errorf("certificate pinning can't be combined with the insecure flag or root certificates")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.Insecure,
+		ServerName:         c.ServerName,
+		NextProtos:         c.NextProtos,
+	}
+
+	if config.CertificatePins != nil {
+		host, err := DefaultServerURL(config.APIPath)
+		if err != nil {
+			return nil, err
+		}
+		// the pin replaces the verification of the chain
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = verifyPinnedCertificate(host.Hostname(), config.CertificatePins)
+	}
+
+	caData := c.CAData
+	if len(caData) == 0 && c.CAFile != "" {
+		data, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file %s error: %v", c.CAFile, err)
+		}
+		caData = data
+	}
+	if len(caData) > 0 {
+		pool, err := certPoolFor(caData)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case len(c.CertData) > 0 || len(c.KeyData) > 0:
+		cert, err := tls.X509KeyPair(c.CertData, c.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate error: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case c.CertFile != "" || c.KeyFile != "":
+		reloader, err := newCertificateReloader(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	}
+	return tlsConfig, nil
+}
+
+func certPoolFor(caData []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no valid PEM encoded CA certificate found")
+	}
+	return pool, nil
+}
+
+// certificateReloader serves a client certificate read from files, reloading it when
+// the modification time of either file changes.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both a client certificate file and key file must be specified")
+	}
+	r := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certificateReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// reload reads the key pair, the caller must hold r.mu or be the constructor.
+func (r *certificateReloader) reload() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return fmt.Errorf("stat client certificate error: %v", err)
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load client certificate error: %v", err)
+	}
+	r.cert, r.certMod, r.keyMod = &cert, certMod, keyMod
+	return nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate. If the files changed
+// but can't be loaded, e.g. while they are being rotated, the previous certificate is used.
+func (r *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	certMod, keyMod, err := r.modTimes()
+	if err == nil && (!certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod)) {
+		if err := r.reload(); err != nil {
+			klog.Warningf("Keeping the previous client certificate: %v", err)
+		} else {
+			klog.V(2).Infof("Reloaded client certificate from %s", r.certFile)
+		}
+	}
+	return r.cert, nil
+}
+
+// VerifyServerChain connects to the host of config, using its client certificate if any,
+// and verifies that the certificate chain presented by the server is issued by one of the
+// PEM encoded certificates of caData. The presented chain is returned, leaf first, so
+// callers can inspect it further.
+func VerifyServerChain(config *Config, caData []byte) ([]*x509.Certificate, error) {
+	roots, err := certPoolFor(caData)
+	if err != nil {
+		return nil, err
+	}
+	hostURL, err := DefaultServerURL(config.APIPath)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	// the chain is verified below against the expected roots instead of the configured ones
+	tlsConfig.InsecureSkipVerify = true
+	serverName := tlsConfig.ServerName
+	if serverName == "" {
+		serverName = hostURL.Hostname()
+	}
+	address := hostURL.Host
+	if hostURL.Port() == "" {
+		address = net.JoinHostPort(hostURL.Hostname(), "443")
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", address, tlsConfig)
+	if err != nil {
+		return nil, &TLSHandshakeError{Host: address, Err: err}
+	}
+	defer conn.Close()
+
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("server %s presented no certificate", address)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return chain, fmt.Errorf("certificate chain presented by %s does not match the expected CA: %v", address, err)
+	}
+	return chain, nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCertificate creates a certificate signed by parent, or self signed if parent is nil.
+func newTestCertificate(t *testing.T, serial int64, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "go-harbor test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return cert, key,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func serverCAData(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func TestMutualTLSReloadsClientCertificate(t *testing.T) {
+	ca, caKey, _, _ := newTestCertificate(t, 1, nil, nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	var lastSerial int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lastSerial = req.TLS.PeerCertificates[0].SerialNumber.Int64()
+		w.Write([]byte("{}"))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	// without a client certificate the handshake is rejected
+	config := NewDefaultConfig(server.URL, "", "")
+	config.CAData = serverCAData(server)
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Get().Resource("projects").Do(context.Background()).Error()
+	if !IsTLSHandshakeError(err) {
+		t.Fatalf("expected a TLS handshake error, got %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "mtls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config.CertFile = filepath.Join(dir, "tls.crt")
+	config.KeyFile = filepath.Join(dir, "tls.key")
+	writeKeyPair := func(serial int64, mod time.Time) {
+		_, _, certPEM, keyPEM := newTestCertificate(t, serial, ca, caKey)
+		if err := ioutil.WriteFile(config.CertFile, certPEM, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(config.KeyFile, keyPEM, 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(config.CertFile, mod, mod)
+		os.Chtimes(config.KeyFile, mod, mod)
+	}
+	writeKeyPair(2, time.Now().Add(-time.Minute))
+
+	client, err = RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastSerial != 2 {
+		t.Fatalf("expected certificate 2, got %d", lastSerial)
+	}
+
+	// rotate the certificate, new connections must present it
+	writeKeyPair(3, time.Now())
+	client.Client.Transport.(*http.Transport).CloseIdleConnections()
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastSerial != 3 {
+		t.Fatalf("expected rotated certificate 3, got %d", lastSerial)
+	}
+}
+
+func TestVerifyServerChain(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+	config := NewDefaultConfig(server.URL, "", "")
+	config.ServerName = "example.com"
+
+	chain, err := VerifyServerChain(config, serverCAData(server))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chain) == 0 {
+		t.Fatalf("expected the presented chain")
+	}
+
+	_, _, otherCA, _ := newTestCertificate(t, 1, nil, nil)
+	if _, err := VerifyServerChain(config, otherCA); err == nil {
+		t.Fatalf("expected a chain mismatch error")
+	}
+}
+
+func TestIsTLSHandshakeFailure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, untrusted := http.Get(server.URL)
+	if untrusted == nil {
+		t.Fatal("expected the certificate of the test server to be untrusted")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"untrusted server", untrusted, true},
+		{"unknown authority", &url.Error{Op: "Get", URL: server.URL, Err: x509.UnknownAuthorityError{}}, true},
+		{"hostname mismatch", fmt.Errorf("dial error: %w", x509.HostnameError{Host: "harbor.example.com"}), true},
+		{"record header", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}, true},
+		{"remote alert", &url.Error{Op: "Get", URL: server.URL, Err: &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}}, true},
+		{"connection refused", &url.Error{Op: "Get", URL: server.URL, Err: &net.OpError{Op: "dial", Err: errors.New("connect: connection refused")}}, false},
+		{"unrelated text", errors.New("parse settings error: tls: unknown option"), false},
+	}
+	for _, test := range tests {
+		if got := isTLSHandshakeFailure(test.err); got != test.want {
+			t.Errorf("%s: expected %v, got %v for %v", test.name, test.want, got, test.err)
+		}
+	}
+}
@@ -15,12 +15,108 @@ See the License for the specific language governing permissions and
 
 package rest
 
-import "net/http"
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
 
-func TransportFor() *http.Transport {
+// Defaults of the connection pool of the transport.
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 100
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// TransportFor returns the transport used to talk to the server described by config,
+// with the TLS and connection pool settings of config applied.
+func TransportFor(config *Config) (*http.Transport, error) {
 	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.MaxIdleConns = 100
+	t.MaxIdleConns = orDefault(config.MaxIdleConns, DefaultMaxIdleConns)
 	t.MaxConnsPerHost = 100
-	t.MaxIdleConnsPerHost = 100
-	return t
+	t.MaxIdleConnsPerHost = orDefault(config.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost)
+	if t.MaxIdleConnsPerHost > t.MaxConnsPerHost {
+		t.MaxConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	t.IdleConnTimeout = orDefault(config.IdleConnTimeout, DefaultIdleConnTimeout)
+	t.TLSHandshakeTimeout = orDefault(config.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout)
+	tlsConfig, err := TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig
+	}
+	if config.Proxy != nil {
+		t.Proxy = config.Proxy
+	}
+	t.DisableCompression = config.DisableCompression
+	if config.DisableHTTP2 {
+		disableHTTP2(t)
+	}
+	return t, nil
+}
+
+// disableHTTP2 restricts t to HTTP/1.1, a non nil TLSNextProto keeping the transport from
+// negotiating h2.
+func disableHTTP2(t *http.Transport) {
+	t.ForceAttemptHTTP2 = false
+	t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	if t.TLSClientConfig == nil {
+		return
+	}
+	var protos []string
+	for _, proto := range t.TLSClientConfig.NextProtos {
+		if proto != "h2" {
+			protos = append(protos, proto)
+		}
+	}
+	t.TLSClientConfig.NextProtos = protos
+}
+
+// orDefault returns value, or def if value isn't positive.
+func orDefault[T int | time.Duration](value, def T) T {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// WrapperFunc wraps an http.RoundTripper when a new transport is created for a client,
+// allowing per connection behavior to be injected.
+type WrapperFunc func(rt http.RoundTripper) http.RoundTripper
+
+// Wrappers accepts any number of wrappers and returns a wrapper function that is the
+// equivalent of calling each of them in order. Nil values are ignored, which makes
+// this function convenient for incrementally wrapping a function.
+func Wrappers(fns ...WrapperFunc) WrapperFunc {
+	if len(fns) == 0 {
+		return nil
+	}
+	// optimize the common case of wrapping a possibly nil transport wrapper
+	// with an additional wrapper
+	if len(fns) == 2 && fns[0] == nil {
+		return fns[1]
+	}
+	return func(rt http.RoundTripper) http.RoundTripper {
+		base := rt
+		for _, fn := range fns {
+			if fn != nil {
+				rt = fn(rt)
+			}
+		}
+		if rt == nil {
+			return base
+		}
+		return rt
+	}
+}
+
+// RoundTripperFunc is an http.RoundTripper calling itself, e.g. to write a WrapperFunc.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
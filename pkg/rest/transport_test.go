@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tagRequests returns a middleware appending tag to the X-Middlewares header of the requests.
+func tagRequests(tag string) WrapperFunc {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Add("X-Middlewares", tag)
+			return rt.RoundTrip(req)
+		})
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	var tags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tags = req.Header["X-Middlewares"]
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.Wrap(tagRequests("config-1"))
+	config.Wrap(tagRequests("config-2"))
+	client, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Use()
+	client.Use(tagRequests("use-1"), tagRequests("use-2"))
+	if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the outermost middleware sees the request first
+	if expected := []string{"use-2", "use-1", "config-2", "config-1"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected the middlewares to run in the order %v, got %v", expected, tags)
+	}
+}
+
+func TestTransportForConnectionPool(t *testing.T) {
+	transport, err := TransportFor(&Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.MaxIdleConns != DefaultMaxIdleConns || transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost ||
+		transport.IdleConnTimeout != DefaultIdleConnTimeout || transport.TLSHandshakeTimeout != DefaultTLSHandshakeTimeout {
+		t.Errorf("unexpected default connection pool: %d %d %v %v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost,
+			transport.IdleConnTimeout, transport.TLSHandshakeTimeout)
+	}
+
+	transport, err = TransportFor(&Config{
+		MaxIdleConns:        500,
+		MaxIdleConnsPerHost: 200,
+		IdleConnTimeout:     time.Minute,
+		TLSHandshakeTimeout: 3 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.MaxIdleConns != 500 || transport.MaxIdleConnsPerHost != 200 || transport.MaxConnsPerHost != 200 ||
+		transport.IdleConnTimeout != time.Minute || transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("unexpected connection pool: %d %d %d %v %v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost,
+			transport.MaxConnsPerHost, transport.IdleConnTimeout, transport.TLSHandshakeTimeout)
+	}
+}
+
+func TestTransportForHTTP2(t *testing.T) {
+	var proto string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proto = req.Proto
+		w.Write([]byte("{}"))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	for _, disabled := range []bool{false, true} {
+		config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+		config.CAData = serverCAData(server)
+		config.NextProtos = []string{"h2", "http/1.1"}
+		config.DisableHTTP2 = disabled
+		client, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := map[bool]string{false: "HTTP/2.0", true: "HTTP/1.1"}[disabled]; proto != expected {
+			t.Errorf("expected %s with HTTP/2 disabled %v, got %s", expected, disabled, proto)
+		}
+	}
+}
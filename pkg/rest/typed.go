@@ -0,0 +1,39 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import "context"
+
+// Do executes the request and decodes the response into a T, e.g.
+//
+//	project, err := rest.Do[models.Project](ctx, client.Get().Resource("projects").Name("library"))
+//
+// The error is the one of Result.Into.
+func Do[T any](ctx context.Context, r *Request) (T, error) {
+	var result T
+	err := r.Do(ctx).Into(&result)
+	return result, err
+}
+
+// DoList executes a list request and decodes the items of the response into a []T, the
+// response being either an array or an object wrapping it, see Result.IntoList.
+func DoList[T any](ctx context.Context, r *Request) ([]T, error) {
+	var result []T
+	if err := r.Do(ctx).IntoList(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
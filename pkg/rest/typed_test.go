@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDoTyped(t *testing.T) {
+	type project struct {
+		Name string `json:"name"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v2.0/projects":
+			w.Write([]byte(`[{"name":"a"},{"name":"b"}]`))
+		case "/api/v2.0/audit-logs":
+			w.Write([]byte(`{"total":1,"items":[{"name":"c"}]}`))
+		case "/api/v2.0/projects/a":
+			w.Write([]byte(`{"name":"a"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client, err := RESTClientFor(NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if p, err := Do[project](ctx, client.Get().Resource("projects").Name("a")); err != nil || p.Name != "a" {
+		t.Errorf("unexpected project %v, %v", p, err)
+	}
+	if p, err := Do[*project](ctx, client.Get().Resource("projects").Name("a")); err != nil || p == nil || p.Name != "a" {
+		t.Errorf("unexpected project %v, %v", p, err)
+	}
+	if _, err := Do[project](ctx, client.Get().Resource("projects").Name("missing")); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if list, err := DoList[project](ctx, client.Get().Resource("projects")); err != nil || !reflect.DeepEqual(list, []project{{"a"}, {"b"}}) {
+		t.Errorf("unexpected projects %v, %v", list, err)
+	}
+	if list, err := DoList[project](ctx, client.Get().Resource("audit-logs")); err != nil || !reflect.DeepEqual(list, []project{{"c"}}) {
+		t.Errorf("unexpected projects %v, %v", list, err)
+	}
+	if list, err := DoList[project](ctx, client.Get().Resource("missing")); list != nil || !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v, %v", list, err)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Version is the version of go-harbor, reported in the User-Agent of the requests.
+const Version = "2.0.0"
+
+// DefaultUserAgent returns the User-Agent of the requests when Config.UserAgent is not set,
+// e.g. go-harbor/2.0.0 (linux/amd64), so that the requests of the SDK can be told apart in
+// the audit logs of Harbor.
+func DefaultUserAgent() string {
+	return "go-harbor/" + Version + " (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
+}
+
+// userAgent returns the User-Agent of the requests of config, the product of the caller, if
+// any, followed by the one of the SDK.
+func userAgent(config *Config) string {
+	if ua := strings.TrimSpace(config.UserAgent); ua != "" {
+		return ua + " " + DefaultUserAgent()
+	}
+	return DefaultUserAgent()
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.Header.Get("User-Agent")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		userAgent string
+		expected  string
+	}{
+		{"", DefaultUserAgent()},
+		{"my-operator/1.2.0", "my-operator/1.2.0 " + DefaultUserAgent()},
+	} {
+		config := NewDefaultConfig(server.URL, "admin", "Harbor12345")
+		config.UserAgent = test.userAgent
+		client, err := RESTClientFor(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Get().Resource("projects").Do(context.Background()).Error(); err != nil {
+			t.Fatal(err)
+		}
+		if userAgent != test.expected {
+			t.Errorf("expected the User-Agent %q, got %q", test.expected, userAgent)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// reserver is a rate limiter whose tokens are reserved ahead of time.
+type reserver interface {
+	// reserve reserves a token at most maxDelay ahead, returning the wait until it is
+	// available and whether it was reserved.
+	reserve(now time.Time, maxDelay time.Duration) (time.Duration, bool)
+	// cancel gives back the token reserved for at, when it can still be used by another
+	// request.
+	cancel(at time.Time)
+}
+
+type leakyBucketRateLimiter struct {
+	mu       sync.Mutex
+	clock    Clock
+	qps      float32
+	interval time.Duration
+	// next is the time the next token leaks from the bucket
+	next time.Time
+}
+
+// NewLeakyBucketRateLimiter creates a rate limiter which implements a leaky bucket
+// approach. The requests are spaced evenly at a rate of 'qps', without any burst, which
+// keeps a client below a rate limit enforced over short windows.
+func NewLeakyBucketRateLimiter(qps float32) RateLimiter {
+	return NewLeakyBucketRateLimiterWithClock(qps, realClock{})
+}
+
+// NewLeakyBucketRateLimiterWithClock is identical to NewLeakyBucketRateLimiter but allows
+// an injectable clock, for testing. It panics if qps isn't positive.
+func NewLeakyBucketRateLimiterWithClock(qps float32, c Clock) RateLimiter {
+	if !(qps > 0) {
+		panic(fmt.Sprintf("flowcontrol: non-positive qps %v for the leaky bucket rate limiter", qps))
+	}
+	return &leakyBucketRateLimiter{
+		clock:    c,
+		qps:      qps,
+		interval: time.Duration(float64(time.Second) / float64(qps)),
+	}
+}
+
+func (l *leakyBucketRateLimiter) reserve(now time.Time, maxDelay time.Duration) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	next := l.next
+	if next.Before(now) {
+		next = now
+	}
+	delay := next.Sub(now)
+	if delay > maxDelay {
+		return delay, false
+	}
+	l.next = next.Add(l.interval)
+	return delay, true
+}
+
+func (l *leakyBucketRateLimiter) cancel(at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the later reservations keep their slot, the token is only given back when it is the
+	// last one reserved
+	if l.next.Equal(at.Add(l.interval)) {
+		l.next = at
+	}
+}
+
+func (l *leakyBucketRateLimiter) TryAccept() bool {
+	_, ok := l.reserve(l.clock.Now(), 0)
+	return ok
+}
+
+// Accept will block until a token becomes available
+func (l *leakyBucketRateLimiter) Accept() {
+	delay, _ := l.reserve(l.clock.Now(), math.MaxInt64)
+	l.clock.Sleep(delay)
+}
+
+// Wait will block until a token becomes available or ctx is done
+func (l *leakyBucketRateLimiter) Wait(ctx context.Context) error {
+	return wait(ctx, l.clock.Now(), l)
+}
+
+func (l *leakyBucketRateLimiter) Stop() {
+}
+
+func (l *leakyBucketRateLimiter) QPS() float32 {
+	return l.qps
+}
+
+type fixedWindowRateLimiter struct {
+	mu     sync.Mutex
+	clock  Clock
+	limit  int
+	window time.Duration
+	// start is the start of the window the last token was taken in, count the number of
+	// tokens taken in it
+	start time.Time
+	count int
+}
+
+// NewFixedWindowRateLimiter creates a rate limiter which allows up to 'limit' requests in
+// each window of duration 'window', the windows being aligned on multiples of 'window',
+// like the rate limits counting the requests per minute.
+func NewFixedWindowRateLimiter(limit int, window time.Duration) RateLimiter {
+	return NewFixedWindowRateLimiterWithClock(limit, window, realClock{})
+}
+
+// NewFixedWindowRateLimiterWithClock is identical to NewFixedWindowRateLimiter but allows
+// an injectable clock, for testing. It panics if limit or window isn't positive.
+func NewFixedWindowRateLimiterWithClock(limit int, window time.Duration, c Clock) RateLimiter {
+	if limit <= 0 {
+		panic(fmt.Sprintf("flowcontrol: non-positive limit %d for the fixed window rate limiter", limit))
+	}
+	if window <= 0 {
+		panic(fmt.Sprintf("flowcontrol: non-positive window %v for the fixed window rate limiter", window))
+	}
+	return &fixedWindowRateLimiter{
+		clock:  c,
+		limit:  limit,
+		window: window,
+	}
+}
+
+func (f *fixedWindowRateLimiter) reserve(now time.Time, maxDelay time.Duration) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	start, count := f.start, f.count
+	if current := now.Truncate(f.window); start.Before(current) {
+		start, count = current, 0
+	}
+	if count >= f.limit {
+		// the window is full, the token is taken in the next one
+		start, count = start.Add(f.window), 0
+	}
+	var delay time.Duration
+	if start.After(now) {
+		delay = start.Sub(now)
+	}
+	if delay > maxDelay {
+		return delay, false
+	}
+	f.start, f.count = start, count+1
+	return delay, true
+}
+
+func (f *fixedWindowRateLimiter) cancel(at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// the token is lost once its window is over
+	if f.start.Equal(at.Truncate(f.window)) && f.count > 0 {
+		f.count--
+	}
+}
+
+func (f *fixedWindowRateLimiter) TryAccept() bool {
+	_, ok := f.reserve(f.clock.Now(), 0)
+	return ok
+}
+
+// Accept will block until a token becomes available
+func (f *fixedWindowRateLimiter) Accept() {
+	delay, _ := f.reserve(f.clock.Now(), math.MaxInt64)
+	f.clock.Sleep(delay)
+}
+
+// Wait will block until a token becomes available or ctx is done
+func (f *fixedWindowRateLimiter) Wait(ctx context.Context) error {
+	return wait(ctx, f.clock.Now(), f)
+}
+
+func (f *fixedWindowRateLimiter) Stop() {
+}
+
+func (f *fixedWindowRateLimiter) QPS() float32 {
+	return float32(float64(f.limit) / f.window.Seconds())
+}
+
+// wait reserves a token of r and waits until it is available. It fails without reserving
+// a token if it wouldn't be available before the deadline of ctx, and gives the token back
+// if ctx is done while waiting.
+func wait(ctx context.Context, now time.Time, r reserver) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	maxDelay := time.Duration(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxDelay = deadline.Sub(now)
+	}
+	delay, ok := r.reserve(now, maxDelay)
+	if !ok {
+		return fmt.Errorf("rate: Wait(n=1) would exceed context deadline")
+	}
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.cancel(now.Add(delay))
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the class of a request sharing the budget of a priority rate limiter.
+type Priority int
+
+const (
+	// PriorityInteractive is the priority of the requests a user waits for, the default.
+	PriorityInteractive Priority = iota
+	// PriorityBatch is the priority of the background requests, e.g. of reconciliation
+	// loops, which only get the tokens no interactive request waits for.
+	PriorityBatch
+
+	numPriorities = int(PriorityBatch) + 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx whose requests are rate limited with priority by a
+// priority rate limiter, e.g. to run a background loop with PriorityBatch.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom returns the priority of ctx and whether it has one, PriorityInteractive
+// if it hasn't.
+func PriorityFrom(ctx context.Context) (Priority, bool) {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok && priority >= 0 && int(priority) < numPriorities {
+		return priority, true
+	}
+	return PriorityInteractive, false
+}
+
+type priorityRateLimiter struct {
+	limiter RateLimiter
+
+	mu sync.Mutex
+	// waiters are the channels of the requests waiting for a token by priority, closed when
+	// they are granted one
+	waiters     [numPriorities][]chan struct{}
+	dispatching bool
+}
+
+// NewPriorityRateLimiter creates a rate limiter which shares the tokens of limiter between
+// priority classes: a token is granted to a waiting request of the highest priority, set
+// with WithPriority on the context passed to Wait, and to the requests of a lower priority
+// only when none of a higher one waits. Accept and TryAccept have PriorityInteractive.
+func NewPriorityRateLimiter(limiter RateLimiter) RateLimiter {
+	return &priorityRateLimiter{limiter: limiter}
+}
+
+// NewPriorityTokenBucketRateLimiter creates a priority rate limiter sharing a token
+// bucket, see NewTokenBucketRateLimiter and NewPriorityRateLimiter.
+func NewPriorityTokenBucketRateLimiter(qps float32, burst int) RateLimiter {
+	return NewPriorityRateLimiter(NewTokenBucketRateLimiter(qps, burst))
+}
+
+func (p *priorityRateLimiter) TryAccept() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the waiting requests are served first
+	return p.empty() && p.limiter.TryAccept()
+}
+
+// Accept will block until a token becomes available
+func (p *priorityRateLimiter) Accept() {
+	<-p.enqueue(PriorityInteractive)
+}
+
+// Wait will block until a token is granted to the priority of ctx or ctx is done
+func (p *priorityRateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	priority, _ := PriorityFrom(ctx)
+	granted := p.enqueue(priority)
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	waiters := p.waiters[priority]
+	for i, waiter := range waiters {
+		if waiter == granted {
+			p.waiters[priority] = append(waiters[:i:i], waiters[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// the token was granted meanwhile
+	return nil
+}
+
+func (p *priorityRateLimiter) Stop() {
+	p.limiter.Stop()
+}
+
+func (p *priorityRateLimiter) QPS() float32 {
+	return p.limiter.QPS()
+}
+
+// enqueue adds a waiter of priority and starts the dispatch if it isn't running.
+func (p *priorityRateLimiter) enqueue(priority Priority) chan struct{} {
+	granted := make(chan struct{})
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.waiters[priority] = append(p.waiters[priority], granted)
+	if !p.dispatching {
+		p.dispatching = true
+		go p.dispatch()
+	}
+	return granted
+}
+
+// dispatch takes the tokens of the limiter while requests wait and grants each one to the
+// first waiter of the highest priority at the time it is taken.
+func (p *priorityRateLimiter) dispatch() {
+	for {
+		p.mu.Lock()
+		if p.empty() {
+			p.dispatching = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		p.limiter.Accept()
+
+		p.mu.Lock()
+		for priority, waiters := range p.waiters {
+			if len(waiters) > 0 {
+				close(waiters[0])
+				p.waiters[priority] = waiters[1:]
+				break
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *priorityRateLimiter) empty() bool {
+	for _, waiters := range p.waiters {
+		if len(waiters) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// chanRateLimiter is a rate limiter granting a token for every value sent on its channel.
+type chanRateLimiter struct {
+	tokens chan struct{}
+}
+
+func (c *chanRateLimiter) TryAccept() bool {
+	select {
+	case <-c.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *chanRateLimiter) Accept() {
+	<-c.tokens
+}
+
+func (c *chanRateLimiter) Wait(ctx context.Context) error {
+	c.Accept()
+	return nil
+}
+
+func (c *chanRateLimiter) Stop() {}
+
+func (c *chanRateLimiter) QPS() float32 {
+	return 1
+}
+
+func waitForWaiters(t *testing.T, p *priorityRateLimiter, priority Priority, n int) {
+	for i := 0; i < 100; i++ {
+		p.mu.Lock()
+		count := len(p.waiters[priority])
+		p.mu.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d waiters of priority %d", n, priority)
+}
+
+func TestPriorityRateLimiter(t *testing.T) {
+	tokens := make(chan struct{})
+	p := NewPriorityRateLimiter(&chanRateLimiter{tokens: tokens}).(*priorityRateLimiter)
+
+	granted := make(chan string, 3)
+	batch := WithPriority(context.Background(), PriorityBatch)
+	for i := 0; i < 2; i++ {
+		go func() {
+			if err := p.Wait(batch); err == nil {
+				granted <- "batch"
+			}
+		}()
+	}
+	waitForWaiters(t, p, PriorityBatch, 2)
+	go func() {
+		if err := p.Wait(context.Background()); err == nil {
+			granted <- "interactive"
+		}
+	}()
+	waitForWaiters(t, p, PriorityInteractive, 1)
+	if p.TryAccept() {
+		t.Error("TryAccept shouldn't take a token while requests wait")
+	}
+
+	var order []string
+	for i := 0; i < 3; i++ {
+		tokens <- struct{}{}
+		order = append(order, <-granted)
+	}
+	if order[0] != "interactive" || order[1] != "batch" || order[2] != "batch" {
+		t.Errorf("expected the interactive request to be served first, got %v", order)
+	}
+
+	ctx, cancel := context.WithCancel(batch)
+	go cancel()
+	if err := p.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.waiters[PriorityBatch]) != 0 {
+		t.Error("the canceled waiter should be removed")
+	}
+}
+
+func TestPriorityFrom(t *testing.T) {
+	if priority, ok := PriorityFrom(context.Background()); ok || priority != PriorityInteractive {
+		t.Errorf("unexpected priority %d of a context without one", priority)
+	}
+	if priority, ok := PriorityFrom(WithPriority(context.Background(), PriorityBatch)); !ok || priority != PriorityBatch {
+		t.Errorf("unexpected priority %d", priority)
+	}
+}
@@ -30,6 +30,11 @@ type RateLimiter interface {
 	TryAccept() bool
 	// Accept returns once a token becomes available.
 	Accept()
+	// Wait returns nil once a token becomes available, or an error if ctx is done first
+	// or the token can't be obtained before its deadline. It is the context aware
+	// counterpart of Accept, a cancelled context interrupts the wait instead of blocking
+	// until a token is available, and the one the requests are throttled with.
+	Wait(ctx context.Context) error
 	// Stop stops the rate limiter, subsequent calls to CanAccept will return false
 	Stop()
 	// QPS returns QPS of this rate limiter
@@ -92,6 +97,11 @@ func (t *tokenBucketRateLimiter) Accept() {
 	t.clock.Sleep(t.limiter.ReserveN(now, 1).DelayFrom(now))
 }
 
+// Wait will block until a token becomes available or ctx is done
+func (t *tokenBucketRateLimiter) Wait(ctx context.Context) error {
+	return t.limiter.Wait(ctx)
+}
+
 func (t *tokenBucketRateLimiter) Stop() {
 }
 
@@ -102,7 +112,7 @@ func (t *tokenBucketRateLimiter) QPS() float32 {
 type fakeAlwaysRateLimiter struct{}
 
 func (t *fakeAlwaysRateLimiter) Wait(ctx context.Context) error {
-	panic("implement me")
+	return nil
 }
 
 func NewFakeAlwaysRateLimiter() RateLimiter {
@@ -143,6 +153,20 @@ func (t *fakeNeverRateLimiter) Accept() {
 	t.wg.Wait()
 }
 
+func (t *fakeNeverRateLimiter) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
 func (t *fakeNeverRateLimiter) QPS() float32 {
 	return 1
 }
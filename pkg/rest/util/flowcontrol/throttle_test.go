@@ -17,6 +17,7 @@ limitations under the License.
 package flowcontrol
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -151,3 +152,158 @@ func TestNeverFake(t *testing.T) {
 		t.Error("Stop should make Accept unblock in NeverFake.")
 	}
 }
+
+func TestWaitContext(t *testing.T) {
+	r := NewTokenBucketRateLimiter(1, 1)
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error waiting for the first token: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Wait(ctx); err == nil {
+		t.Error("Wait should fail when the token can't be obtained before the deadline")
+	}
+
+	rl := NewFakeNeverRateLimiter()
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Wait(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// fakeClock is a clock whose Sleep advances the time instead of blocking.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestLeakyBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewLeakyBucketRateLimiterWithClock(10, clock)
+	if !r.TryAccept() {
+		t.Error("the first token should be available")
+	}
+	if r.TryAccept() {
+		t.Error("the leaky bucket shouldn't allow a burst")
+	}
+	clock.Sleep(100 * time.Millisecond)
+	if !r.TryAccept() {
+		t.Error("a token should leak every 100ms")
+	}
+	start := clock.Now()
+	for i := 0; i < 5; i++ {
+		r.Accept()
+	}
+	if elapsed := clock.Now().Sub(start); elapsed != 500*time.Millisecond {
+		t.Errorf("expected the tokens to be spaced evenly, took %v", elapsed)
+	}
+	if r.QPS() != 10 {
+		t.Errorf("unexpected QPS %v", r.QPS())
+	}
+
+	r = NewLeakyBucketRateLimiter(1)
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error waiting for the first token: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Wait(ctx); err == nil {
+		t.Error("Wait should fail when the token can't be obtained before the deadline")
+	}
+}
+
+func TestFixedWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 30, 0, time.UTC)}
+	r := NewFixedWindowRateLimiterWithClock(3, time.Minute, clock)
+	for i := 0; i < 3; i++ {
+		if !r.TryAccept() {
+			t.Fatalf("token %d should be available in the window", i)
+		}
+	}
+	if r.TryAccept() {
+		t.Error("the window should be full")
+	}
+	r.Accept()
+	if expected := time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC); !clock.Now().Equal(expected) {
+		t.Errorf("expected to wait for the next window at %v, got %v", expected, clock.Now())
+	}
+	if !r.TryAccept() || !r.TryAccept() || r.TryAccept() {
+		t.Error("the next window should allow the remaining 2 tokens")
+	}
+	if r.QPS() != 0.05 {
+		t.Errorf("unexpected QPS %v", r.QPS())
+	}
+
+	r = NewFixedWindowRateLimiter(1, time.Hour)
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error waiting for the first token: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Wait(ctx); err == nil {
+		t.Error("Wait should fail when the token can't be obtained before the deadline")
+	}
+}
+
+// canceledWait waits for a token of r and cancels the wait once the token is reserved.
+func canceledWait(t *testing.T, r RateLimiter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := r.Wait(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWaitCanceledGivesTokenBack(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewLeakyBucketRateLimiterWithClock(10, clock)
+	if !r.TryAccept() {
+		t.Fatal("the first token should be available")
+	}
+	canceledWait(t, r)
+	clock.Sleep(100 * time.Millisecond)
+	if !r.TryAccept() {
+		t.Error("the leaky bucket should give the token of a canceled wait back")
+	}
+
+	clock = &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 30, 0, time.UTC)}
+	r = NewFixedWindowRateLimiterWithClock(1, time.Minute, clock)
+	if !r.TryAccept() {
+		t.Fatal("the first token should be available")
+	}
+	canceledWait(t, r)
+	clock.Sleep(30 * time.Second)
+	if !r.TryAccept() {
+		t.Error("the fixed window should give the token of a canceled wait back")
+	}
+	if r.TryAccept() {
+		t.Error("the window should be full")
+	}
+}
+
+func TestLimitersRejectNonPositiveRates(t *testing.T) {
+	for name, create := range map[string]func(){
+		"zero qps":        func() { NewLeakyBucketRateLimiter(0) },
+		"negative qps":    func() { NewLeakyBucketRateLimiter(-1) },
+		"zero limit":      func() { NewFixedWindowRateLimiter(0, time.Minute) },
+		"zero window":     func() { NewFixedWindowRateLimiter(1, 0) },
+		"negative window": func() { NewFixedWindowRateLimiter(1, -time.Minute) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			create()
+		}()
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package retention
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+)
+
+// RetentionInterface holds the methods of the tag retention APIs.
+type RetentionInterface interface {
+	GetMetadatas(ctx context.Context) (result *model.RetentionMetadata, err error)
+}
+
+// RetentionClient is used to interact with the tag retention APIs.
+type RetentionClient struct {
+	restClient rest2.Interface
+}
+
+func NewRetentionClient(restClient *rest2.Config) (*RetentionClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionClient{restClient: client}, nil
+}
+
+// GetMetadatas gets the rule templates and the selectors supported by Harbor, which
+// RetentionMetadata.Validate checks policies against.
+func (r *RetentionClient) GetMetadatas(ctx context.Context) (result *model.RetentionMetadata, err error) {
+	result = &model.RetentionMetadata{}
+	err = r.restClient.Get().
+		Resource("retentions").
+		Suffix("metadatas").
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package robot
+
+import (
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretEscrow receives the secrets of the robot accounts created or rotated by the
+// RobotsClient, so they flow directly into a secret manager instead of through return
+// values that end up in logs. The secret is zeroed once Deposit returns, implementations
+// must copy it if they keep it.
+type SecretEscrow interface {
+	Deposit(robot *model.RobotCreated, secret *rest2.Secret) error
+}
+
+// SecretEscrowFunc adapts a function to a SecretEscrow.
+type SecretEscrowFunc func(robot *model.RobotCreated, secret *rest2.Secret) error
+
+// Deposit calls f(robot, secret).
+func (f SecretEscrowFunc) Deposit(robot *model.RobotCreated, secret *rest2.Secret) error {
+	return f(robot, secret)
+}
+
+// FileEscrow is a reference SecretEscrow writing each secret to its own file, readable by
+// the owner only, in Dir. The file is named after the robot account, path escaped like the
+// keys of store.FileStore so that distinct accounts never share a file, e.g.
+// robot$project+ci for robot$project+ci and robot$team%2Fci for robot$team/ci. It is
+// replaced atomically on rotation so that readers never see a partial secret.
+type FileEscrow struct {
+	Dir string
+}
+
+// NewFileEscrow returns a FileEscrow writing into dir, which is created if needed.
+func NewFileEscrow(dir string) (*FileEscrow, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create escrow directory %s error: %v", dir, err)
+	}
+	return &FileEscrow{Dir: dir}, nil
+}
+
+// Path returns the path of the file holding the secret of the robot account name.
+func (e *FileEscrow) Path(name string) string {
+	name = url.PathEscape(name)
+	if name == "." || name == ".." {
+		name = strings.ReplaceAll(name, ".", "%2E")
+	}
+	return filepath.Join(e.Dir, name)
+}
+
+// Deposit implements SecretEscrow.
+func (e *FileEscrow) Deposit(robot *model.RobotCreated, secret *rest2.Secret) error {
+	tmp, err := ioutil.TempFile(e.Dir, ".secret-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(secret.Reveal()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), e.Path(robot.Name))
+}
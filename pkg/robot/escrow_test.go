@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileEscrowDeposit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "escrow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	escrow, err := NewFileEscrow(filepath.Join(dir, "secrets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deposits := []struct{ name, secret string }{
+		{"robot$library+ci", "first"},
+		{"robot$library_ci", "other"},
+		// the rotation replaces the secret
+		{"robot$library+ci", "second"},
+	}
+	for _, deposit := range deposits {
+		if err := escrow.Deposit(&model.RobotCreated{Name: deposit.name}, rest2.NewSecret(deposit.secret)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, secret := range map[string]string{"robot$library+ci": "second", "robot$library_ci": "other"} {
+		path := escrow.Path(name)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != secret {
+			t.Errorf("%s: expected the secret %q, got %q", name, secret, data)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0600 {
+			t.Errorf("%s: expected the secret to be readable by the owner only, got %v", name, mode)
+		}
+	}
+	files, err := ioutil.ReadDir(escrow.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expected a file per robot account and no temporary file left, got %d files", len(files))
+	}
+	for _, name := range []string{"robot$team/ci", "..", "."} {
+		if filepath.Dir(escrow.Path(name)) != escrow.Dir {
+			t.Errorf("expected the secret of %q to be kept in the escrow directory, got %s", name, escrow.Path(name))
+		}
+	}
+}
+
+// fakeRobots is a Harbor creating robot accounts and recording their deletions.
+type fakeRobots struct {
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (f *fakeRobots) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/api/v2.0/robots":
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(&model.RobotCreated{ID: 7, Name: "robot$library+ci", Secret: "robot-secret"})
+	case req.Method == http.MethodDelete && strings.HasPrefix(req.URL.Path, "/api/v2.0/robots/"):
+		f.deleted = append(f.deleted, strings.TrimPrefix(req.URL.Path, "/api/v2.0/robots/"))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestCreateDeletesRobotWhenDepositFails(t *testing.T) {
+	robots := &fakeRobots{}
+	client, stop := newTestRobotsClient(t, robots.ServeHTTP)
+	defer stop()
+
+	var deposited string
+	client.SetEscrow(SecretEscrowFunc(func(robot *model.RobotCreated, secret *rest2.Secret) error {
+		deposited = secret.Reveal()
+		return fmt.Errorf("secret manager unavailable")
+	}))
+	result, err := client.Create(context.Background(), &model.Robot{Name: "ci", Level: model.RobotLevelProject})
+	if err == nil || !strings.Contains(err.Error(), "secret manager unavailable") || result != nil {
+		t.Fatalf("expected the deposit error, got %v, %+v", err, result)
+	}
+	if deposited != "robot-secret" {
+		t.Errorf("expected the secret to be handed to the escrow, got %q", deposited)
+	}
+	if len(robots.deleted) != 1 || robots.deleted[0] != "7" {
+		t.Errorf("expected the robot account to be deleted, got the deletions %v", robots.deleted)
+	}
+
+	client.SetEscrow(SecretEscrowFunc(func(robot *model.RobotCreated, secret *rest2.Secret) error {
+		return nil
+	}))
+	result, err = client.Create(context.Background(), &model.Robot{Name: "ci", Level: model.RobotLevelProject})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Secret != "" || len(robots.deleted) != 1 {
+		t.Errorf("expected the deposited secret to be cleared and the robot account kept, got %+v", result)
+	}
+}
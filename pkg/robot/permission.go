@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package robot
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"k8s.io/klog"
+)
+
+// ValidatePermissions checks the permissions of robot against the permission catalog of
+// Harbor, or model.DefaultPermissionCatalog if the server doesn't expose it, so that
+// invalid permissions are rejected with a clear error rather than a 400. The catalog is
+// fetched once per client, the errors other than a missing catalog are returned and the
+// next call fetches it again.
+func (r *RobotsClient) ValidatePermissions(ctx context.Context, robot *model.Robot) error {
+	catalog, err := r.permissionCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	return catalog.Validate(robot.Permissions)
+}
+
+func (r *RobotsClient) permissionCatalog(ctx context.Context) (*model.PermissionCatalog, error) {
+	r.catalogMu.Lock()
+	catalog := r.catalog
+	r.catalogMu.Unlock()
+	if catalog != nil {
+		return catalog, nil
+	}
+
+	catalog = &model.PermissionCatalog{}
+	err := r.restClient.Get().
+		Resource("permissions").
+		Do(ctx).
+		Into(catalog)
+	switch {
+	case rest2.IsNotFound(err):
+		klog.V(4).Infof("Harbor doesn't expose its permission catalog, using the default one: %v", err)
+		catalog = model.DefaultPermissionCatalog
+	case err != nil:
+		return nil, fmt.Errorf("get permission catalog error: %v", err)
+	}
+	r.catalogMu.Lock()
+	defer r.catalogMu.Unlock()
+	r.catalog = catalog
+	return catalog, nil
+}
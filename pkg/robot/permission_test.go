@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newTestRobotsClient(t *testing.T, handler http.HandlerFunc) (*RobotsClient, func()) {
+	server := httptest.NewServer(handler)
+	client, err := NewRobotsClient(rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345"))
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestValidatePermissionsRetriesCatalog(t *testing.T) {
+	var requests int32
+	client, stop := newTestRobotsClient(t, func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(&model.PermissionCatalog{Project: []*model.Access{{Resource: "sbom", Action: model.ActionRead}}})
+	})
+	defer stop()
+
+	robot := &model.Robot{Permissions: []*model.RobotPermission{{
+		Kind:      model.RobotLevelProject,
+		Namespace: "library",
+		Access:    []*model.Access{{Resource: "sbom", Action: model.ActionRead}},
+	}}}
+	if err := client.ValidatePermissions(context.Background(), robot); err == nil {
+		t.Fatal("expected the error getting the catalog")
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.ValidatePermissions(context.Background(), robot); err != nil {
+			t.Fatalf("expected the permission of the catalog of Harbor to be valid, got %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected the catalog to be fetched again after the error only, got %d requests", n)
+	}
+}
+
+func TestValidatePermissionsDefaultCatalog(t *testing.T) {
+	var requests int32
+	client, stop := newTestRobotsClient(t, func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, req)
+	})
+	defer stop()
+
+	robot := &model.Robot{Permissions: []*model.RobotPermission{{
+		Kind:      model.RobotLevelProject,
+		Namespace: "library",
+		Access:    []*model.Access{{Resource: model.ResourceRepository, Action: model.ActionPull}},
+	}}}
+	for i := 0; i < 2; i++ {
+		if err := client.ValidatePermissions(context.Background(), robot); err != nil {
+			t.Fatalf("expected the default catalog to be used, got %v", err)
+		}
+	}
+	robot.Permissions[0].Access[0].Resource = "sbom"
+	if err := client.ValidatePermissions(context.Background(), robot); err == nil {
+		t.Error("expected the permission missing from the default catalog to be rejected")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected the default catalog to be cached, got %d requests", n)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package robot
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strconv"
+	"sync"
+)
+
+// RobotsInterface holds the methods of the robot account APIs.
+type RobotsInterface interface {
+	Get(ctx context.Context, id int64) (result *model.Robot, err error)
+	List(ctx context.Context, query *model.Query) (results *[]model.Robot, err error)
+	Create(ctx context.Context, robot *model.Robot) (result *model.RobotCreated, err error)
+	Update(ctx context.Context, id int64, robot *model.Robot) (err error)
+	Delete(ctx context.Context, id int64) (err error)
+	RefreshSecret(ctx context.Context, id int64, secret *rest2.Secret) (result *model.RobotSecret, err error)
+	ValidatePermissions(ctx context.Context, robot *model.Robot) error
+}
+
+// RobotsClient is used to manage robot accounts. When a SecretEscrow is set, the secrets
+// of the robot accounts it creates are deposited into the escrow instead of being returned.
+// The permissions of the robot accounts are validated before they are created or updated.
+type RobotsClient struct {
+	restClient rest2.Interface
+	escrow     SecretEscrow
+
+	// catalog is the permission catalog once fetched, or the default one if Harbor
+	// doesn't expose it
+	catalogMu sync.Mutex
+	catalog   *model.PermissionCatalog
+}
+
+func NewRobotsClient(restClient *rest2.Config) (*RobotsClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &RobotsClient{restClient: client}, nil
+}
+
+// SetEscrow sets the escrow the secrets of created robot accounts are deposited into.
+func (r *RobotsClient) SetEscrow(escrow SecretEscrow) {
+	r.escrow = escrow
+}
+
+func (r *RobotsClient) Get(ctx context.Context, id int64) (result *model.Robot, err error) {
+	result = &model.Robot{}
+	err = r.restClient.Get().
+		Resource("robots").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (r *RobotsClient) List(ctx context.Context, query *model.Query) (results *[]model.Robot, err error) {
+	results = &[]model.Robot{}
+	err = r.restClient.List().
+		Resource("robots").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// Create creates a robot account. If an escrow is set, the secret is deposited into it and
+// cleared from the result; if the deposit fails the robot account is deleted, since its
+// secret would be lost otherwise.
+func (r *RobotsClient) Create(ctx context.Context, robot *model.Robot) (result *model.RobotCreated, err error) {
+	if err := r.ValidatePermissions(ctx, robot); err != nil {
+		return nil, err
+	}
+	result = &model.RobotCreated{}
+	err = r.restClient.Post().
+		Resource("robots").
+		Body(robot).
+		Do(ctx).
+		Into(result)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.deposit(result); err != nil {
+		if deleteErr := r.Delete(ctx, result.ID); deleteErr != nil {
+			return nil, fmt.Errorf("%v, delete robot account %s error: %v", err, result.Name, deleteErr)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// deposit hands the secret of robot to the escrow, if any, and clears it.
+func (r *RobotsClient) deposit(robot *model.RobotCreated) error {
+	if r.escrow == nil {
+		return nil
+	}
+	secret := rest2.NewSecret(robot.Secret)
+	robot.Secret = ""
+	defer secret.Zero()
+	if err := r.escrow.Deposit(robot, secret); err != nil {
+		return fmt.Errorf("deposit secret of robot account %s error: %v", robot.Name, err)
+	}
+	return nil
+}
+
+// Update updates the description, duration, permissions or status of a robot account.
+func (r *RobotsClient) Update(ctx context.Context, id int64, robot *model.Robot) (err error) {
+	if err := r.ValidatePermissions(ctx, robot); err != nil {
+		return err
+	}
+	return r.restClient.Put().
+		Resource("robots").
+		Name(strconv.FormatInt(id, 10)).
+		Body(robot).
+		Do(ctx).
+		Error()
+}
+
+func (r *RobotsClient) Delete(ctx context.Context, id int64) (err error) {
+	return r.restClient.Delete().
+		Resource("robots").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Error()
+}
+
+// RefreshSecret rotates the secret of a robot account, setting it to secret or to a secret
+// generated by Harbor if nil, and returns the new secret. If an escrow is set, the secret
+// is deposited into it and cleared from the result; if the deposit fails the secret is
+// lost and the robot account must be refreshed again.
+func (r *RobotsClient) RefreshSecret(ctx context.Context, id int64, secret *rest2.Secret) (result *model.RobotSecret, err error) {
+	var robot *model.Robot
+	if r.escrow != nil {
+		// the escrow keys the secrets by robot account name
+		if robot, err = r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	body := &model.RobotSecret{}
+	if secret != nil {
+		body.Secret = secret.Reveal()
+	}
+	result = &model.RobotSecret{}
+	err = r.restClient.Patch().
+		Resource("robots").
+		Name(strconv.FormatInt(id, 10)).
+		Body(body).
+		Do(ctx).
+		Into(result)
+	body.Secret = ""
+	if err != nil {
+		return nil, err
+	}
+	if robot == nil {
+		return result, nil
+	}
+	created := &model.RobotCreated{ID: id, Name: robot.Name, Secret: result.Secret, ExpiresAt: robot.ExpiresAt}
+	result.Secret = ""
+	if err := r.deposit(created); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func testStore(t *testing.T, s Store) {
+	if _, ok, err := s.Get("pins/harbor.example.com:443"); ok || err != nil {
+		t.Fatalf("expected no value, got %v, %v", ok, err)
+	}
+	for _, value := range []string{"first", "second"} {
+		if err := s.Put("pins/harbor.example.com:443", []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+		got, ok, err := s.Get("pins/harbor.example.com:443")
+		if !ok || err != nil || string(got) != value {
+			t.Errorf("expected %q, got %q, %v, %v", value, got, ok, err)
+		}
+	}
+	if _, ok, _ := s.Get("pins"); ok {
+		t.Error("the keys must not overlap")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	// the values outlive the store
+	reopened, _ := NewFileStore(dir)
+	if value, ok, err := reopened.Get("pins/harbor.example.com:443"); !ok || err != nil || string(value) != "second" {
+		t.Errorf("expected the value to be persisted, got %q, %v, %v", value, ok, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// GetConfigurations gets the system settings, it requires the system admin role.
+func (s *SystemClient) GetConfigurations(ctx context.Context) (result *model.ConfigurationsResponse, err error) {
+	result = &model.ConfigurationsResponse{}
+	err = s.restClient.Get().
+		Resource("configurations").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateConfigurations updates the non nil system settings of configurations.
+func (s *SystemClient) UpdateConfigurations(ctx context.Context, configurations *model.Configurations) (err error) {
+	return s.restClient.Put().
+		Resource("configurations").
+		Body(configurations).
+		Do(ctx).
+		Error()
+}
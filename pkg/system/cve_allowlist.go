@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// GetCVEAllowlist gets the system wide CVE allowlist.
+func (s *SystemClient) GetCVEAllowlist(ctx context.Context) (result *model.CVEAllowlist, err error) {
+	result = &model.CVEAllowlist{}
+	err = s.restClient.Get().
+		Resource("system").
+		Suffix("CVEAllowlist").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateCVEAllowlist replaces the items and the expiration of the system wide CVE
+// allowlist, it requires the system admin role.
+func (s *SystemClient) UpdateCVEAllowlist(ctx context.Context, allowlist *model.CVEAllowlist) (err error) {
+	return s.restClient.Put().
+		Resource("system").
+		Suffix("CVEAllowlist").
+		Body(allowlist).
+		Do(ctx).
+		Error()
+}
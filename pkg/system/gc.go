@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"strconv"
+)
+
+// ListGC lists the garbage collection runs, the latest first.
+func (s *SystemClient) ListGC(ctx context.Context, query *model.Query) (results *[]model.GCHistory, err error) {
+	if query == nil {
+		query = &model.Query{}
+	}
+	results = &[]model.GCHistory{}
+	err = s.restClient.List().
+		Resource("system").
+		Suffix("gc").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+func (s *SystemClient) GetGC(ctx context.Context, id int64) (result *model.GCHistory, err error) {
+	result = &model.GCHistory{}
+	err = s.restClient.Get().
+		Resource("system").
+		Suffix("gc", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetGCLog gets the log of a garbage collection run.
+func (s *SystemClient) GetGCLog(ctx context.Context, id int64) (log []byte, err error) {
+	log, _, err = s.restClient.Get().
+		Resource("system").
+		Suffix("gc", strconv.FormatInt(id, 10), "log").
+		DoRaw(ctx)
+	return
+}
+
+// RunGC starts a garbage collection right away, it fails with a 409 if one is running.
+func (s *SystemClient) RunGC(ctx context.Context, deleteUntagged bool) (err error) {
+	return s.restClient.Post().
+		Resource("system").
+		Suffix("gc", "schedule").
+		Body(model.NewManualGC(deleteUntagged)).
+		Do(ctx).
+		Error()
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// GetIcon gets the icon identified by digest, e.g. the icon field of an artifact.
+func (s *SystemClient) GetIcon(ctx context.Context, digest string) (result *model.Icon, err error) {
+	result = &model.Icon{}
+	err = s.restClient.Get().
+		Resource("icons").
+		Name(digest).
+		Do(ctx).
+		Into(result)
+	return
+}
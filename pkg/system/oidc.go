@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// PingOIDC checks that Harbor can reach the OIDC endpoint, it requires the system admin
+// role and fails if the endpoint is unreachable or its certificate can't be verified.
+func (s *SystemClient) PingOIDC(ctx context.Context, ping *model.OIDCPing) (err error) {
+	return s.restClient.Post().
+		Resource("system").
+		Suffix("oidc", "ping").
+		Body(ping).
+		Do(ctx).
+		Error()
+}
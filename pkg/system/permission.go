@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// Permissions gets the resources and actions that can be granted to robot accounts at the
+// system and project levels, it requires Harbor 2.10 or later.
+func (s *SystemClient) Permissions(ctx context.Context) (result *model.PermissionCatalog, err error) {
+	result = &model.PermissionCatalog{}
+	err = s.restClient.Get().
+		Resource("permissions").
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+)
+
+// Search searches the projects, repositories and charts whose name contains q among the
+// public ones and the ones the current user is a member of.
+func (s *SystemClient) Search(ctx context.Context, q string) (result *model.Search, err error) {
+	result = &model.Search{}
+	err = s.restClient.Get().
+		Resource("search").
+		Param("q", q).
+		Do(ctx).
+		Into(result)
+	return
+}
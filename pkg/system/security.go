@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"strconv"
+)
+
+// SecuritySummary gets the vulnerability summary of all the scanned artifacts, with the
+// most dangerous CVEs and artifacts if withDangerous is set. It requires Harbor 2.9 or newer.
+func (s *SystemClient) SecuritySummary(ctx context.Context, withDangerous bool) (result *model.SecuritySummary, err error) {
+	if err = s.requireFeature(ctx, model.FeatureSecurityHub); err != nil {
+		return nil, err
+	}
+	result = &model.SecuritySummary{}
+	err = s.restClient.Get().
+		Resource("security").
+		Suffix("summary").
+		Param("with_dangerous_cve", strconv.FormatBool(withDangerous)).
+		Param("with_dangerous_artifact", strconv.FormatBool(withDangerous)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ListVulnerabilities lists the vulnerabilities of all the scanned artifacts matching the
+// query, without crawling the vulnerability report of each artifact. It requires Harbor 2.9
+// or newer.
+func (s *SystemClient) ListVulnerabilities(ctx context.Context, query *model.SecurityVulnerabilityQuery) (results *[]model.SecurityVulnerability, err error) {
+	if err = s.requireFeature(ctx, model.FeatureSecurityHub); err != nil {
+		return nil, err
+	}
+	results = &[]model.SecurityVulnerability{}
+	err = s.restClient.List().
+		Resource("security").
+		Suffix("vul").
+		Params(query.Query()).
+		Do(ctx).
+		IntoList(results)
+	return
+}
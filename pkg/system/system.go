@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strings"
+)
+
+// SystemInterface holds the methods of the system wide Harbor APIs.
+type SystemInterface interface {
+	Statistics(ctx context.Context) (result *model.Statistic, err error)
+	Health(ctx context.Context) (result *model.OverallHealthStatus, err error)
+	Ping(ctx context.Context) (err error)
+	GetConfigurations(ctx context.Context) (result *model.ConfigurationsResponse, err error)
+	UpdateConfigurations(ctx context.Context, configurations *model.Configurations) (err error)
+	Search(ctx context.Context, q string) (result *model.Search, err error)
+	GetCVEAllowlist(ctx context.Context) (result *model.CVEAllowlist, err error)
+	UpdateCVEAllowlist(ctx context.Context, allowlist *model.CVEAllowlist) (err error)
+	GetIcon(ctx context.Context, digest string) (result *model.Icon, err error)
+	ListGC(ctx context.Context, query *model.Query) (results *[]model.GCHistory, err error)
+	GetGC(ctx context.Context, id int64) (result *model.GCHistory, err error)
+	GetGCLog(ctx context.Context, id int64) (log []byte, err error)
+	RunGC(ctx context.Context, deleteUntagged bool) (err error)
+	Permissions(ctx context.Context) (result *model.PermissionCatalog, err error)
+	PingOIDC(ctx context.Context, ping *model.OIDCPing) (err error)
+	SecuritySummary(ctx context.Context, withDangerous bool) (result *model.SecuritySummary, err error)
+	ListVulnerabilities(ctx context.Context, query *model.SecurityVulnerabilityQuery) (results *[]model.SecurityVulnerability, err error)
+	SystemInfo(ctx context.Context) (result *model.GeneralInfo, err error)
+	Version(ctx context.Context) (result *model.ServerVersion, err error)
+	APIVersion(ctx context.Context) (version string, err error)
+	Supports(ctx context.Context, feature model.Feature) (bool, error)
+}
+
+// SystemClient is used to interact with the system wide Harbor APIs.
+type SystemClient struct {
+	restClient rest2.Interface
+	version    versionCache
+}
+
+func NewSystemClient(restClient *rest2.Config) (*SystemClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &SystemClient{restClient: client}, nil
+}
+
+// Statistics gets the project and repository counts relevant to the current user.
+func (s *SystemClient) Statistics(ctx context.Context) (result *model.Statistic, err error) {
+	result = &model.Statistic{}
+	err = s.restClient.Get().
+		Resource("statistics").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Health checks the status of Harbor and of each of its components.
+func (s *SystemClient) Health(ctx context.Context) (result *model.OverallHealthStatus, err error) {
+	result = &model.OverallHealthStatus{}
+	err = s.restClient.Get().
+		Resource("health").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Ping checks that Harbor is reachable, the endpoint doesn't require authentication.
+func (s *SystemClient) Ping(ctx context.Context) (err error) {
+	body, _, err := s.restClient.Get().
+		Resource("ping").
+		DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(body), "Pong") {
+		return fmt.Errorf("unexpected ping response: %q", string(body))
+	}
+	return nil
+}
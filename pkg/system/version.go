@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"sync"
+)
+
+// ErrVersionUnknown is returned by Version when Harbor doesn't return its version, e.g.
+// to an anonymous user, or returns a version that can't be parsed.
+var ErrVersionUnknown = errors.New("the version of Harbor is unknown")
+
+// versionCache keeps the version of Harbor, or the error telling it is unknown, once it
+// is known. The system information is fetched by a single caller at a time, the others
+// wait for its result.
+type versionCache struct {
+	mu      sync.Mutex
+	version *model.ServerVersion
+	unknown error
+	fetch   *versionFetch
+}
+
+// versionFetch is the result of a fetch of the version, ready once done is closed.
+type versionFetch struct {
+	done    chan struct{}
+	version *model.ServerVersion
+	err     error
+}
+
+// SystemInfo gets the system information of Harbor, e.g. its authentication mode.
+func (s *SystemClient) SystemInfo(ctx context.Context) (result *model.GeneralInfo, err error) {
+	result = &model.GeneralInfo{}
+	err = s.restClient.Get().
+		Resource("systeminfo").
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Version gets the release of Harbor, e.g. v2.9.1, from its system information. It is
+// only returned to the authenticated users, an error wrapping ErrVersionUnknown is
+// returned otherwise. The version, or the error wrapping ErrVersionUnknown, is cached once
+// known, other errors are returned as is and the next call fetches the version again.
+func (s *SystemClient) Version(ctx context.Context) (result *model.ServerVersion, err error) {
+	for {
+		s.version.mu.Lock()
+		if s.version.version != nil || s.version.unknown != nil {
+			result, err = s.version.version, s.version.unknown
+			s.version.mu.Unlock()
+			return result, err
+		}
+		fetch := s.version.fetch
+		if fetch == nil {
+			fetch = &versionFetch{done: make(chan struct{})}
+			s.version.fetch = fetch
+			s.version.mu.Unlock()
+			s.fetchVersion(ctx, fetch)
+			return fetch.version, fetch.err
+		}
+		s.version.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-fetch.done:
+		}
+		// the context of the caller that fetched the version may have been canceled,
+		// try again with ours
+		canceled := errors.Is(fetch.err, context.Canceled) || errors.Is(fetch.err, context.DeadlineExceeded)
+		if !canceled || ctx.Err() != nil {
+			return fetch.version, fetch.err
+		}
+	}
+}
+
+// fetchVersion gets the version of Harbor into fetch and caches it unless it failed.
+func (s *SystemClient) fetchVersion(ctx context.Context, fetch *versionFetch) {
+	defer close(fetch.done)
+	info, err := s.SystemInfo(ctx)
+	switch {
+	case err != nil:
+		fetch.err = err
+	case info.HarborVersion == "":
+		fetch.err = fmt.Errorf("%w: it is only returned to the authenticated users", ErrVersionUnknown)
+	default:
+		fetch.version, err = model.ParseServerVersion(info.HarborVersion)
+		if err != nil {
+			fetch.err = fmt.Errorf("%w: %v", ErrVersionUnknown, err)
+		}
+	}
+
+	s.version.mu.Lock()
+	defer s.version.mu.Unlock()
+	s.version.fetch = nil
+	if fetch.err == nil {
+		s.version.version = fetch.version
+	} else if errors.Is(fetch.err, ErrVersionUnknown) {
+		s.version.unknown = fetch.err
+	}
+}
+
+// APIVersion gets the version of the API of Harbor, e.g. v2.0.
+func (s *SystemClient) APIVersion(ctx context.Context) (version string, err error) {
+	var result struct {
+		Version string `json:"version"`
+	}
+	err = s.restClient.Get().
+		AbsPath("/api/version").
+		Do(ctx).
+		Into(&result)
+	return result.Version, err
+}
+
+// Supports returns true if the release of Harbor has the feature, e.g.
+// model.FeatureSecurityHub.
+func (s *SystemClient) Supports(ctx context.Context, feature model.Feature) (bool, error) {
+	version, err := s.Version(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version.Supports(feature), nil
+}
+
+// requireFeature fails with an UnsupportedFeatureError if the release of Harbor doesn't
+// have the feature. The call is let through if the version is unknown, e.g. to an
+// anonymous user, for the server to reject it. Errors getting the version are returned.
+func (s *SystemClient) requireFeature(ctx context.Context, feature model.Feature) error {
+	version, err := s.Version(ctx)
+	if errors.Is(err, ErrVersionUnknown) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get the version of Harbor error: %v", err)
+	}
+	if version.Supports(feature) {
+		return nil
+	}
+	return &model.UnsupportedFeatureError{Feature: feature, Version: version}
+}
@@ -16,14 +16,31 @@ See the License for the specific language governing permissions and
 package user
 
 import (
+	"context"
 	"github.com/hujianxiong/go-harbor/pkg/model"
 	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"fmt"
 	"github.com/goharbor/harbor/src/common/models"
+	"strconv"
 )
 
+// searchPageSize is the page size used to go through the search results.
+const searchPageSize = 100
+
 // UsersInterface holds the methods that discover server-supported API groups,
 // versions and resources.
 type UsersInterface interface {
+	Get(ctx context.Context, name string) (result *models.User, err error)
+	Current(ctx context.Context) (result *models.User, err error)
+	CurrentPermissions(ctx context.Context, scope string, relative bool) (result model.Permissions, err error)
+	List(ctx context.Context, query *model.Query) (results *[]models.User, err error)
+	Search(ctx context.Context, username string, query *model.Query) (results *[]model.UserSearch, err error)
+	GetIDByUsername(ctx context.Context, username string) (id int, err error)
+	Create(ctx context.Context, user *model.UserCreationReq) (err error)
+	UpdateProfile(ctx context.Context, id int, profile *model.UserProfile) (err error)
+	SetPassword(ctx context.Context, id int, password *model.PasswordReq) (err error)
+	SetSysAdmin(ctx context.Context, id int, sysAdmin bool) (err error)
+	Delete(ctx context.Context, name string) (err error)
 }
 
 type UsersClient struct {
@@ -38,30 +55,131 @@ func NewUsersClient(restClient *rest2.Config) (*UsersClient, error) {
 	return &UsersClient{restClient: client}, nil
 }
 
-func (u *UsersClient) Get(name string) (result *models.User, err error) {
+func (u *UsersClient) Get(ctx context.Context, name string) (result *models.User, err error) {
 	result = &models.User{}
 	err = u.restClient.Get().
 		Resource("users").
 		Name(name).
-		Do().
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Current gets the user the client is authenticated as.
+func (u *UsersClient) Current(ctx context.Context) (result *models.User, err error) {
+	result = &models.User{}
+	err = u.restClient.Get().
+		Resource("users").
+		Name("current").
+		Do(ctx).
 		Into(result)
 	return
 }
 
-func (u *UsersClient) List(query *model.Query) (results *[]models.User, err error) {
+// CurrentPermissions gets the permissions of the current user in scope, e.g. /project/1,
+// or in every scope if empty. If relative is set the resources are relative to scope,
+// e.g. repository rather than /project/1/repository.
+func (u *UsersClient) CurrentPermissions(ctx context.Context, scope string, relative bool) (result model.Permissions, err error) {
+	request := u.restClient.Get().
+		Resource("users").
+		Name("current").
+		Suffix("permissions").
+		Param("relative", strconv.FormatBool(relative))
+	if scope != "" {
+		request = request.Param("scope", scope)
+	}
+	err = request.Do(ctx).Into(&result)
+	return
+}
+
+func (u *UsersClient) List(ctx context.Context, query *model.Query) (results *[]models.User, err error) {
 	results = &[]models.User{}
 	err = u.restClient.List().
 		Resource("users").
-		Params(*query).
-		Do().
-		Into(results)
+		Params(query).
+		Do(ctx).
+		IntoList(results)
 	return
 }
 
-func (u *UsersClient) Delete(name string) (err error) {
+func (u *UsersClient) Delete(ctx context.Context, name string) (err error) {
 	return u.restClient.Delete().
 		Resource("users").
 		Name(name).
-		Do().
+		Do(ctx).
+		Error()
+}
+
+// Search searches the users whose username contains username.
+func (u *UsersClient) Search(ctx context.Context, username string, query *model.Query) (results *[]model.UserSearch, err error) {
+	results = &[]model.UserSearch{}
+	err = u.restClient.List().
+		Resource("users").
+		Suffix("search").
+		Param("username", username).
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// GetIDByUsername resolves a username to the ID of the user, e.g. to add the user as a
+// project member. Search matches usernames partially, so the results are paged through
+// until the exact username is found.
+func (u *UsersClient) GetIDByUsername(ctx context.Context, username string) (id int, err error) {
+	for page := int64(1); ; page++ {
+		results, err := u.Search(ctx, username, &model.Query{Page: page, PageSize: searchPageSize})
+		if err != nil {
+			return 0, err
+		}
+		for _, user := range *results {
+			if user.Username == username {
+				return user.UserID, nil
+			}
+		}
+		if len(*results) < searchPageSize {
+			return 0, fmt.Errorf("user %s %w", username, rest2.ErrNotFound)
+		}
+	}
+}
+
+// Create creates a user, it is only allowed when the authentication mode is database.
+func (u *UsersClient) Create(ctx context.Context, user *model.UserCreationReq) (err error) {
+	return u.restClient.Post().
+		Resource("users").
+		Body(user).
+		Do(ctx).
+		Error()
+}
+
+// UpdateProfile updates the email, real name and comment of a user.
+func (u *UsersClient) UpdateProfile(ctx context.Context, id int, profile *model.UserProfile) (err error) {
+	return u.restClient.Put().
+		Resource("users").
+		Name(strconv.Itoa(id)).
+		Body(profile).
+		Do(ctx).
+		Error()
+}
+
+// SetPassword changes the password of a user.
+func (u *UsersClient) SetPassword(ctx context.Context, id int, password *model.PasswordReq) (err error) {
+	return u.restClient.Put().
+		Resource("users").
+		Name(strconv.Itoa(id)).
+		Suffix("password").
+		Body(password).
+		Do(ctx).
+		Error()
+}
+
+// SetSysAdmin grants or revokes the system administrator role of a user.
+func (u *UsersClient) SetSysAdmin(ctx context.Context, id int, sysAdmin bool) (err error) {
+	return u.restClient.Put().
+		Resource("users").
+		Name(strconv.Itoa(id)).
+		Suffix("sysadmin").
+		Body(&model.SysAdminFlag{SysAdminFlag: sysAdmin}).
+		Do(ctx).
 		Error()
 }
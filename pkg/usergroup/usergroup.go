@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package usergroup
+
+import (
+	"context"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"strconv"
+)
+
+// UserGroupsInterface holds the methods of the user group APIs.
+type UserGroupsInterface interface {
+	Get(ctx context.Context, id int64) (result *model.UserGroup, err error)
+	GetByName(ctx context.Context, name string, groupType int) (result *model.UserGroup, err error)
+	List(ctx context.Context, query *model.UserGroupQuery) (results *[]model.UserGroup, err error)
+	Search(ctx context.Context, query *model.UserGroupSearchQuery) (results *[]model.UserGroupSearchItem, err error)
+	Create(ctx context.Context, group *model.UserGroup) (err error)
+	Update(ctx context.Context, id int64, group *model.UserGroup) (err error)
+	Delete(ctx context.Context, id int64) (err error)
+}
+
+// UserGroupsClient is used to manage the LDAP, HTTP and OIDC user groups used for
+// group based project membership.
+type UserGroupsClient struct {
+	restClient rest2.Interface
+}
+
+func NewUserGroupsClient(restClient *rest2.Config) (*UserGroupsClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &UserGroupsClient{restClient: client}, nil
+}
+
+func (u *UserGroupsClient) Get(ctx context.Context, id int64) (result *model.UserGroup, err error) {
+	result = &model.UserGroup{}
+	err = u.restClient.Get().
+		Resource("usergroups").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// GetByName gets the user group with the given name and type. Search is fuzzy so the
+// results are filtered on the exact name.
+func (u *UserGroupsClient) GetByName(ctx context.Context, name string, groupType int) (result *model.UserGroup, err error) {
+	results, err := u.Search(ctx, &model.UserGroupSearchQuery{GroupName: name})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range *results {
+		if item.GroupName == name && item.GroupType == groupType {
+			return u.Get(ctx, item.ID)
+		}
+	}
+	return nil, fmt.Errorf("user group %s %w", name, rest2.ErrNotFound)
+}
+
+// List lists the user groups, query.LdapGroupDN filters LDAP groups by their DN.
+func (u *UserGroupsClient) List(ctx context.Context, query *model.UserGroupQuery) (results *[]model.UserGroup, err error) {
+	results = &[]model.UserGroup{}
+	err = u.restClient.List().
+		Resource("usergroups").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// Search searches the user groups by name.
+func (u *UserGroupsClient) Search(ctx context.Context, query *model.UserGroupSearchQuery) (results *[]model.UserGroupSearchItem, err error) {
+	results = &[]model.UserGroupSearchItem{}
+	err = u.restClient.List().
+		Resource("usergroups").
+		Suffix("search").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// Create creates a user group. LDAP groups require LdapGroupDN, the group name is then
+// read from the LDAP server if empty; OIDC and HTTP groups require GroupName.
+func (u *UserGroupsClient) Create(ctx context.Context, group *model.UserGroup) (err error) {
+	return u.restClient.Post().
+		Resource("usergroups").
+		Body(group).
+		Do(ctx).
+		Error()
+}
+
+// Update updates the name of a user group.
+func (u *UserGroupsClient) Update(ctx context.Context, id int64, group *model.UserGroup) (err error) {
+	return u.restClient.Put().
+		Resource("usergroups").
+		Name(strconv.FormatInt(id, 10)).
+		Body(group).
+		Do(ctx).
+		Error()
+}
+
+func (u *UserGroupsClient) Delete(ctx context.Context, id int64) (err error) {
+	return u.restClient.Delete().
+		Resource("usergroups").
+		Name(strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Error()
+}
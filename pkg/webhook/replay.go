@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// jobsPageSize is the page size used to list the webhook jobs.
+const jobsPageSize = 100
+
+// ReplayOptions holds the settings of Replay.
+type ReplayOptions struct {
+	// Since and Until bound the creation time of the jobs replayed, unbounded if zero.
+	Since time.Time
+	Until time.Time
+	// PolicyIDs restricts the replay to the given policies, all the policies of the project
+	// are replayed if empty.
+	PolicyIDs []int64
+	// DryRun only reports the jobs that would be replayed.
+	DryRun bool
+	// Timeout bounds each delivery, 30 seconds by default.
+	Timeout time.Duration
+}
+
+// ReplayResult is the replay of a failed job to a target.
+type ReplayResult struct {
+	JobID     int64     `json:"job_id"`
+	PolicyID  int64     `json:"policy_id"`
+	EventType string    `json:"event_type"`
+	Created   time.Time `json:"created"`
+	Target    string    `json:"target"`
+	// Replayed is true if the target accepted the event, Error is set otherwise, unless
+	// the replay was skipped.
+	Replayed bool   `json:"replayed"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReplaySummary reports the replay of the failed jobs of a project.
+type ReplaySummary struct {
+	Project  string          `json:"project"`
+	Jobs     int             `json:"jobs"`
+	Replayed int             `json:"replayed"`
+	Failed   int             `json:"failed"`
+	Skipped  int             `json:"skipped"`
+	Results  []*ReplayResult `json:"results"`
+}
+
+// Replay re-sends the events of the webhook jobs of a project that failed within a time
+// range, e.g. after an outage of a consumer. Harbor has no API to re-trigger a job and its
+// test endpoint sends a synthetic event, so the payload recorded in each job is posted
+// again to the HTTP targets of its policy, with their auth header. Slack targets are
+// skipped, as are the targets of disabled policies. The events are delivered with the
+// proxy settings and the root certificates of the config of the client, the certificates
+// of the targets being verified unless they skip the certificate verification. The client
+// certificate and the certificate pins of the config only apply to Harbor.
+func (w *WebhooksClient) Replay(ctx context.Context, project string, opts *ReplayOptions) (*ReplaySummary, error) {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+	policyIDs := opts.PolicyIDs
+	if len(policyIDs) == 0 {
+		var err error
+		if policyIDs, err = w.policyIDs(ctx, project); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &ReplaySummary{Project: project}
+	for _, policyID := range policyIDs {
+		policy, err := w.GetPolicy(ctx, project, policyID)
+		if err != nil {
+			return nil, fmt.Errorf("get webhook policy %d error: %v", policyID, err)
+		}
+		jobs, err := w.failedJobs(ctx, project, policyID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			summary.Jobs++
+			for _, target := range policy.Targets {
+				result := &ReplayResult{
+					JobID:     job.ID,
+					PolicyID:  policyID,
+					EventType: job.EventType,
+					Created:   job.CreationTime,
+					Target:    target.Address,
+				}
+				switch {
+				case !policy.Enabled || target.Type != model.WebhookNotifyTypeHTTP || opts.DryRun:
+					result.Skipped = true
+					summary.Skipped++
+				default:
+					if err := w.deliver(ctx, target, job.JobDetail, opts.Timeout); err != nil {
+						result.Error = err.Error()
+						summary.Failed++
+					} else {
+						result.Replayed = true
+						summary.Replayed++
+					}
+				}
+				summary.Results = append(summary.Results, result)
+			}
+		}
+	}
+	return summary, nil
+}
+
+// policyIDs lists the IDs of all the policies of a project.
+func (w *WebhooksClient) policyIDs(ctx context.Context, project string) ([]int64, error) {
+	var ids []int64
+	for page := int64(1); ; page++ {
+		policies, err := w.ListPolicies(ctx, project, &model.Query{Page: page, PageSize: jobsPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("list webhook policies of project %s error: %v", project, err)
+		}
+		for _, policy := range *policies {
+			ids = append(ids, policy.ID)
+		}
+		if len(*policies) < jobsPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// failedJobs lists the failed jobs of a policy created within the time range.
+func (w *WebhooksClient) failedJobs(ctx context.Context, project string, policyID int64, opts *ReplayOptions) ([]model.WebhookJob, error) {
+	var failed []model.WebhookJob
+	for page := int64(1); ; page++ {
+		jobs, err := w.ListJobs(ctx, project, &model.WebhookJobQuery{
+			Query:    model.Query{Page: page, PageSize: jobsPageSize},
+			PolicyID: policyID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list jobs of webhook policy %d error: %v", policyID, err)
+		}
+		for _, job := range *jobs {
+			if !job.Failed() ||
+				(!opts.Since.IsZero() && job.CreationTime.Before(opts.Since)) ||
+				(!opts.Until.IsZero() && job.CreationTime.After(opts.Until)) {
+				continue
+			}
+			failed = append(failed, job)
+		}
+		if len(*jobs) < jobsPageSize {
+			return failed, nil
+		}
+	}
+}
+
+// deliver posts the payload of a job to an HTTP target.
+func (w *WebhooksClient) deliver(ctx context.Context, target *model.WebhookTarget, payload string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	client := &http.Client{Transport: w.targets, Timeout: timeout}
+	if target.SkipCertVerify {
+		client.Transport = w.insecureTargets
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Address, bytes.NewBufferString(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if target.AuthHeader != "" {
+		req.Header.Set("Authorization", target.AuthHeader)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("target %s responded with status %d", target.Address, resp.StatusCode)
+	}
+	return nil
+}
+
+// targetTransport returns the transport delivering the events to the targets: transport,
+// for its proxy and timeouts, trusting the root certificates of the config only. The
+// client certificate, the server name and the certificate pins of the config authenticate
+// Harbor and are not used with the targets, whose certificates are verified unless
+// insecure.
+func targetTransport(transport *http.Transport, insecure bool) http.RoundTripper {
+	transport = transport.Clone()
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure,
+	}
+	if transport.TLSClientConfig != nil && !insecure {
+		tlsConfig.RootCAs = transport.TLSClientConfig.RootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
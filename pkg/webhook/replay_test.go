@@ -0,0 +1,190 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// delivery is an event received by a target.
+type delivery struct {
+	authorization string
+	payload       string
+}
+
+// fakeTarget records the events it receives.
+type fakeTarget struct {
+	mu         sync.Mutex
+	deliveries []delivery
+}
+
+func (f *fakeTarget) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, delivery{authorization: req.Header.Get("Authorization"), payload: string(body)})
+}
+
+// fakeWebhooks serves the webhook policies and jobs of the project library, paged.
+type fakeWebhooks struct {
+	policies []model.WebhookPolicy
+	jobs     map[int64][]model.WebhookJob
+}
+
+func (f *fakeWebhooks) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	const prefix = "/api/v2.0/projects/library/webhook/"
+	query := req.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	paged := func(total int) (int, int) {
+		from, to := (page-1)*pageSize, page*pageSize
+		if from > total {
+			from = total
+		}
+		if to > total {
+			to = total
+		}
+		return from, to
+	}
+	switch path := strings.TrimPrefix(req.URL.Path, prefix); {
+	case path == "policies":
+		from, to := paged(len(f.policies))
+		json.NewEncoder(w).Encode(f.policies[from:to])
+	case strings.HasPrefix(path, "policies/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(path, "policies/"), 10, 64)
+		json.NewEncoder(w).Encode(f.policies[id-1])
+	case path == "jobs":
+		id, _ := strconv.ParseInt(query.Get("policy_id"), 10, 64)
+		jobs := f.jobs[id]
+		from, to := paged(len(jobs))
+		json.NewEncoder(w).Encode(jobs[from:to])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	target := &fakeTarget{}
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	job := func(id int64, status string, created time.Time) model.WebhookJob {
+		return model.WebhookJob{ID: id, Status: status, EventType: "PUSH_ARTIFACT", JobDetail: fmt.Sprintf(`{"job":%d}`, id), CreationTime: created}
+	}
+	webhooks := &fakeWebhooks{jobs: map[int64][]model.WebhookJob{}}
+	// more than a page of policies, the first one on both an HTTP and a Slack target, the
+	// second one disabled and the last one, on the second page, without auth header
+	for id := int64(1); id <= jobsPageSize+1; id++ {
+		webhooks.policies = append(webhooks.policies, model.WebhookPolicy{ID: id, Enabled: true})
+	}
+	webhooks.policies[0].Targets = []*model.WebhookTarget{
+		{Type: model.WebhookNotifyTypeHTTP, Address: targetServer.URL, AuthHeader: "Bearer target-token"},
+		{Type: model.WebhookNotifyTypeSlack, Address: "https://hooks.slack.com/services/x"},
+	}
+	webhooks.policies[1].Enabled = false
+	webhooks.policies[1].Targets = []*model.WebhookTarget{{Type: model.WebhookNotifyTypeHTTP, Address: targetServer.URL}}
+	webhooks.policies[jobsPageSize].Targets = []*model.WebhookTarget{{Type: model.WebhookNotifyTypeHTTP, Address: targetServer.URL}}
+	// more than a page of jobs for the first policy, one of the failed jobs being too old
+	webhooks.jobs[1] = append(webhooks.jobs[1], job(1, "Error", since.Add(-time.Hour)), job(2, "Error", since.Add(time.Hour)))
+	for id := int64(3); id <= jobsPageSize; id++ {
+		webhooks.jobs[1] = append(webhooks.jobs[1], job(id, "Success", since.Add(time.Hour)))
+	}
+	webhooks.jobs[1] = append(webhooks.jobs[1], job(jobsPageSize+1, "failed", since.Add(2*time.Hour)))
+	webhooks.jobs[2] = []model.WebhookJob{job(1000, "Error", since.Add(time.Hour))}
+	webhooks.jobs[jobsPageSize+1] = []model.WebhookJob{job(2000, "Error", since.Add(time.Hour))}
+	harbor := httptest.NewServer(webhooks)
+	defer harbor.Close()
+
+	config := rest2.NewDefaultConfig(harbor.URL, "admin", "Harbor12345")
+	// the replay of more than a page of policies isn't throttled
+	config.QPS, config.Burst = 1000, 1000
+	client, err := NewWebhooksClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := client.Replay(context.Background(), "library", &ReplayOptions{Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Jobs != 4 || summary.Replayed != 3 || summary.Skipped != 3 || summary.Failed != 0 {
+		t.Errorf("expected 4 jobs, 3 replayed and 3 skipped deliveries, got %+v", summary)
+	}
+	for _, result := range summary.Results {
+		if result.Skipped != (result.PolicyID == 2 || strings.Contains(result.Target, "slack")) {
+			t.Errorf("unexpected replay %+v", result)
+		}
+	}
+
+	expected := map[string]string{
+		`{"job":2}`:    "Bearer target-token",
+		`{"job":101}`:  "Bearer target-token",
+		`{"job":2000}`: "",
+	}
+	if len(target.deliveries) != len(expected) {
+		t.Fatalf("expected %d deliveries, got %+v", len(expected), target.deliveries)
+	}
+	for _, delivery := range target.deliveries {
+		authorization, ok := expected[delivery.payload]
+		if !ok || delivery.authorization != authorization {
+			t.Errorf("unexpected delivery of %s with the authorization %q", delivery.payload, delivery.authorization)
+		}
+	}
+}
+
+func TestTargetTransport(t *testing.T) {
+	roots := x509.NewCertPool()
+	harbor := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			RootCAs:            roots,
+			ServerName:         "harbor.internal",
+			Certificates:       []tls.Certificate{{}},
+			InsecureSkipVerify: true,
+			GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &tls.Certificate{}, nil
+			},
+			VerifyConnection: func(tls.ConnectionState) error { return nil },
+		},
+	}
+	for _, insecure := range []bool{false, true} {
+		transport := targetTransport(harbor, insecure).(*http.Transport)
+		config := transport.TLSClientConfig
+		if transport.Proxy == nil {
+			t.Error("expected the proxy settings to be kept")
+		}
+		if config.ServerName != "" || len(config.Certificates) > 0 || config.GetClientCertificate != nil || config.VerifyConnection != nil {
+			t.Errorf("expected the client certificate, the server name and the pins of Harbor to be dropped, got %+v", config)
+		}
+		if config.InsecureSkipVerify != insecure || (config.RootCAs == roots) == insecure {
+			t.Errorf("insecure %v: expected the certificates of the targets to be verified with the roots of the config unless insecure, got %+v", insecure, config)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package webhook
+
+import (
+	"context"
+	"github.com/hujianxiong/go-harbor/pkg/model"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"net/http"
+	"strconv"
+)
+
+// WebhooksInterface holds the methods of the webhook APIs of a project.
+type WebhooksInterface interface {
+	GetPolicy(ctx context.Context, project string, id int64) (result *model.WebhookPolicy, err error)
+	ListPolicies(ctx context.Context, project string, query *model.Query) (results *[]model.WebhookPolicy, err error)
+	TestPolicy(ctx context.Context, project string, policy *model.WebhookPolicy) (err error)
+	ListJobs(ctx context.Context, project string, query *model.WebhookJobQuery) (results *[]model.WebhookJob, err error)
+	Replay(ctx context.Context, project string, opts *ReplayOptions) (*ReplaySummary, error)
+}
+
+// WebhooksClient is used to manage the webhook policies of projects and their deliveries.
+type WebhooksClient struct {
+	restClient rest2.Interface
+	// targets and insecureTargets deliver the replayed events with the proxy settings and
+	// the root certificates of the config, insecureTargets to the targets skipping the
+	// certificate verification
+	targets         http.RoundTripper
+	insecureTargets http.RoundTripper
+}
+
+func NewWebhooksClient(restClient *rest2.Config) (*WebhooksClient, error) {
+	client, err := rest2.RESTClientFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := rest2.TransportFor(restClient)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhooksClient{
+		restClient:      client,
+		targets:         targetTransport(transport, false),
+		insecureTargets: targetTransport(transport, true),
+	}, nil
+}
+
+func (w *WebhooksClient) GetPolicy(ctx context.Context, project string, id int64) (result *model.WebhookPolicy, err error) {
+	result = &model.WebhookPolicy{}
+	err = w.restClient.Get().
+		Project(project).
+		Resource("webhook").
+		Suffix("policies", strconv.FormatInt(id, 10)).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+func (w *WebhooksClient) ListPolicies(ctx context.Context, project string, query *model.Query) (results *[]model.WebhookPolicy, err error) {
+	results = &[]model.WebhookPolicy{}
+	err = w.restClient.List().
+		Project(project).
+		Resource("webhook").
+		Suffix("policies").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
+
+// TestPolicy makes Harbor send a test event to the targets of the policy.
+func (w *WebhooksClient) TestPolicy(ctx context.Context, project string, policy *model.WebhookPolicy) (err error) {
+	return w.restClient.Post().
+		Project(project).
+		Resource("webhook").
+		Suffix("policies", "test").
+		Body(policy).
+		Do(ctx).
+		Error()
+}
+
+// ListJobs lists the deliveries of a webhook policy.
+func (w *WebhooksClient) ListJobs(ctx context.Context, project string, query *model.WebhookJobQuery) (results *[]model.WebhookJob, err error) {
+	results = &[]model.WebhookJob{}
+	err = w.restClient.List().
+		Project(project).
+		Resource("webhook").
+		Suffix("jobs").
+		Params(query).
+		Do(ctx).
+		IntoList(results)
+	return
+}
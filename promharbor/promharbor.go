@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+// Package promharbor exports the measurements of the requests of the go-harbor clients as
+// Prometheus metrics. It is a separate module so that the clients do not depend on the
+// Prometheus client.
+package promharbor
+
+import (
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// Collector is a prometheus.Collector of the measurements of the requests, to be set as the
+// rest.Metrics of the clients, e.g. with harbor.WithMetrics:
+//
+//	collector := promharbor.NewCollector()
+//	prometheus.MustRegister(collector)
+//	clientSet, err := harbor.NewClientSet(host, username, password, harbor.WithMetrics(collector))
+//
+// The routes are the route templates of the requests so that the number of series is
+// bounded by the number of endpoints used.
+type Collector struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	throttleWait *prometheus.HistogramVec
+	retries      *prometheus.CounterVec
+}
+
+var _ rest2.Metrics = &Collector{}
+
+// NewCollector creates the collector of the metrics:
+//   - harbor_client_requests_total, by verb and status code, "0" if the request failed
+//     without a response
+//   - harbor_client_request_duration_seconds, by verb and route, retries included
+//   - harbor_client_throttle_wait_seconds, by verb and route
+//   - harbor_client_retries_total, by verb and route
+func NewCollector() *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "harbor_client",
+			Name:      "requests_total",
+			Help:      "Number of requests to Harbor by verb and status code.",
+		}, []string{"verb", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "harbor_client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the requests to Harbor, retries included, by verb and route.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"verb", "route"}),
+		throttleWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "harbor_client",
+			Name:      "throttle_wait_seconds",
+			Help:      "Time the requests to Harbor waited for the client rate limiter, by verb and route.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"verb", "route"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "harbor_client",
+			Name:      "retries_total",
+			Help:      "Number of retries of the requests to Harbor by verb and route.",
+		}, []string{"verb", "route"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.throttleWait.Describe(ch)
+	c.retries.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.throttleWait.Collect(ch)
+	c.retries.Collect(ch)
+}
+
+// ObserveRequest implements rest.Metrics.
+func (c *Collector) ObserveRequest(verb, route string, statusCode int, duration time.Duration) {
+	c.requests.WithLabelValues(verb, strconv.Itoa(statusCode)).Inc()
+	c.duration.WithLabelValues(verb, route).Observe(duration.Seconds())
+}
+
+// ObserveThrottle implements rest.Metrics.
+func (c *Collector) ObserveThrottle(verb, route string, wait time.Duration) {
+	c.throttleWait.WithLabelValues(verb, route).Observe(wait.Seconds())
+}
+
+// ObserveRetry implements rest.Metrics.
+func (c *Collector) ObserveRetry(verb, route string) {
+	c.retries.WithLabelValues(verb, route).Inc()
+}
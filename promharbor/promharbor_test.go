@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The go-harbor Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+*/
+
+package promharbor
+
+import (
+	"context"
+	rest2 "github.com/hujianxiong/go-harbor/pkg/rest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	collector := NewCollector()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	config := rest2.NewDefaultConfig(server.URL, "admin", "Harbor12345")
+	config.RetryPolicy = &rest2.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	config.Metrics = collector
+	client, err := rest2.RESTClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Get().Resource("projects").Name("library").Do(context.Background()).Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP harbor_client_requests_total Number of requests to Harbor by verb and status code.
+# TYPE harbor_client_requests_total counter
+harbor_client_requests_total{code="200",verb="GET"} 1
+# HELP harbor_client_retries_total Number of retries of the requests to Harbor by verb and route.
+# TYPE harbor_client_retries_total counter
+harbor_client_retries_total{route="/api/v2.0/projects/{name}",verb="GET"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "harbor_client_requests_total", "harbor_client_retries_total"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(collector, "harbor_client_request_duration_seconds", "harbor_client_throttle_wait_seconds"); count != 2 {
+		t.Errorf("expected a duration and a throttle wait series, got %d", count)
+	}
+}